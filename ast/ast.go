@@ -3,6 +3,8 @@ package ast
 import (
 	"go-interpreter-lexer/token"
 	"bytes"
+	"math/big"
+	"sort"
 	"strings"
 )
 
@@ -55,6 +57,8 @@ func (p *Program) String() string{
 type LetStatement struct{
 	Token token.Token
 	Name *Identifier
+	// Type is the annotation after the name, nil when there is none.
+	Type TypeExpr
 	Value Expression
 }
 
@@ -67,14 +71,35 @@ func (ls LetStatement) String() string{
 	var out bytes.Buffer
 
 	out.WriteString(ls.TokenLiteral() +" ")
-	out.WriteString(ls.Name.String())
+	out.WriteString(str(ls.Name))
+	if ls.Type != nil {
+		out.WriteString(": " + ls.Type.String())
+	}
 	out.WriteString(" = ")
-	out.WriteString(ls.Value.String())
+	out.WriteString(str(ls.Value))
     out.WriteString(";")
 	return out.String()
 }
 
 
+/*
+	FunctionStatement declares a named function, fn name(params) { body }. The name is bound in the
+	enclosing scope before any of its statements run so functions can call each other in any order.
+*/
+type FunctionStatement struct{
+	Token token.Token
+	Name *Identifier
+	Function *FunctionLiteral
+}
+
+func (fs *FunctionStatement) statementNode(){}
+func (fs *FunctionStatement) TokenLiteral() string{
+	return fs.Token.Literal
+}
+func (fs *FunctionStatement) String() string{
+	return fs.TokenLiteral() + " " + str(fs.Name) + strings.TrimPrefix(str(fs.Function), fs.TokenLiteral())
+}
+
 /**
 	the return statement is one where we have the following syntax each time
  	return <expression>
@@ -107,6 +132,13 @@ func (rs *ReturnStatement) String() string{
 type Identifier struct {
 	Token token.Token
 	Value string
+	/*
+		Depth is filled in by the resolver, it is the number of function scopes between the identifier and the
+		scope its binding is declared in and -1 for builtins and identifiers the resolver could not find. It is
+		0 on an identifier that was never resolved. The evaluator only uses a Depth above 0 as a hint, so
+		identifiers that were never resolved are looked up as before.
+	*/
+	Depth int
 }
 
 func (i *Identifier) expressionNode(){}
@@ -130,6 +162,35 @@ func (i *IntegerLiteral) String() string{
 	return i.Token.Literal
 }
 
+// BigIntLiteral is an integer written with an n suffix, 123n, it has no size limit.
+type BigIntLiteral struct{
+	Token token.Token
+	Value *big.Int
+}
+
+func(b *BigIntLiteral) expressionNode(){}
+func(b *BigIntLiteral) TokenLiteral() string{
+	return b.Token.Literal
+}
+func (b *BigIntLiteral) String() string{
+	return b.Token.Literal
+}
+
+// DecimalLiteral is a number with a fraction, 12.50 is 1250 with a scale of 2.
+type DecimalLiteral struct{
+	Token token.Token
+	Unscaled *big.Int
+	Scale int32
+}
+
+func(d *DecimalLiteral) expressionNode(){}
+func(d *DecimalLiteral) TokenLiteral() string{
+	return d.Token.Literal
+}
+func (d *DecimalLiteral) String() string{
+	return d.Token.Literal
+}
+
 /**
 	Expression statements are statements that represent a single or combination of expressions.
 */
@@ -165,9 +226,9 @@ func (ie *InfixExpression) String() string{
 	var out bytes.Buffer
 
 	out.WriteString("(")
-	out.WriteString(ie.Left.String())
+	out.WriteString(str(ie.Left))
 	out.WriteString(" "+ie.Operator+" ")
-	out.WriteString(ie.Right.String())
+	out.WriteString(str(ie.Right))
 	out.WriteString(")")
 
 	return out.String()
@@ -189,7 +250,7 @@ func (pe *PrefixExpression) String() string{
 
 	out.WriteString("(")
 	out.WriteString(pe.Operator)
-	out.WriteString(pe.Right.String())
+	out.WriteString(str(pe.Right))
 	out.WriteString(")")
 
 	return out.String()
@@ -224,8 +285,8 @@ func (ife *IfExpression) String() string{
 	var out bytes.Buffer
 
 	out.WriteString("if")
-	out.WriteString(ife.Condition.String())
-	out.WriteString(ife.Consequence.String())
+	out.WriteString(str(ife.Condition))
+	out.WriteString(str(ife.Consequence))
 	out.WriteString(" ")
 	if ife.Alternative != nil {
 		out.WriteString("else ")
@@ -237,6 +298,8 @@ func (ife *IfExpression) String() string{
 type BlockStatement struct{
 	Token token.Token
 	Statements []Statement
+	// End is the position of the closing brace, or of the end of the input if the block is not closed.
+	End token.Position
 }
 
 func(bs *BlockStatement) statementNode(){}
@@ -253,7 +316,17 @@ func(bs *BlockStatement) String() string{
 
 type FunctionLiteral struct{
 	Token token.Token
+	// Name is the name of a declared function or of the let the literal is bound by, empty otherwise.
+	Name string
 	Parameters []*Identifier
+	// ParameterTypes holds the annotation of each parameter, an entry is nil when the parameter has none.
+	ParameterTypes []TypeExpr
+	// Defaults holds the default value of each parameter, an entry is nil when the parameter has none.
+	Defaults []Expression
+	// Rest is true when the last parameter collects the remaining arguments into an array, fn(a, ...rest).
+	Rest bool
+	// ResultType is the annotation after the parameter list, nil when there is none.
+	ResultType TypeExpr
 	Body *BlockStatement
 }
 
@@ -264,18 +337,46 @@ func (fl *FunctionLiteral) TokenLiteral() string{
 func (fl *FunctionLiteral) String() string{
 	var out bytes.Buffer
 	params := []string{}
-	for _, p := range fl.Parameters {
-		params = append(params,p.String())
+	for i, p := range fl.Parameters {
+		param := str(p)
+		if fl.Rest && i == len(fl.Parameters)-1 {
+			param = "..." + param
+		}
+		if i < len(fl.ParameterTypes) && fl.ParameterTypes[i] != nil {
+			param += ": " + fl.ParameterTypes[i].String()
+		}
+		if i < len(fl.Defaults) && fl.Defaults[i] != nil {
+			param += " = " + fl.Defaults[i].String()
+		}
+		params = append(params,param)
 	}
 	out.WriteString(fl.TokenLiteral())
 	out.WriteString("(")
 	out.WriteString(strings.Join(params,","))
-	out.WriteString(") ")
-	out.WriteString(fl.Body.String())
+	out.WriteString(")")
+	if fl.ResultType != nil {
+		out.WriteString(": " + fl.ResultType.String())
+	}
+	out.WriteString(" ")
+	out.WriteString(str(fl.Body))
 
 	return out.String()
 }
 
+// SpreadExpression passes the elements of an array as separate arguments of a call, f(...args).
+type SpreadExpression struct{
+	Token token.Token
+	Value Expression
+}
+
+func (se *SpreadExpression) expressionNode(){}
+func (se *SpreadExpression) TokenLiteral() string{
+	return se.Token.Literal
+}
+func (se *SpreadExpression) String() string{
+	return "..." + str(se.Value)
+}
+
 type CallExpression struct{
 	Token token.Token
 	Function Expression
@@ -292,10 +393,10 @@ func (ce *CallExpression) String() string{
 
 	args := []string{}
 	for _, a := range ce.Arguments{
-		args = append(args,a.String())
+		args = append(args,str(a))
 	}
 
-	out.WriteString(ce.Function.String())
+	out.WriteString(str(ce.Function))
 	out.WriteString("(")
 	out.WriteString(strings.Join(args,", "))
 	out.WriteString(")")
@@ -328,7 +429,7 @@ func (al *ArrayLiteral) String() string{
 	var out bytes.Buffer
 	elements := []string{}
 	for _, el := range al.Elements{
-		elements = append(elements,el.String())
+		elements = append(elements,str(el))
 	}
 	out.WriteString("[")
 	out.WriteString(strings.Join(elements,","))
@@ -350,14 +451,32 @@ func (ie *IndexExpression) String() string{
 	var out bytes.Buffer
 
 	out.WriteString("(")
-	out.WriteString(ie.Left.String())
+	out.WriteString(str(ie.Left))
 	out.WriteString("[")
-	out.WriteString(ie.Index.String())
+	out.WriteString(str(ie.Index))
 	out.WriteString("])")
 
 	return out.String()
 }
 
+/*
+	MemberExpression looks up a member by name, strings.split or config.name. Property is not resolved like
+	other identifiers, it names a member of a module or a string key of a hash.
+*/
+type MemberExpression struct{
+	Token token.Token
+	Object Expression
+	Property *Identifier
+}
+
+func (me *MemberExpression) expressionNode(){}
+func (me *MemberExpression) TokenLiteral() string{
+	return me.Token.Literal
+}
+func (me *MemberExpression) String() string{
+	return "(" + str(me.Object) + "." + str(me.Property) + ")"
+}
+
 type HashLiteral struct{
 	Token token.Token
 	Pairs map[Expression]Expression
@@ -371,11 +490,30 @@ func (hl *HashLiteral) String() string{
 	var out bytes.Buffer
 	pairs := []string{}
 
-	for key, value := range hl.Pairs{
-		pairs = append(pairs, key.String()+":"+value.String())
+	for _, key := range hl.Keys(){
+		pairs = append(pairs, str(key)+":"+str(hl.Pairs[key]))
 	}
 	out.WriteString("{")
 	out.WriteString(strings.Join(pairs,", "))
 	out.WriteString("}")
 	return out.String()
-}
\ No newline at end of file
+}
+
+// Keys returns the keys of the pairs in the order they are written in the source.
+func (hl *HashLiteral) Keys() []Expression{
+	keys := make([]Expression, 0, len(hl.Pairs))
+	for key := range hl.Pairs{
+		keys = append(keys, key)
+	}
+	sort.SliceStable(keys, func(i, j int) bool{
+		return Pos(keys[i]).Before(Pos(keys[j]))
+	})
+	return keys
+}
+// str is the source of a node that may be missing because of a syntax error, a missing node prints as nothing.
+func str(n Node) string{
+	if isNil(n){
+		return ""
+	}
+	return n.String()
+}
@@ -0,0 +1,74 @@
+package ast
+
+import "go-interpreter-lexer/token"
+
+/*
+	Pos returns the position of the first token of node. An infix, call or index expression starts with its
+	left operand rather than with its own token. The position is invalid for nodes the parser failed to build.
+*/
+func Pos(node Node) token.Position {
+	if isNil(node) {
+		return token.Position{}
+	}
+	switch n := node.(type) {
+	case *Program:
+		if len(n.Statements) > 0 {
+			return Pos(n.Statements[0])
+		}
+		return token.Position{}
+	case *ExpressionStatement:
+		if n.Expression != nil {
+			return Pos(n.Expression)
+		}
+		return n.Token.Pos
+	case *InfixExpression:
+		return Pos(n.Left)
+	case *CallExpression:
+		return Pos(n.Function)
+	case *IndexExpression:
+		return Pos(n.Left)
+	case *MemberExpression:
+		return Pos(n.Object)
+	case *LetStatement:
+		return n.Token.Pos
+	case *FunctionStatement:
+		return n.Token.Pos
+	case *ReturnStatement:
+		return n.Token.Pos
+	case *BlockStatement:
+		return n.Token.Pos
+	case *Identifier:
+		return n.Token.Pos
+	case *IntegerLiteral:
+		return n.Token.Pos
+	case *BigIntLiteral:
+		return n.Token.Pos
+	case *DecimalLiteral:
+		return n.Token.Pos
+	case *StringLiteral:
+		return n.Token.Pos
+	case *Boolean:
+		return n.Token.Pos
+	case *PrefixExpression:
+		return n.Token.Pos
+	case *IfExpression:
+		return n.Token.Pos
+	case *FunctionLiteral:
+		return n.Token.Pos
+	case *ArrayLiteral:
+		return n.Token.Pos
+	case *HashLiteral:
+		return n.Token.Pos
+	case *SpreadExpression:
+		return n.Token.Pos
+	case *NamedType:
+		return n.Token.Pos
+	case *ArrayType:
+		return n.Token.Pos
+	case *HashType:
+		return n.Token.Pos
+	case *FunctionType:
+		return n.Token.Pos
+	}
+	return token.Position{}
+}
@@ -0,0 +1,88 @@
+package ast
+
+import (
+	"bytes"
+	"go-interpreter-lexer/token"
+	"strings"
+)
+
+/*
+	TypeExpr is an optional type annotation on a let binding, a function parameter or a function result.
+	The evaluator ignores annotations, they are only read by the type checker.
+
+	let x: int = 5;
+	let f = fn(a: string, b: [int]): bool { ... };
+*/
+type TypeExpr interface{
+	Node
+	typeNode()
+}
+
+// NamedType is a type referred to by name, int, string, bool, null or any.
+type NamedType struct{
+	Token token.Token
+	Name string
+}
+
+func (nt *NamedType) typeNode(){}
+func (nt *NamedType) TokenLiteral() string{
+	return nt.Token.Literal
+}
+func (nt *NamedType) String() string{
+	return nt.Name
+}
+
+// ArrayType is written [element].
+type ArrayType struct{
+	Token token.Token
+	Element TypeExpr
+}
+
+func (at *ArrayType) typeNode(){}
+func (at *ArrayType) TokenLiteral() string{
+	return at.Token.Literal
+}
+func (at *ArrayType) String() string{
+	return "[" + at.Element.String() + "]"
+}
+
+// HashType is written {key: value}.
+type HashType struct{
+	Token token.Token
+	Key TypeExpr
+	Value TypeExpr
+}
+
+func (ht *HashType) typeNode(){}
+func (ht *HashType) TokenLiteral() string{
+	return ht.Token.Literal
+}
+func (ht *HashType) String() string{
+	return "{" + ht.Key.String() + ": " + ht.Value.String() + "}"
+}
+
+// FunctionType is written fn(param, ...): result, the result can be left out.
+type FunctionType struct{
+	Token token.Token
+	Parameters []TypeExpr
+	Result TypeExpr
+}
+
+func (ft *FunctionType) typeNode(){}
+func (ft *FunctionType) TokenLiteral() string{
+	return ft.Token.Literal
+}
+func (ft *FunctionType) String() string{
+	var out bytes.Buffer
+	params := []string{}
+	for _, p := range ft.Parameters{
+		params = append(params, p.String())
+	}
+	out.WriteString("fn(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(")")
+	if ft.Result != nil{
+		out.WriteString(": " + ft.Result.String())
+	}
+	return out.String()
+}
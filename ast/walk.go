@@ -0,0 +1,99 @@
+package ast
+
+import "reflect"
+
+/*
+	Inspect traverses the tree rooted at node depth first, calling f for every node in source order
+	(the pairs of a hash literal in the order they are written). If f
+	returns false the children of that node are not visited. Children that are missing because the parser
+	failed to build them are skipped.
+*/
+func Inspect(node Node, f func(Node) bool) {
+	if isNil(node) || !f(node) {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		for _, s := range n.Statements {
+			Inspect(s, f)
+		}
+	case *LetStatement:
+		Inspect(n.Name, f)
+		Inspect(n.Type, f)
+		Inspect(n.Value, f)
+	case *FunctionStatement:
+		Inspect(n.Name, f)
+		Inspect(n.Function, f)
+	case *ReturnStatement:
+		Inspect(n.ReturnValue, f)
+	case *ExpressionStatement:
+		Inspect(n.Expression, f)
+	case *BlockStatement:
+		for _, s := range n.Statements {
+			Inspect(s, f)
+		}
+	case *PrefixExpression:
+		Inspect(n.Right, f)
+	case *InfixExpression:
+		Inspect(n.Left, f)
+		Inspect(n.Right, f)
+	case *IfExpression:
+		Inspect(n.Condition, f)
+		Inspect(n.Consequence, f)
+		Inspect(n.Alternative, f)
+	case *FunctionLiteral:
+		for i, p := range n.Parameters {
+			Inspect(p, f)
+			if i < len(n.ParameterTypes) {
+				Inspect(n.ParameterTypes[i], f)
+			}
+			if i < len(n.Defaults) {
+				Inspect(n.Defaults[i], f)
+			}
+		}
+		Inspect(n.ResultType, f)
+		Inspect(n.Body, f)
+	case *SpreadExpression:
+		Inspect(n.Value, f)
+	case *CallExpression:
+		Inspect(n.Function, f)
+		for _, a := range n.Arguments {
+			Inspect(a, f)
+		}
+	case *ArrayLiteral:
+		for _, e := range n.Elements {
+			Inspect(e, f)
+		}
+	case *IndexExpression:
+		Inspect(n.Left, f)
+		Inspect(n.Index, f)
+	case *MemberExpression:
+		Inspect(n.Object, f)
+		Inspect(n.Property, f)
+	case *HashLiteral:
+		for _, k := range n.Keys() {
+			Inspect(k, f)
+			Inspect(n.Pairs[k], f)
+		}
+	case *ArrayType:
+		Inspect(n.Element, f)
+	case *HashType:
+		Inspect(n.Key, f)
+		Inspect(n.Value, f)
+	case *FunctionType:
+		for _, p := range n.Parameters {
+			Inspect(p, f)
+		}
+		Inspect(n.Result, f)
+	}
+}
+
+// isNil reports whether node is nil, including typed nil pointers stored in an interface.
+func isNil(node Node) bool {
+	if node == nil {
+		return true
+	}
+	v := reflect.ValueOf(node)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"go-interpreter-lexer/astviz"
+)
+
+// astCommand prints the ast of a source file, monkey ast --format=dot|tree|tokens file.mk
+func astCommand(args []string) int {
+	fs := flag.NewFlagSet("ast", flag.ContinueOnError)
+	format := fs.String("format", "tree", "output format: dot, tree or tokens")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: monkey ast [--format=dot|tree|tokens] file.mk")
+		return 2
+	}
+
+	if *format == "tokens" {
+		src, err := ioutil.ReadFile(fs.Arg(0))
+		if err == nil {
+			err = astviz.Tokens(os.Stdout, string(src))
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	program, diags, err := parseFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(diags) != 0 {
+		printDiagnostics(os.Stderr, fs.Arg(0), diags)
+		return 1
+	}
+
+	switch *format {
+	case "dot":
+		err = astviz.Dot(os.Stdout, program)
+	case "tree":
+		err = astviz.Tree(os.Stdout, program)
+	default:
+		fmt.Fprintf(os.Stderr, "monkey ast: unknown format %q\n", *format)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
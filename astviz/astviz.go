@@ -0,0 +1,225 @@
+/*
+	Package astviz renders the ast produced by the parser in forms that are easier to read than the
+	nested parenthesised strings returned by String(). Dot writes a Graphviz graph, Tree writes an indented
+	text dump of the same structure and Tokens lists the tokens the lexer produced for some input.
+*/
+package astviz
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/token"
+)
+
+// child is an edge in the tree, the label names the field of the parent the node is stored in.
+type child struct {
+	label string
+	node  ast.Node
+}
+
+// Dot writes node and everything below it as a Graphviz digraph.
+func Dot(w io.Writer, node ast.Node) error {
+	bw := bufio.NewWriter(w)
+	d := &dotWriter{w: bw}
+
+	fmt.Fprintln(bw, "digraph ast {")
+	fmt.Fprintln(bw, "\tnode [shape=box, fontname=\"monospace\"];")
+	d.write(node)
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+type dotWriter struct {
+	w    *bufio.Writer
+	next int
+}
+
+func (d *dotWriter) write(node ast.Node) string {
+	id := fmt.Sprintf("n%d", d.next)
+	d.next++
+
+	fmt.Fprintf(d.w, "\t%s [label=%s];\n", id, dotQuote(label(node)))
+	for _, c := range children(node) {
+		childID := d.write(c.node)
+		fmt.Fprintf(d.w, "\t%s -> %s [label=%s];\n", id, childID, dotQuote(c.label))
+	}
+	return id
+}
+
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// Tree writes node and everything below it as an indented tree, one node per line.
+func Tree(w io.Writer, node ast.Node) error {
+	bw := bufio.NewWriter(w)
+	writeTree(bw, "", node, 0)
+	return bw.Flush()
+}
+
+func writeTree(w *bufio.Writer, edge string, node ast.Node, depth int) {
+	w.WriteString(strings.Repeat("  ", depth))
+	if edge != "" {
+		w.WriteString(edge + ": ")
+	}
+	w.WriteString(strings.Replace(label(node), "\n", " ", -1))
+	w.WriteString("\n")
+
+	for _, c := range children(node) {
+		writeTree(w, c.label, c.node, depth+1)
+	}
+}
+
+// Tokens writes every token the lexer produces for input, one per line, up to and including EOF.
+func Tokens(w io.Writer, input string) error {
+	bw := bufio.NewWriter(w)
+	l := lexer.New(input)
+	for {
+		t := l.NextToken()
+		fmt.Fprintf(bw, "%-10s %q\n", t.Type, t.Literal)
+		if t.Type == token.EOF {
+			break
+		}
+	}
+	return bw.Flush()
+}
+
+// label is the text shown for a node, the type of node followed by the value it holds if any.
+func label(node ast.Node) string {
+	if isNil(node) {
+		return "<nil>"
+	}
+	name := strings.TrimPrefix(fmt.Sprintf("%T", node), "*ast.")
+	switch n := node.(type) {
+	case *ast.Identifier:
+		return name + "\n" + n.Value
+	case *ast.IntegerLiteral, *ast.BigIntLiteral, *ast.DecimalLiteral:
+		return name + "\n" + n.TokenLiteral()
+	case *ast.StringLiteral:
+		return name + "\n" + fmt.Sprintf("%q", n.Value)
+	case *ast.Boolean:
+		return name + "\n" + n.Token.Literal
+	case *ast.PrefixExpression:
+		return name + "\n" + n.Operator
+	case *ast.InfixExpression:
+		return name + "\n" + n.Operator
+	case *ast.FunctionLiteral:
+		if n.Rest {
+			return name + "\n..." + n.Parameters[len(n.Parameters)-1].Value
+		}
+	case *ast.NamedType:
+		return name + "\n" + n.Name
+	}
+	return name
+}
+
+func children(node ast.Node) []child {
+	if isNil(node) {
+		return nil
+	}
+	var out []child
+	add := func(label string, n ast.Node) {
+		out = append(out, child{label, n})
+	}
+
+	switch n := node.(type) {
+	case *ast.Program:
+		for i, s := range n.Statements {
+			add(fmt.Sprintf("%d", i), s)
+		}
+	case *ast.LetStatement:
+		add("name", n.Name)
+		if n.Type != nil {
+			add("type", n.Type)
+		}
+		add("value", n.Value)
+	case *ast.FunctionStatement:
+		add("name", n.Name)
+		add("function", n.Function)
+	case *ast.ReturnStatement:
+		add("value", n.ReturnValue)
+	case *ast.ExpressionStatement:
+		add("expression", n.Expression)
+	case *ast.BlockStatement:
+		for i, s := range n.Statements {
+			add(fmt.Sprintf("%d", i), s)
+		}
+	case *ast.PrefixExpression:
+		add("right", n.Right)
+	case *ast.InfixExpression:
+		add("left", n.Left)
+		add("right", n.Right)
+	case *ast.IfExpression:
+		add("condition", n.Condition)
+		add("consequence", n.Consequence)
+		if n.Alternative != nil {
+			add("alternative", n.Alternative)
+		}
+	case *ast.FunctionLiteral:
+		for i, p := range n.Parameters {
+			add(fmt.Sprintf("param %d", i), p)
+			if i < len(n.ParameterTypes) && n.ParameterTypes[i] != nil {
+				add(fmt.Sprintf("type %d", i), n.ParameterTypes[i])
+			}
+			if i < len(n.Defaults) && n.Defaults[i] != nil {
+				add(fmt.Sprintf("default %d", i), n.Defaults[i])
+			}
+		}
+		if n.ResultType != nil {
+			add("result", n.ResultType)
+		}
+		add("body", n.Body)
+	case *ast.SpreadExpression:
+		add("value", n.Value)
+	case *ast.CallExpression:
+		add("function", n.Function)
+		for i, a := range n.Arguments {
+			add(fmt.Sprintf("arg %d", i), a)
+		}
+	case *ast.ArrayLiteral:
+		for i, e := range n.Elements {
+			add(fmt.Sprintf("%d", i), e)
+		}
+	case *ast.IndexExpression:
+		add("left", n.Left)
+		add("index", n.Index)
+	case *ast.MemberExpression:
+		add("object", n.Object)
+		add("property", n.Property)
+	case *ast.HashLiteral:
+		// pairs are shown in the order they were written.
+		for _, k := range n.Keys() {
+			add("key", k)
+			add("value", n.Pairs[k])
+		}
+	case *ast.ArrayType:
+		add("element", n.Element)
+	case *ast.HashType:
+		add("key", n.Key)
+		add("value", n.Value)
+	case *ast.FunctionType:
+		for i, p := range n.Parameters {
+			add(fmt.Sprintf("param %d", i), p)
+		}
+		if n.Result != nil {
+			add("result", n.Result)
+		}
+	}
+	return out
+}
+
+// isNil reports whether node is nil, including typed nil pointers left behind by failed parse functions.
+func isNil(node ast.Node) bool {
+	if node == nil {
+		return true
+	}
+	v := reflect.ValueOf(node)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
@@ -0,0 +1,106 @@
+package astviz
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/parser"
+)
+
+func TestTree(t *testing.T) {
+	p := parser.New(lexer.New("let x = 1 + 2 * 3;"))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+
+	var out bytes.Buffer
+	if err := Tree(&out, program); err != nil {
+		t.Fatalf("Tree returned error %v", err)
+	}
+	expected := `Program
+  0: LetStatement
+    name: Identifier x
+    value: InfixExpression +
+      left: IntegerLiteral 1
+      right: InfixExpression *
+        left: IntegerLiteral 2
+        right: IntegerLiteral 3
+`
+	if out.String() != expected {
+		t.Errorf("wrong tree. expected=\n%s\ngot=\n%s", expected, out.String())
+	}
+}
+
+func TestTreeHashInSourceOrder(t *testing.T) {
+	p := parser.New(lexer.New(`{"b": 1, "a": 2}`))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+
+	var out bytes.Buffer
+	if err := Tree(&out, program); err != nil {
+		t.Fatalf("Tree returned error %v", err)
+	}
+	expected := `Program
+  0: ExpressionStatement
+    expression: HashLiteral
+      key: StringLiteral "b"
+      value: IntegerLiteral 1
+      key: StringLiteral "a"
+      value: IntegerLiteral 2
+`
+	if out.String() != expected {
+		t.Errorf("wrong tree. expected=\n%s\ngot=\n%s", expected, out.String())
+	}
+}
+
+func TestDot(t *testing.T) {
+	p := parser.New(lexer.New(`if (x < 1) { "a" } else { fn(y) { y } }`))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+
+	var out bytes.Buffer
+	if err := Dot(&out, program); err != nil {
+		t.Fatalf("Dot returned error %v", err)
+	}
+	dot := out.String()
+
+	expected := []string{
+		"digraph ast {",
+		`n0 [label="Program"];`,
+		`[label="IfExpression"];`,
+		`[label="InfixExpression\n<"];`,
+		`[label="StringLiteral\n\"a\""];`,
+		`[label="condition"];`,
+		`[label="alternative"];`,
+		`[label="param 0"];`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(dot, e) {
+			t.Errorf("dot output does not contain %q got=\n%s", e, dot)
+		}
+	}
+	if !strings.HasSuffix(dot, "}\n") {
+		t.Errorf("dot output is not terminated got=\n%s", dot)
+	}
+}
+
+func TestTokens(t *testing.T) {
+	var out bytes.Buffer
+	if err := Tokens(&out, "let a = 5;"); err != nil {
+		t.Fatalf("Tokens returned error %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("expected 6 tokens got %d:\n%s", len(lines), out.String())
+	}
+	if !strings.HasPrefix(lines[0], "LET") || !strings.HasPrefix(lines[5], "EOF") {
+		t.Errorf("unexpected tokens:\n%s", out.String())
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"go-interpreter-lexer/diagnostic"
+	"go-interpreter-lexer/resolver"
+	"go-interpreter-lexer/testrunner"
+	"go-interpreter-lexer/typecheck"
+)
+
+// checkCommand type checks source files without running them, monkey check file.mk...
+func checkCommand(args []string) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: monkey check file.mk...")
+		return 2
+	}
+
+	code := 0
+	for _, path := range fs.Args() {
+		program, diags, err := parseFile(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = 1
+			continue
+		}
+		if len(diags) == 0 {
+			r := resolver.New(testrunner.BuiltinNames(path))
+			r.Resolve(program)
+			c := typecheck.New()
+			c.Check(program)
+			diags = append(r.Diagnostics(), c.Diagnostics()...)
+			diagnostic.Sort(diags)
+		}
+		printDiagnostics(os.Stdout, path, diags)
+		if diagnostic.HasErrors(diags) {
+			code = 1
+		}
+	}
+	return code
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/diagnostic"
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/parser"
+)
+
+// parseFile reads and parses a source file, the diagnostics are the parser errors if there were any.
+func parseFile(path string) (*ast.Program, []diagnostic.Diagnostic, error) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	program, diags := parseSource(string(src))
+	return program, diags, nil
+}
+
+// parseSource parses source text read by the caller, the diagnostics are the parser errors if there were any.
+func parseSource(src string) (*ast.Program, []diagnostic.Diagnostic) {
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+	return program, p.Diagnostics()
+}
+
+// printDiagnostics writes diagnostics prefixed with the file they belong to, path:line:col: severity: message
+func printDiagnostics(w io.Writer, path string, diags []diagnostic.Diagnostic) {
+	for _, d := range diags {
+		fmt.Fprintf(w, "%s:%s\n", path, d)
+	}
+}
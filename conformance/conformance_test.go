@@ -0,0 +1,173 @@
+package conformance
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-interpreter-lexer/evaluator"
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/object"
+	"go-interpreter-lexer/parser"
+	"go-interpreter-lexer/resolver"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files with the output of the evaluator")
+
+// clock is the time programs read from time.now, frozen so golden files do not change from run to run.
+var clock = time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+
+// outcome is what running a program produced, each field is a section of a golden file.
+type outcome struct {
+	stdout string
+	result string
+	errors string
+}
+
+var sections = []string{"stdout", "result", "error"}
+
+func (o outcome) field(section string) string {
+	switch section {
+	case "stdout":
+		return o.stdout
+	case "result":
+		return o.result
+	}
+	return o.errors
+}
+
+func (o outcome) String() string {
+	var b strings.Builder
+	for _, s := range sections {
+		fmt.Fprintf(&b, "-- %s --\n%s", s, o.field(s))
+	}
+	return b.String()
+}
+
+// parseGolden splits a golden file into its sections.
+func parseGolden(text string) (outcome, error) {
+	var o outcome
+	fields := map[string]*string{"stdout": &o.stdout, "result": &o.result, "error": &o.errors}
+	var current *string
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "-- ") && strings.HasSuffix(line, " --\n") {
+			name := strings.TrimSuffix(strings.TrimPrefix(line, "-- "), " --\n")
+			f, ok := fields[name]
+			if !ok {
+				return o, fmt.Errorf("unknown section %q", name)
+			}
+			current = f
+			continue
+		}
+		if current == nil {
+			return o, fmt.Errorf("text before the first section: %q", line)
+		}
+		*current += line
+	}
+	return o, nil
+}
+
+// backend is a way of running programs, every backend must produce the golden outcome.
+type backend struct {
+	name string
+	run  func(t *testing.T, src string) outcome
+}
+
+var backends = []backend{
+	{"evaluator", evaluate},
+}
+
+// evaluate runs a program the way monkey run does: parse, resolve and evaluate.
+func evaluate(t *testing.T, src string) outcome {
+	var o outcome
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+	if diags := p.Diagnostics(); len(diags) != 0 {
+		for _, d := range diags {
+			o.errors += d.String() + "\n"
+		}
+		return o
+	}
+	r := resolver.New(evaluator.BuiltinNames())
+	r.Resolve(program)
+	for _, d := range r.Diagnostics() {
+		o.errors += d.String() + "\n"
+	}
+	if o.errors != "" {
+		return o
+	}
+
+	var stdout bytes.Buffer
+	env := evaluator.NewEnvironment(evaluator.Config{
+		IO:    evaluator.IO{Stdout: &stdout},
+		Clock: func() time.Time { return clock },
+	})
+	result := evaluator.Eval(program, env)
+	o.stdout = stdout.String()
+	switch result := result.(type) {
+	case nil:
+	case *object.Error:
+		o.errors = result.Inspect() + "\n"
+	default:
+		o.result = result.Inspect() + "\n"
+	}
+	return o
+}
+
+func TestGolden(t *testing.T) {
+	programs, err := filepath.Glob(filepath.Join("testdata", "*.mk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(programs) == 0 {
+		t.Fatal("no programs in testdata")
+	}
+	for _, path := range programs {
+		path := path
+		t.Run(strings.TrimSuffix(filepath.Base(path), ".mk"), func(t *testing.T) {
+			// every program has an environment of its own, so they can run at the same time.
+			t.Parallel()
+			golden(t, path)
+		})
+	}
+}
+
+// golden compares the outcome of the program at path with its golden file, or rewrites the file with -update.
+func golden(t *testing.T, path string) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goldenPath := strings.TrimSuffix(path, ".mk") + ".golden"
+	if *update {
+		got := evaluate(t, string(src))
+		if err := ioutil.WriteFile(goldenPath, []byte(got.String()), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	text, err := ioutil.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("%s, run go test -update to create it", err)
+	}
+	want, err := parseGolden(string(text))
+	if err != nil {
+		t.Fatalf("%s: %s", goldenPath, err)
+	}
+
+	for _, b := range backends {
+		got := b.run(t, string(src))
+		for _, s := range sections {
+			if got.field(s) != want.field(s) {
+				t.Errorf("%s: wrong %s\n got %q\nwant %q", b.name, s, got.field(s), want.field(s))
+			}
+		}
+	}
+}
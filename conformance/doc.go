@@ -0,0 +1,9 @@
+/*
+	Package conformance is the specification of the language as a set of programs. Every testdata/*.mk
+	program has a .golden file next to it with what the program prints, the value of its last statement and
+	its error output. The tests run each program through every backend and compare the three with the
+	golden file, go test ./conformance -update rewrites the golden files from the evaluator.
+
+	A new backend is added to the backends list of the test, it has to agree with the same golden files.
+*/
+package conformance
@@ -0,0 +1,5 @@
+let a = 5 * (2 + 3) - 10 / 2;
+puts(a);
+puts(-a + 50);
+puts(7 / 2);
+2 * 2 * 2 * 2 * 2 - 2;
@@ -0,0 +1,3 @@
+let add = fn(a, b) { a + b };
+puts(add(1, 2));
+add(1)
@@ -0,0 +1,7 @@
+let xs = [1, 2 * 2, 3 + 3];
+puts(xs[1]);
+puts(len(xs));
+puts(first(xs));
+puts(last(xs));
+puts(xs[10]);
+[xs[0], xs[2], [first([])]];
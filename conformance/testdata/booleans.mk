@@ -0,0 +1,6 @@
+puts(1 < 2);
+puts(1 > 2 == false);
+puts(!true);
+puts(!!5);
+puts(true != false);
+(1 < 2) == true;
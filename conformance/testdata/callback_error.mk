@@ -0,0 +1,3 @@
+let double = fn(x) { x * 2 };
+puts(map([1, 2], double));
+map([1, "two", 3], double)
@@ -0,0 +1,8 @@
+let adder = fn(x) { fn(y) { x + y } };
+let addTwo = adder(2);
+puts(addTwo(3));
+let counter = fn(start) {
+  let next = fn() { start + 1 };
+  next;
+};
+counter(41)();
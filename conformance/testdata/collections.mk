@@ -0,0 +1,8 @@
+let words = ["pear", "apple", "fig", "apple"];
+puts(sort(uniq(words)));
+puts(map(words, len));
+puts(filter(range(10), fn(n) { n * 2 > 10 }));
+puts(reduce(range(1, 6), fn(acc, n) { acc * n }));
+puts(zip(range(3), reverse(range(3))));
+puts(flatten([[1, 2], [3], []]));
+sort([3, 1, 2], fn(a, b) { a > b })
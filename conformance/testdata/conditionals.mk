@@ -0,0 +1,4 @@
+let max = fn(a, b) { if (a > b) { a } else { b } };
+puts(max(3, 9));
+puts(if (false) { 1 });
+if (1) { "truthy" } else { "falsy" };
@@ -0,0 +1,19 @@
+puts(isEven(10));
+puts(isOdd(7));
+
+fn isEven(n) {
+  if (n == 0) { true } else { isOdd(n - 1) }
+}
+
+fn isOdd(n) {
+  if (n == 0) { false } else { isEven(n - 1) }
+}
+
+fn counter(start) {
+  let next = fn() { step(start) };
+  fn step(n) { n + 1 }
+  next
+}
+
+puts(counter(41)());
+isEven
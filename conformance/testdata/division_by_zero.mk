@@ -0,0 +1,3 @@
+let average = fn(xs) { reduce(xs, fn(a, b) { a + b }, 0) / len(xs) };
+puts(average([2, 4]));
+average([])
@@ -0,0 +1,13 @@
+let prices = [19.99, 5.25, 0.10];
+let subtotal = reduce(prices, fn(a, b) { a + b });
+puts(subtotal);
+let tax = decimal(subtotal * 0.0825, 2);
+puts(tax);
+puts(subtotal + tax);
+puts(100.00 / 3);
+puts(0.1 + 0.2 == 0.3);
+let fact = fn(n) { if (n < 2) { 1 } else { n * fact(n - 1) } };
+puts(fact(30));
+puts(2n * 9223372036854775807);
+let ledger = {1: "one", 2.5: "two and a half"};
+[ledger[1.00], ledger[2.50]]
@@ -0,0 +1,9 @@
+let config = {"name": "monkey", "debug": false, "level": 3};
+puts(config);
+puts(keys(config));
+puts(values(config));
+puts(entries({"x": 1}));
+puts(has(config, "debug"));
+let quiet = delete(config, "debug");
+puts(quiet);
+merge(quiet, {"level": 4, "color": true})
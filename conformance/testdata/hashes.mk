@@ -0,0 +1,8 @@
+let key = "two";
+let h = {"one": 1};
+let n = {key: 2};
+puts(h["one"]);
+puts(n["tw" + "o"]);
+puts(h["missing"]);
+let b = {true: "yes"};
+b[1 < 2];
@@ -0,0 +1,6 @@
+let apply = fn(f, x) { f(x) };
+let twice = fn(f) { fn(x) { f(f(x)) } };
+let inc = fn(x) { x + 1 };
+puts(apply(inc, 1));
+puts(twice(inc)(5));
+twice(twice(inc))(0);
@@ -0,0 +1,5 @@
+io.print("loading");
+io.print(".", ".", ".");
+puts(" done");
+puts(io.readLine());
+io.readFile("config.json")
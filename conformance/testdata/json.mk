@@ -0,0 +1,9 @@
+let order = {"id": 7, "items": [{"sku": "A1", "price": 12.50}, {"sku": "B2", "price": 3n}], "paid": false};
+let text = json.stringify(order);
+puts(text);
+let back = json.parse(text);
+puts(back.items[0].price * 2);
+puts(json.stringify(back) == text);
+puts(json.stringify({"tags": ["x", "y"], "empty": {}}, 2));
+puts(json.parse("[1e3, 0.25, null]"));
+json.parse("[1, 2,]")
@@ -0,0 +1,8 @@
+let scores = [72, 95, 88, 61];
+puts(math.max(...scores) - math.min(...scores));
+puts(math.round(decimal(reduce(scores, fn(a, b) { a + b })) / len(scores)));
+puts([17 % 5, -17 % 5, math.div(-17, 5), math.floor(-3.4)]);
+puts(math.pow(2, 62));
+puts(math.sqrt(1000000));
+puts(math.clamp(120, 0, 100));
+9223372036854775807 + 1
@@ -0,0 +1,4 @@
+let inner = fn() { -true };
+let outer = fn() { inner() + 1 };
+puts("start");
+outer();
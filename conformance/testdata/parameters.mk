@@ -0,0 +1,14 @@
+let greet = fn(name, greeting = "hello") { greeting + " " + name };
+puts(greet("monkey"));
+puts(greet("monkey", "hi"));
+
+let sum = fn(...xs) {
+  let loop = fn(i, acc) { if (i == len(xs)) { acc } else { loop(i + 1, acc + xs[i]) } };
+  loop(0, 0)
+};
+puts(sum());
+puts(sum(1, 2, 3));
+puts(sum(...[4, 5], 6));
+
+let head = fn(first, ...rest) { [first, rest] };
+head(1, 2, 3)
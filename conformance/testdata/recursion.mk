@@ -0,0 +1,15 @@
+let fib = fn(n) {
+  if (n < 2) {
+    return n;
+  }
+  fib(n - 1) + fib(n - 2);
+};
+puts(fib(10));
+let sum = fn(arr, i) {
+  if (i == len(arr)) {
+    return 0;
+  }
+  arr[i] + sum(arr, i + 1);
+};
+puts(sum([1, 2, 3, 4], 0));
+fib(15);
@@ -0,0 +1,9 @@
+let email = regex.compile("^[\w.]+@[\w]+\.[a-z]{2,}$");
+puts(email);
+puts(filter(["ann@mail.com", "not an email", "bob.smith@web.io"], fn(s) { regex.match(email, s) }));
+let date = regex.captures("(?P<year>\d{4})-(?P<month>\d{2})-(?P<day>\d{2})", "due 2024-05-01");
+puts(date.day + "/" + date.month + "/" + date.year);
+puts(regex.findAll("\d+", "3 apples, 12 pears and 7 plums"));
+puts(regex.replace("\b(\w)(\w*)", "hello monkey world", fn(w) { strings.upper(w[0]) + strings.substring(w, 1) }));
+puts(regex.split("\s*;\s*", "a ; b;c"));
+regex.compile("a(")
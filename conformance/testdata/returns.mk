@@ -0,0 +1,12 @@
+let early = fn(x) {
+  if (x > 10) {
+    if (x > 100) {
+      return "huge";
+    }
+    return "big";
+  }
+  "small";
+};
+puts(early(5));
+puts(early(50));
+early(500);
@@ -0,0 +1,4 @@
+let greet = fn(name) { "Hello, " + name + "!" };
+puts(greet("Monkey"));
+puts(len("four"));
+greet("world");
@@ -0,0 +1,8 @@
+let words = strings.split("the quick brown fox", " ");
+puts(strings.join(map(words, strings.upper), "-"));
+puts(strings.format("%s has %d words", "the line", len(words)));
+puts(strings.padLeft("42", 5, "0"));
+let user = {"name": "  monkey  "};
+puts(strings.trim(user.name) + "!");
+puts(sort(words, fn(a, b) { a > b }));
+"monkey"[0] == "m"
@@ -0,0 +1,11 @@
+let opened = time.parse("DateTime", "2024-04-29 17:15:00", "Europe/Paris");
+puts(opened);
+puts(time.since(opened));
+let due = opened + time.duration("72h");
+puts(time.format(due, "Mon 2 Jan 15:04 MST"));
+puts([due < time.now(), time.in(due, "UTC") == due]);
+puts(time.since(opened) / time.duration("1h"));
+let shifts = map(range(3), fn(i) { time.format(time.now() + time.duration("8h") * i, "15:04") });
+puts(shifts);
+puts(time.parts(time.fromUnix(time.unix(time.now()))).weekday);
+time.now() - 1
@@ -0,0 +1,3 @@
+puts("before");
+let x = 5 + true;
+puts("after");
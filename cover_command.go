@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"go-interpreter-lexer/coverage"
+)
+
+/*
+	coverCommand reports the coverage written by monkey run --coverage,
+	monkey cover [--format=text|html|lcov] [-o file] coverage.json
+	The HTML report reads the source files again, they are found by the paths the coverage was recorded with.
+*/
+func coverCommand(args []string) int {
+	fs := flag.NewFlagSet("cover", flag.ContinueOnError)
+	format := fs.String("format", "text", "report format: text, html or lcov")
+	output := fs.String("o", "", "write the report to `file` instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: monkey cover [--format=text|html|lcov] [-o file] coverage.json")
+		return 2
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	profiles, err := coverage.Read(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", fs.Arg(0), err)
+		return 1
+	}
+
+	var write func(w io.Writer) error
+	switch *format {
+	case "text":
+		write = func(w io.Writer) error { return coverage.WriteText(w, profiles) }
+	case "lcov":
+		write = func(w io.Writer) error { return coverage.WriteLCOV(w, profiles) }
+	case "html":
+		write = func(w io.Writer) error {
+			return coverage.WriteHTML(w, profiles, func(file string) (string, error) {
+				src, err := ioutil.ReadFile(file)
+				return string(src), err
+			})
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "monkey cover: unknown format %q\n", *format)
+		return 2
+	}
+
+	if *output == "" {
+		if err := write(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+	out, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := write(out); err != nil {
+		out.Close()
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := out.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,190 @@
+/*
+	Package coverage records which statements, branches and functions of a program run. A Collector is
+	the hook of an evaluation, see evaluator.Config, the profiles it produces are saved with Write and turned into text,
+	HTML or LCOV reports once the program has finished.
+
+	Every if expression has two branches, the consequence and the alternative. An if without an else still
+	has the alternative branch, taken whenever the condition is false.
+*/
+package coverage
+
+import (
+	"encoding/json"
+	"io"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/object"
+	"go-interpreter-lexer/token"
+)
+
+// Profile is the coverage of one file.
+type Profile struct {
+	File       string
+	Statements []Statement
+	Branches   []Branch
+	Functions  []Function
+}
+
+type Statement struct {
+	Pos  token.Position
+	Hits int
+}
+
+// Branch is one of the two ways through an if expression, Index is 0 for the consequence and 1 for the alternative.
+type Branch struct {
+	// Pos is the position of the if expression, both its branches have the same one.
+	Pos   token.Position
+	Index int
+	Hits  int
+}
+
+// Function is a function literal, Hits is the number of times its body ran.
+type Function struct {
+	Name string
+	Pos  token.Position
+	Hits int
+}
+
+// ifCount counts the evaluations of an if expression and of its consequence.
+type ifCount struct {
+	profile      *Profile
+	branch       int
+	evaluations  int
+	consequences int
+	alternatives int
+	hasElse      bool
+}
+
+type Collector struct {
+	profiles   []*Profile
+	statements map[ast.Node]*Statement
+	functions  map[ast.Node]*Function
+	ifs        map[*ast.IfExpression]*ifCount
+	blocks     map[*ast.BlockStatement]*ifCount
+	// consequence tells the blocks of the ifs apart from their alternatives.
+	consequence map[*ast.BlockStatement]bool
+}
+
+func NewCollector() *Collector {
+	return &Collector{
+		statements:  make(map[ast.Node]*Statement),
+		functions:   make(map[ast.Node]*Function),
+		ifs:         make(map[*ast.IfExpression]*ifCount),
+		blocks:      make(map[*ast.BlockStatement]*ifCount),
+		consequence: make(map[*ast.BlockStatement]bool),
+	}
+}
+
+/*
+	Add registers the statements, ifs and functions of a program so they are reported even if they never
+	run. file is the name the program is reported under.
+*/
+func (c *Collector) Add(file string, program *ast.Program) {
+	p := &Profile{File: file}
+	c.profiles = append(c.profiles, p)
+
+	var stmts []ast.Node
+	var ifs []*ast.IfExpression
+	var fns []*ast.FunctionLiteral
+	ast.Inspect(program, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.LetStatement, *ast.FunctionStatement, *ast.ReturnStatement, *ast.ExpressionStatement:
+			if ast.Pos(n).IsValid() {
+				stmts = append(stmts, n)
+			}
+		case *ast.IfExpression:
+			ifs = append(ifs, n)
+		case *ast.FunctionLiteral:
+			fns = append(fns, n)
+		}
+		return true
+	})
+
+	// the slices are filled before pointers into them are handed out.
+	p.Statements = make([]Statement, len(stmts))
+	for i, s := range stmts {
+		p.Statements[i] = Statement{Pos: ast.Pos(s)}
+		c.statements[s] = &p.Statements[i]
+	}
+	p.Functions = make([]Function, len(fns))
+	for i, fl := range fns {
+		name := fl.Name
+		if name == "" {
+			name = "fn@" + fl.Token.Pos.String()
+		}
+		p.Functions[i] = Function{Name: name, Pos: fl.Token.Pos}
+		if fl.Body != nil {
+			c.functions[fl.Body] = &p.Functions[i]
+		}
+	}
+	for _, ie := range ifs {
+		count := &ifCount{profile: p, branch: len(p.Branches), hasElse: ie.Alternative != nil}
+		p.Branches = append(p.Branches, Branch{Pos: ie.Token.Pos, Index: 0}, Branch{Pos: ie.Token.Pos, Index: 1})
+		c.ifs[ie] = count
+		if ie.Consequence != nil {
+			c.blocks[ie.Consequence] = count
+			c.consequence[ie.Consequence] = true
+		}
+		if ie.Alternative != nil {
+			c.blocks[ie.Alternative] = count
+		}
+	}
+}
+
+// Profiles returns the coverage of every program added, in the order they were added.
+func (c *Collector) Profiles() []*Profile {
+	for _, count := range c.ifs {
+		taken := count.alternatives
+		if !count.hasElse {
+			taken = count.evaluations - count.consequences
+		}
+		count.profile.Branches[count.branch].Hits = count.consequences
+		count.profile.Branches[count.branch+1].Hits = taken
+	}
+	return c.profiles
+}
+
+// Eval implements evaluator.Hook.
+func (c *Collector) Eval(node ast.Node, env *object.Environment) {
+	switch n := node.(type) {
+	case *ast.IfExpression:
+		if count, ok := c.ifs[n]; ok {
+			count.evaluations++
+		}
+	case *ast.BlockStatement:
+		if fn, ok := c.functions[n]; ok {
+			fn.Hits++
+		}
+		if count, ok := c.blocks[n]; ok {
+			if c.consequence[n] {
+				count.consequences++
+			} else {
+				count.alternatives++
+			}
+		}
+	default:
+		if s, ok := c.statements[node]; ok {
+			s.Hits++
+		}
+	}
+}
+
+func (c *Collector) Call(call *ast.CallExpression, fn object.Object, args []object.Object) {}
+
+func (c *Collector) Return(call *ast.CallExpression, fn object.Object, result object.Object) {}
+
+// Write saves profiles as JSON for a later report.
+func Write(w io.Writer, profiles []*Profile) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(profiles)
+}
+
+// Read loads profiles saved with Write.
+func Read(r io.Reader) ([]*Profile, error) {
+	var profiles []*Profile
+	if err := json.NewDecoder(r).Decode(&profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
@@ -0,0 +1,146 @@
+package coverage
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go-interpreter-lexer/evaluator"
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/parser"
+)
+
+const input = `let sign = fn(n) {
+  if (n < 0) { return -1; }
+  if (n == 0) { 0 } else { 1 }
+};
+let unused = fn() { 2 };
+sign(5);
+sign(7);`
+
+func collect(t *testing.T) *Profile {
+	c := NewCollector()
+	program := parser.New(lexer.New(input)).ParseProgram()
+	c.Add("sign.mk", program)
+	evaluator.Eval(program, evaluator.NewEnvironment(evaluator.Config{Hook: c}))
+	profiles := c.Profiles()
+	if len(profiles) != 1 {
+		t.Fatalf("wrong number of profiles %d", len(profiles))
+	}
+	return profiles[0]
+}
+
+func TestCollector(t *testing.T) {
+	p := collect(t)
+	var stmts []string
+	for _, s := range p.Statements {
+		stmts = append(stmts, s.Pos.String()+"="+strconv.Itoa(s.Hits))
+	}
+	want := "1:1=1 2:3=2 2:16=0 3:3=2 3:17=0 3:28=2 5:1=1 5:21=0 6:1=1 7:1=1"
+	if got := strings.Join(stmts, " "); got != want {
+		t.Errorf("wrong statements\n got %s\nwant %s", got, want)
+	}
+
+	var branches []string
+	for _, b := range p.Branches {
+		branches = append(branches, b.Pos.String()+"/"+strconv.Itoa(b.Index)+"="+strconv.Itoa(b.Hits))
+	}
+	want = "2:3/0=0 2:3/1=2 3:3/0=0 3:3/1=2"
+	if got := strings.Join(branches, " "); got != want {
+		t.Errorf("wrong branches\n got %s\nwant %s", got, want)
+	}
+
+	var funcs []string
+	for _, f := range p.Functions {
+		funcs = append(funcs, f.Name+"="+strconv.Itoa(f.Hits))
+	}
+	want = "sign=2 unused=0"
+	if got := strings.Join(funcs, " "); got != want {
+		t.Errorf("wrong functions\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	p := collect(t)
+	var buf bytes.Buffer
+	if err := Write(&buf, []*Profile{p}); err != nil {
+		t.Fatal(err)
+	}
+	profiles, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 1 || profiles[0].Summary() != p.Summary() || profiles[0].File != "sign.mk" {
+		t.Errorf("profile changed on the way through JSON: %+v", profiles)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	WriteText(&buf, []*Profile{collect(t)})
+	want := `sign.mk: statements 70.0% (7/10), branches 50.0% (2/4), functions 50.0% (1/2)
+  not run: 2-3, 5
+  not taken: consequence of if at 2:3
+  not taken: consequence of if at 3:3
+`
+	if buf.String() != want {
+		t.Errorf("wrong report\n got %q\nwant %q", buf.String(), want)
+	}
+}
+
+func TestWriteLCOV(t *testing.T) {
+	var buf bytes.Buffer
+	WriteLCOV(&buf, []*Profile{collect(t)})
+	want := `TN:
+SF:sign.mk
+FN:1,sign
+FN:5,unused
+FNDA:2,sign
+FNDA:0,unused
+FNF:2
+FNH:1
+BRDA:2,0,0,0
+BRDA:2,0,1,2
+BRDA:3,1,0,0
+BRDA:3,1,1,2
+BRF:4
+BRH:2
+DA:1,1
+DA:2,2
+DA:3,2
+DA:5,1
+DA:6,1
+DA:7,1
+LF:6
+LH:6
+end_of_record
+`
+	if buf.String() != want {
+		t.Errorf("wrong lcov\n got %s\nwant %s", buf.String(), want)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteHTML(&buf, []*Profile{collect(t)}, func(file string) (string, error) {
+		if file != "sign.mk" {
+			return "", errors.New("no such file")
+		}
+		return input, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`<tr class="partial"><td class="n">2</td><td class="h">2</td><td>  if (n &lt; 0) { return -1; }</td></tr>`,
+		`<tr class="run"><td class="n">6</td><td class="h">1</td><td>sign(5);</td></tr>`,
+		`<tr class=""><td class="n">4</td><td class="h"></td><td>};</td></tr>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("html is missing %s\n%s", want, out)
+		}
+	}
+}
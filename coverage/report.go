@@ -0,0 +1,266 @@
+package coverage
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Summary counts what a profile covers, a count of 0 in Total means there is nothing of that kind.
+type Summary struct {
+	Statements, StatementsHit int
+	Branches, BranchesHit     int
+	Functions, FunctionsHit   int
+}
+
+func (p *Profile) Summary() Summary {
+	var s Summary
+	for _, st := range p.Statements {
+		s.Statements++
+		if st.Hits > 0 {
+			s.StatementsHit++
+		}
+	}
+	for _, b := range p.Branches {
+		s.Branches++
+		if b.Hits > 0 {
+			s.BranchesHit++
+		}
+	}
+	for _, f := range p.Functions {
+		s.Functions++
+		if f.Hits > 0 {
+			s.FunctionsHit++
+		}
+	}
+	return s
+}
+
+// lineHits is the hits of every statement on each line.
+func (p *Profile) lineHits() map[int][]int {
+	lines := make(map[int][]int)
+	for _, st := range p.Statements {
+		lines[st.Pos.Line] = append(lines[st.Pos.Line], st.Hits)
+	}
+	return lines
+}
+
+// UncoveredLines returns the lines with a statement that never ran, in order.
+func (p *Profile) UncoveredLines() []int {
+	var lines []int
+	for line, hits := range p.lineHits() {
+		for _, h := range hits {
+			if h == 0 {
+				lines = append(lines, line)
+				break
+			}
+		}
+	}
+	sort.Ints(lines)
+	return lines
+}
+
+func percent(hit, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(100*float64(hit)/float64(total), 'f', 1, 64) + "%"
+}
+
+// ranges joins consecutive lines, 1 2 3 7 becomes 1-3, 7.
+func ranges(lines []int) string {
+	var parts []string
+	for i := 0; i < len(lines); {
+		j := i
+		for j+1 < len(lines) && lines[j+1] == lines[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(lines[i]))
+		} else {
+			parts = append(parts, strconv.Itoa(lines[i])+"-"+strconv.Itoa(lines[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
+
+/*
+	WriteText writes a line per file with the share of statements, branches and functions that ran, and the
+	lines whose statements did not.
+*/
+func WriteText(w io.Writer, profiles []*Profile) error {
+	var b strings.Builder
+	for _, p := range profiles {
+		s := p.Summary()
+		fmt.Fprintf(&b, "%s: statements %s (%d/%d), branches %s (%d/%d), functions %s (%d/%d)\n", p.File,
+			percent(s.StatementsHit, s.Statements), s.StatementsHit, s.Statements,
+			percent(s.BranchesHit, s.Branches), s.BranchesHit, s.Branches,
+			percent(s.FunctionsHit, s.Functions), s.FunctionsHit, s.Functions)
+		if lines := p.UncoveredLines(); len(lines) != 0 {
+			fmt.Fprintf(&b, "  not run: %s\n", ranges(lines))
+		}
+		for _, br := range p.Branches {
+			if br.Hits == 0 {
+				which := "consequence"
+				if br.Index == 1 {
+					which = "alternative"
+				}
+				fmt.Fprintf(&b, "  not taken: %s of if at %s\n", which, br.Pos)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+/*
+	WriteLCOV writes profiles in the LCOV tracefile format read by genhtml and most coverage services. A
+	line counts as run as often as the statement on it that ran most.
+*/
+func WriteLCOV(w io.Writer, profiles []*Profile) error {
+	var b strings.Builder
+	for _, p := range profiles {
+		s := p.Summary()
+		b.WriteString("TN:\nSF:" + p.File + "\n")
+		for _, f := range p.Functions {
+			fmt.Fprintf(&b, "FN:%d,%s\n", f.Pos.Line, f.Name)
+		}
+		for _, f := range p.Functions {
+			fmt.Fprintf(&b, "FNDA:%d,%s\n", f.Hits, f.Name)
+		}
+		fmt.Fprintf(&b, "FNF:%d\nFNH:%d\n", s.Functions, s.FunctionsHit)
+
+		// an if that never ran has no branch data, lcov writes - for it.
+		for i := 0; i+1 < len(p.Branches); i += 2 {
+			then, alt := p.Branches[i], p.Branches[i+1]
+			for _, br := range []Branch{then, alt} {
+				taken := strconv.Itoa(br.Hits)
+				if then.Hits == 0 && alt.Hits == 0 {
+					taken = "-"
+				}
+				fmt.Fprintf(&b, "BRDA:%d,%d,%d,%s\n", br.Pos.Line, i/2, br.Index, taken)
+			}
+		}
+		fmt.Fprintf(&b, "BRF:%d\nBRH:%d\n", s.Branches, s.BranchesHit)
+
+		lines := p.lineHits()
+		numbers := make([]int, 0, len(lines))
+		for line := range lines {
+			numbers = append(numbers, line)
+		}
+		sort.Ints(numbers)
+		hit := 0
+		for _, line := range numbers {
+			max := 0
+			for _, h := range lines[line] {
+				if h > max {
+					max = h
+				}
+			}
+			if max > 0 {
+				hit++
+			}
+			fmt.Fprintf(&b, "DA:%d,%d\n", line, max)
+		}
+		fmt.Fprintf(&b, "LF:%d\nLH:%d\nend_of_record\n", len(numbers), hit)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type htmlLine struct {
+	Number int
+	Hits   string
+	Class  string
+	Text   string
+}
+
+type htmlFile struct {
+	Name    string
+	Summary string
+	Lines   []htmlLine
+}
+
+var htmlReport = template.Must(template.New("coverage").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Coverage</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; font-family: monospace; }
+td { padding: 0 8px; white-space: pre; }
+td.n, td.h { text-align: right; color: #888; }
+tr.run { background: #dfd; }
+tr.notrun { background: #fdd; }
+tr.partial { background: #ffd; }
+</style>
+</head>
+<body>
+{{range .}}<h2>{{.Name}}</h2>
+<p>{{.Summary}}</p>
+<table>
+{{range .Lines}}<tr class="{{.Class}}"><td class="n">{{.Number}}</td><td class="h">{{.Hits}}</td><td>{{.Text}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`))
+
+/*
+	WriteHTML writes the source of every profile with its lines coloured: green when all statements on the
+	line ran, red when none did and yellow when only some did or an if on the line took only one branch.
+	source returns the text of a file, a file it cannot read is listed without its source.
+*/
+func WriteHTML(w io.Writer, profiles []*Profile, source func(file string) (string, error)) error {
+	var files []htmlFile
+	for _, p := range profiles {
+		s := p.Summary()
+		f := htmlFile{
+			Name: p.File,
+			Summary: fmt.Sprintf("statements %s, branches %s, functions %s",
+				percent(s.StatementsHit, s.Statements), percent(s.BranchesHit, s.Branches), percent(s.FunctionsHit, s.Functions)),
+		}
+		src, err := source(p.File)
+		if err != nil {
+			files = append(files, f)
+			continue
+		}
+		hits := p.lineHits()
+		partial := make(map[int]bool)
+		for _, br := range p.Branches {
+			if br.Hits == 0 {
+				partial[br.Pos.Line] = true
+			}
+		}
+		for i, text := range strings.Split(strings.TrimSuffix(src, "\n"), "\n") {
+			line := htmlLine{Number: i + 1, Text: text}
+			if hs, ok := hits[i+1]; ok {
+				run, max := 0, 0
+				for _, h := range hs {
+					if h > 0 {
+						run++
+					}
+					if h > max {
+						max = h
+					}
+				}
+				line.Hits = strconv.Itoa(max)
+				switch {
+				case run == 0:
+					line.Class = "notrun"
+				case run < len(hs) || partial[i+1]:
+					line.Class = "partial"
+				default:
+					line.Class = "run"
+				}
+			}
+			f.Lines = append(f.Lines, line)
+		}
+		files = append(files, f)
+	}
+	return htmlReport.Execute(w, files)
+}
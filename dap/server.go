@@ -0,0 +1,484 @@
+/*
+	Package dap is a debug adapter for monkey speaking the debug adapter protocol over a pair of streams,
+	usually stdin and stdout of the monkey dap command. It launches a single program under the debugger
+	package and answers the requests editors send while it is stopped: the stack, the scopes of each frame
+	and their variables, arrays and hashes expanding one level at a time, and the value of expressions.
+
+	A program has a single thread, its id is always 1.
+*/
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/debugger"
+	"go-interpreter-lexer/diagnostic"
+	"go-interpreter-lexer/evaluator"
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/object"
+	"go-interpreter-lexer/parser"
+	"go-interpreter-lexer/resolver"
+	"go-interpreter-lexer/token"
+)
+
+const threadID = 1
+
+type Server struct {
+	in *bufio.Reader
+	// mu guards out, seq and stopped, events are sent from the goroutine running the program.
+	mu      sync.Mutex
+	out     io.Writer
+	seq     int
+	stopped bool
+
+	lines1, columns1 bool
+
+	path        string
+	program     *ast.Program
+	breakpoints map[string][]int
+	configured  bool
+	running     bool
+
+	// Config is what the launched program is evaluated with, what it writes to IO.Stdout is sent to the
+	// client instead.
+	Config evaluator.Config
+
+	d      *debugger.Debugger
+	resume chan debugger.Action
+	// quit is closed when the client disconnects, a program stopped or about to stop is abandoned.
+	quit chan struct{}
+	done chan struct{}
+	// refs are the environments, arrays and hashes handed out as variables references while the program
+	// is stopped, reference n is refs[n-1].
+	refs []interface{}
+}
+
+func NewServer(in io.Reader, out io.Writer) *Server {
+	s := &Server{
+		in:          bufio.NewReader(in),
+		out:         out,
+		lines1:      true,
+		columns1:    true,
+		breakpoints: make(map[string][]int),
+		resume:      make(chan debugger.Action),
+		quit:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	s.d = debugger.New(s.onStop)
+	return s
+}
+
+// Run serves requests until the client disconnects or the input ends.
+func (s *Server) Run() error {
+	for {
+		body, err := readMessage(s.in)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil || req.Type != "request" {
+			continue
+		}
+
+		result, err := s.handle(req)
+		if err := s.respond(req, result, err); err != nil {
+			return err
+		}
+
+		// what has to happen after the response has been sent.
+		switch req.Command {
+		case "initialize":
+			s.event("initialized", nil)
+		case "launch", "configurationDone":
+			if s.program != nil && s.configured && !s.running {
+				s.start()
+			}
+		case "continue":
+			s.continueWith(debugger.Continue)
+		case "next":
+			s.continueWith(debugger.StepOver)
+		case "stepIn":
+			s.continueWith(debugger.StepIn)
+		case "stepOut":
+			s.continueWith(debugger.StepOut)
+		case "disconnect", "terminate":
+			// the program is abandoned whether it is stopped or running, Run returns once it has ended.
+			if s.running {
+				close(s.quit)
+				s.d.Quit()
+				<-s.done
+			}
+			return nil
+		}
+	}
+}
+
+// Output sends text the program printed to the client.
+func (s *Server) Output(text string) {
+	s.event("output", map[string]string{"category": "stdout", "output": text})
+}
+
+// Stdout returns a writer that sends what is written to it to the client with Output, the standard output of the program.
+// Each write is sent before the program carries on, so the output comes before the events that follow it.
+func (s *Server) Stdout() io.Writer {
+	return outputWriter{s}
+}
+
+type outputWriter struct{ s *Server }
+
+func (w outputWriter) Write(p []byte) (int, error) {
+	w.s.Output(string(p))
+	return len(p), nil
+}
+
+func (s *Server) respond(req request, body interface{}, err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	resp := response{Seq: s.seq, Type: "response", RequestSeq: req.Seq, Command: req.Command, Success: err == nil, Body: body}
+	if err != nil {
+		resp.Message = err.Error()
+		resp.Body = nil
+	}
+	return writeMessage(s.out, resp)
+}
+
+func (s *Server) event(name string, body interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	writeMessage(s.out, event{Seq: s.seq, Type: "event", Event: name, Body: body})
+}
+
+func (s *Server) isStopped() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stopped
+}
+
+var errRunning = errors.New("the program is not stopped")
+
+func (s *Server) handle(req request) (interface{}, error) {
+	switch req.Command {
+	case "initialize":
+		var args InitializeArguments
+		json.Unmarshal(req.Arguments, &args)
+		if args.LinesStartAt1 != nil {
+			s.lines1 = *args.LinesStartAt1
+		}
+		if args.ColumnsStartAt1 != nil {
+			s.columns1 = *args.ColumnsStartAt1
+		}
+		return map[string]interface{}{
+			"supportsConfigurationDoneRequest": true,
+			"supportsEvaluateForHovers":        true,
+		}, nil
+	case "launch":
+		var args LaunchArguments
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return nil, s.launch(args)
+	case "configurationDone":
+		s.configured = true
+		return nil, nil
+	case "setBreakpoints":
+		var args SetBreakpointsArguments
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return s.setBreakpoints(args), nil
+	case "threads":
+		return map[string]interface{}{"threads": []Thread{{ID: threadID, Name: "main"}}}, nil
+	case "stackTrace":
+		return s.stackTrace()
+	case "scopes":
+		var args ScopesArguments
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return s.scopes(args.FrameID)
+	case "variables":
+		var args VariablesArguments
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return s.variables(args.VariablesReference)
+	case "evaluate":
+		var args EvaluateArguments
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return s.evaluate(args)
+	case "continue", "next", "stepIn", "stepOut":
+		if !s.isStopped() {
+			return nil, errRunning
+		}
+		if req.Command == "continue" {
+			return map[string]bool{"allThreadsContinued": true}, nil
+		}
+		return nil, nil
+	case "disconnect", "terminate":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unsupported request %s", req.Command)
+}
+
+func (s *Server) launch(args LaunchArguments) error {
+	if args.Program == "" {
+		return errors.New("launch needs the path of the program")
+	}
+	path, err := filepath.Abs(args.Program)
+	if err != nil {
+		return err
+	}
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	diags := p.Diagnostics()
+	if len(diags) == 0 {
+		r := resolver.New(evaluator.BuiltinNames())
+		r.Resolve(program)
+		diags = r.Diagnostics()
+	}
+	if diagnostic.HasErrors(diags) {
+		var msgs []string
+		for _, d := range diags {
+			msgs = append(msgs, fmt.Sprintf("%s:%s", args.Program, d))
+		}
+		return errors.New(strings.Join(msgs, "\n"))
+	}
+
+	s.path, s.program = path, program
+	if args.StopOnEntry {
+		s.d.StopOnEntry()
+	}
+	s.applyBreakpoints()
+	return nil
+}
+
+// setBreakpoints records the breakpoints of a source, they only take effect for the launched program.
+func (s *Server) setBreakpoints(args SetBreakpointsArguments) interface{} {
+	path, _ := filepath.Abs(args.Source.Path)
+	lines := []int{}
+	result := []Breakpoint{}
+	for _, bp := range args.Breakpoints {
+		lines = append(lines, bp.Line)
+		result = append(result, Breakpoint{Verified: s.path == "" || s.path == path, Line: bp.Line})
+	}
+	s.breakpoints[path] = lines
+	s.applyBreakpoints()
+	return map[string]interface{}{"breakpoints": result}
+}
+
+func (s *Server) applyBreakpoints() {
+	if s.path == "" {
+		return
+	}
+	s.d.ClearBreakpoints()
+	for _, line := range s.breakpoints[s.path] {
+		s.d.SetBreakpoint(s.line(line))
+	}
+}
+
+// line converts a line number from the client to the position lines of the source.
+func (s *Server) line(n int) int {
+	if s.lines1 {
+		return n
+	}
+	return n + 1
+}
+
+func (s *Server) clientPos(pos token.Position) (line, column int) {
+	line, column = pos.Line, pos.Column
+	if !s.lines1 {
+		line--
+	}
+	if !s.columns1 {
+		column--
+	}
+	return line, column
+}
+
+func (s *Server) start() {
+	s.running = true
+	go func() {
+		defer close(s.done)
+		cfg := s.Config
+		cfg.IO.Stdout = s.Stdout()
+		result, err := s.d.Run(s.program, evaluator.NewEnvironment(cfg))
+		code := 0
+		if errObj, ok := result.(*object.Error); ok {
+			if errObj.Exit {
+				code = errObj.Code
+			} else {
+				s.event("output", map[string]string{"category": "stderr", "output": errObj.Inspect() + "\n"})
+				code = 1
+			}
+		}
+		if err != nil {
+			code = 1
+		}
+		s.event("exited", map[string]int{"exitCode": code})
+		s.event("terminated", nil)
+	}()
+}
+
+// onStop runs on the goroutine of the program and blocks it until the client asks it to carry on.
+func (s *Server) onStop(d *debugger.Debugger, stop debugger.Stop) debugger.Action {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+	s.event("stopped", map[string]interface{}{"reason": stop.Reason, "threadId": threadID, "allThreadsStopped": true})
+	select {
+	case a := <-s.resume:
+		return a
+	case <-s.quit:
+		return debugger.Quit
+	}
+}
+
+func (s *Server) continueWith(a debugger.Action) {
+	if !s.isStopped() {
+		return
+	}
+	s.mu.Lock()
+	s.stopped = false
+	s.mu.Unlock()
+	s.refs = nil
+	s.resume <- a
+}
+
+func (s *Server) stackTrace() (interface{}, error) {
+	if !s.isStopped() {
+		return nil, errRunning
+	}
+	frames := []StackFrame{}
+	for i, f := range s.d.Frames() {
+		line, column := s.clientPos(f.Pos)
+		frames = append(frames, StackFrame{
+			ID:     i + 1,
+			Name:   f.Name,
+			Source: &Source{Name: filepath.Base(s.path), Path: s.path},
+			Line:   line,
+			Column: column,
+		})
+	}
+	return map[string]interface{}{"stackFrames": frames, "totalFrames": len(frames)}, nil
+}
+
+func (s *Server) frame(id int) (debugger.Frame, error) {
+	frames := s.d.Frames()
+	if id < 1 || id > len(frames) {
+		return debugger.Frame{}, fmt.Errorf("no frame %d", id)
+	}
+	return frames[id-1], nil
+}
+
+// scopes returns a scope for every environment of the chain of a frame.
+func (s *Server) scopes(frameID int) (interface{}, error) {
+	if !s.isStopped() {
+		return nil, errRunning
+	}
+	f, err := s.frame(frameID)
+	if err != nil {
+		return nil, err
+	}
+	scopes := []Scope{}
+	for env, level := f.Env, 0; env != nil; env, level = env.Outer(), level+1 {
+		name := "Closure"
+		switch {
+		case env.Outer() == nil:
+			name = "Globals"
+		case level == 0:
+			name = "Locals"
+		}
+		scopes = append(scopes, Scope{Name: name, VariablesReference: s.reference(env)})
+	}
+	return map[string]interface{}{"scopes": scopes}, nil
+}
+
+func (s *Server) reference(v interface{}) int {
+	s.refs = append(s.refs, v)
+	return len(s.refs)
+}
+
+func (s *Server) variables(ref int) (interface{}, error) {
+	if !s.isStopped() {
+		return nil, errRunning
+	}
+	if ref < 1 || ref > len(s.refs) {
+		return nil, fmt.Errorf("no variables reference %d", ref)
+	}
+
+	vars := []Variable{}
+	switch v := s.refs[ref-1].(type) {
+	case *object.Environment:
+		for _, name := range v.Names() {
+			value, _ := v.Get(name)
+			vars = append(vars, s.variable(name, value))
+		}
+	case *object.Array:
+		for i, el := range v.Elements {
+			vars = append(vars, s.variable(fmt.Sprintf("[%d]", i), el))
+		}
+	case *object.Hash:
+		for _, pair := range v.Ordered() {
+			vars = append(vars, s.variable(pair.Key.Inspect(), pair.Value))
+		}
+	}
+	return map[string]interface{}{"variables": vars}, nil
+}
+
+/*
+	variable describes a value, arrays and hashes get a reference so their elements are only sent when asked
+	for and their value is a summary, array[3] for example, rather than the whole of their content.
+*/
+func (s *Server) variable(name string, value object.Object) Variable {
+	v := Variable{Name: name, Value: value.Inspect(), Type: strings.ToLower(string(value.Type()))}
+	switch value := value.(type) {
+	case *object.Array:
+		v.Value = fmt.Sprintf("array[%d]", len(value.Elements))
+		if len(value.Elements) > 0 {
+			v.VariablesReference = s.reference(value)
+		}
+	case *object.Hash:
+		v.Value = fmt.Sprintf("hash[%d]", len(value.Pairs))
+		if len(value.Pairs) > 0 {
+			v.VariablesReference = s.reference(value)
+		}
+	}
+	return v
+}
+
+func (s *Server) evaluate(args EvaluateArguments) (interface{}, error) {
+	if !s.isStopped() {
+		return nil, errRunning
+	}
+	frame := 0
+	if args.FrameID != nil {
+		frame = *args.FrameID - 1
+	}
+	result := s.d.Evaluate(args.Expression, frame)
+	if errObj, ok := result.(*object.Error); ok {
+		return nil, errors.New(errObj.Message)
+	}
+	v := s.variable("", result)
+	return map[string]interface{}{"result": v.Value, "type": v.Type, "variablesReference": v.VariablesReference}, nil
+}
@@ -0,0 +1,319 @@
+package dap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-interpreter-lexer/evaluator"
+)
+
+type message struct {
+	Type       string          `json:"type"`
+	Command    string          `json:"command"`
+	Event      string          `json:"event"`
+	RequestSeq int             `json:"request_seq"`
+	Success    bool            `json:"success"`
+	Message    string          `json:"message"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// client talks to a server running on a goroutine.
+type client struct {
+	t        *testing.T
+	w        io.Writer
+	r        *bufio.Reader
+	seq      int
+	messages chan message
+	backlog  []message
+}
+
+func newClient(t *testing.T, cfg evaluator.Config) (*client, chan error) {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	done := make(chan error, 1)
+	s := NewServer(inR, outW)
+	s.Config = cfg
+	go func() {
+		done <- s.Run()
+		outW.Close()
+	}()
+
+	c := &client{t: t, w: inW, r: bufio.NewReader(outR), messages: make(chan message, 100)}
+	go func() {
+		for {
+			body, err := readMessage(c.r)
+			if err != nil {
+				close(c.messages)
+				return
+			}
+			var m message
+			json.Unmarshal(body, &m)
+			c.messages <- m
+		}
+	}()
+	return c, done
+}
+
+func (c *client) send(command string, args interface{}) int {
+	c.seq++
+	writeMessage(c.w, map[string]interface{}{"seq": c.seq, "type": "request", "command": command, "arguments": args})
+	return c.seq
+}
+
+// wait returns the first message that matches, messages that do not are kept for later calls.
+func (c *client) wait(match func(m message) bool) message {
+	for i, m := range c.backlog {
+		if match(m) {
+			c.backlog = append(c.backlog[:i], c.backlog[i+1:]...)
+			return m
+		}
+	}
+	for {
+		select {
+		case m, ok := <-c.messages:
+			if !ok {
+				c.t.Fatalf("server closed the connection")
+			}
+			if match(m) {
+				return m
+			}
+			c.backlog = append(c.backlog, m)
+		case <-time.After(5 * time.Second):
+			c.t.Fatalf("timed out waiting for a message")
+		}
+	}
+}
+
+// request sends a request and returns the body of its response, failing the test if it did not succeed.
+func (c *client) request(command string, args interface{}, body interface{}) {
+	seq := c.send(command, args)
+	resp := c.wait(func(m message) bool { return m.Type == "response" && m.RequestSeq == seq })
+	if !resp.Success {
+		c.t.Fatalf("%s failed: %s", command, resp.Message)
+	}
+	if body != nil {
+		json.Unmarshal(resp.Body, body)
+	}
+}
+
+func (c *client) event(name string) message {
+	return c.wait(func(m message) bool { return m.Type == "event" && m.Event == name })
+}
+
+const program = `let add = fn(a, b) {
+  let sum = a + b;
+  sum
+};
+let list = [1, [2, 3], {"k": 4}];
+let x = add(1, 2);
+let y = add(x, 3);
+y;`
+
+func launch(t *testing.T, stopOnEntry bool, lines ...int) (*client, chan error) {
+	return launchSource(t, evaluator.Config{}, program, stopOnEntry, lines...)
+}
+
+// launchSource launches source evaluated with cfg, stopping on the given lines.
+func launchSource(t *testing.T, cfg evaluator.Config, source string, stopOnEntry bool, lines ...int) (*client, chan error) {
+	path := filepath.Join(t.TempDir(), "prog.mk")
+	if err := ioutil.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, done := newClient(t, cfg)
+	var caps map[string]interface{}
+	c.request("initialize", map[string]interface{}{"adapterID": "monkey"}, &caps)
+	if caps["supportsConfigurationDoneRequest"] != true {
+		t.Errorf("wrong capabilities %v", caps)
+	}
+	c.event("initialized")
+	c.request("launch", map[string]interface{}{"program": path, "stopOnEntry": stopOnEntry}, nil)
+
+	bps := []map[string]int{}
+	for _, l := range lines {
+		bps = append(bps, map[string]int{"line": l})
+	}
+	var set struct{ Breakpoints []Breakpoint }
+	c.request("setBreakpoints", map[string]interface{}{"source": map[string]string{"path": path}, "breakpoints": bps}, &set)
+	for _, bp := range set.Breakpoints {
+		if !bp.Verified {
+			t.Errorf("breakpoint on line %d not verified", bp.Line)
+		}
+	}
+	c.request("configurationDone", nil, nil)
+	return c, done
+}
+
+type stackTrace struct {
+	StackFrames []StackFrame
+}
+
+func stopped(c *client) (string, stackTrace) {
+	var body struct{ Reason string }
+	json.Unmarshal(c.event("stopped").Body, &body)
+	var st stackTrace
+	c.request("stackTrace", map[string]int{"threadId": 1}, &st)
+	return body.Reason, st
+}
+
+func TestBreakpointsAndStepping(t *testing.T) {
+	c, done := launch(t, false, 2)
+
+	reason, st := stopped(c)
+	if reason != "breakpoint" || len(st.StackFrames) != 2 || st.StackFrames[0].Name != "add" ||
+		st.StackFrames[0].Line != 2 || st.StackFrames[1].Line != 6 {
+		t.Fatalf("wrong stop %s %+v", reason, st)
+	}
+
+	c.request("next", map[string]int{"threadId": 1}, nil)
+	reason, st = stopped(c)
+	if reason != "step" || st.StackFrames[0].Line != 3 {
+		t.Fatalf("next stopped at %s %+v", reason, st)
+	}
+	c.request("stepOut", map[string]int{"threadId": 1}, nil)
+	if _, st = stopped(c); len(st.StackFrames) != 1 || st.StackFrames[0].Line != 7 {
+		t.Fatalf("stepOut stopped at %+v", st)
+	}
+	c.request("stepIn", map[string]int{"threadId": 1}, nil)
+	if _, st = stopped(c); len(st.StackFrames) != 2 || st.StackFrames[0].Line != 2 {
+		t.Fatalf("stepIn stopped at %+v", st)
+	}
+
+	c.request("setBreakpoints", map[string]interface{}{"source": map[string]string{"path": st.StackFrames[0].Source.Path},
+		"breakpoints": []map[string]int{}}, nil)
+	c.request("continue", map[string]int{"threadId": 1}, nil)
+	var exited struct{ ExitCode int }
+	json.Unmarshal(c.event("exited").Body, &exited)
+	if exited.ExitCode != 0 {
+		t.Errorf("wrong exit code %d", exited.ExitCode)
+	}
+	c.event("terminated")
+	c.request("disconnect", nil, nil)
+	if err := <-done; err != nil {
+		t.Errorf("Run returned %v", err)
+	}
+}
+
+func TestVariables(t *testing.T) {
+	c, done := launch(t, false, 3)
+	_, st := stopped(c)
+
+	var scopes struct{ Scopes []Scope }
+	c.request("scopes", map[string]int{"frameId": st.StackFrames[0].ID}, &scopes)
+	if len(scopes.Scopes) != 2 || scopes.Scopes[0].Name != "Locals" || scopes.Scopes[1].Name != "Globals" {
+		t.Fatalf("wrong scopes %+v", scopes)
+	}
+
+	var vars struct{ Variables []Variable }
+	c.request("variables", map[string]int{"variablesReference": scopes.Scopes[0].VariablesReference}, &vars)
+	if len(vars.Variables) != 3 || vars.Variables[2].Name != "sum" || vars.Variables[2].Value != "3" || vars.Variables[2].Type != "integer" {
+		t.Errorf("wrong locals %+v", vars.Variables)
+	}
+
+	c.request("variables", map[string]int{"variablesReference": scopes.Scopes[1].VariablesReference}, &vars)
+	var list Variable
+	for _, v := range vars.Variables {
+		if v.Name == "list" {
+			list = v
+		}
+	}
+	if list.VariablesReference == 0 || list.Value != "array[3]" {
+		t.Fatalf("arrays should be expandable and summarised, got %+v", vars.Variables)
+	}
+	c.request("variables", map[string]int{"variablesReference": list.VariablesReference}, &vars)
+	if len(vars.Variables) != 3 || vars.Variables[0].VariablesReference != 0 || vars.Variables[1].VariablesReference == 0 || vars.Variables[2].Value != "hash[1]" {
+		t.Fatalf("wrong elements %+v", vars.Variables)
+	}
+	c.request("variables", map[string]int{"variablesReference": vars.Variables[2].VariablesReference}, &vars)
+	if len(vars.Variables) != 1 || vars.Variables[0].Name != "k" || vars.Variables[0].Value != "4" {
+		t.Errorf("wrong hash pairs %+v", vars.Variables)
+	}
+
+	var result struct {
+		Result string
+		Type   string
+	}
+	c.request("evaluate", map[string]interface{}{"expression": "sum * 10", "frameId": st.StackFrames[0].ID}, &result)
+	if result.Result != "30" || result.Type != "integer" {
+		t.Errorf("wrong evaluate result %+v", result)
+	}
+	seq := c.send("evaluate", map[string]interface{}{"expression": "nope", "frameId": 1})
+	if resp := c.wait(func(m message) bool { return m.RequestSeq == seq }); resp.Success || resp.Message != "identifier not found: nope" {
+		t.Errorf("evaluate of an unknown identifier should fail, got %+v", resp)
+	}
+
+	c.request("disconnect", nil, nil)
+	if err := <-done; err != nil {
+		t.Errorf("Run returned %v", err)
+	}
+}
+
+func TestStopOnEntry(t *testing.T) {
+	c, done := launch(t, true)
+	if reason, st := stopped(c); reason != "entry" || st.StackFrames[0].Line != 1 {
+		t.Errorf("wrong stop on entry %s %+v", reason, st)
+	}
+	c.request("disconnect", nil, nil)
+	if err := <-done; err != nil {
+		t.Errorf("Run returned %v", err)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	cfg := evaluator.Config{Process: evaluator.Process{Allow: evaluator.AllowExit}}
+	c, done := launchSource(t, cfg, "puts(1);\nexit(3);", false)
+	var exited struct{ ExitCode int }
+	json.Unmarshal(c.event("exited").Body, &exited)
+	if exited.ExitCode != 3 {
+		t.Errorf("wrong exit code %d", exited.ExitCode)
+	}
+	for _, m := range c.backlog {
+		if m.Event == "output" && strings.Contains(string(m.Body), "stderr") {
+			t.Errorf("exit should not be reported as an error, got %s", m.Body)
+		}
+	}
+	c.request("disconnect", nil, nil)
+	if err := <-done; err != nil {
+		t.Errorf("Run returned %v", err)
+	}
+}
+
+func TestDisconnectWhileRunning(t *testing.T) {
+	source := `let f = fn(n) { if (n == 0) { 0 } else { f(n - 1) } };
+map(range(10000000), fn(i) { f(100) });`
+	c, done := launchSource(t, evaluator.Config{}, source, false)
+	c.request("disconnect", nil, nil)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run returned %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the program was not stopped by the disconnect")
+	}
+}
+
+func TestStdout(t *testing.T) {
+	var out bytes.Buffer
+	s := NewServer(strings.NewReader(""), &out)
+	fmt.Fprint(s.Stdout(), "hello\n")
+
+	body, err := readMessage(bufio.NewReader(&out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m message
+	json.Unmarshal(body, &m)
+	var output struct{ Category, Output string }
+	json.Unmarshal(m.Body, &output)
+	if m.Event != "output" || output.Category != "stdout" || output.Output != "hello\n" {
+		t.Errorf("wrong output event %s", body)
+	}
+}
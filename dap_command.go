@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"go-interpreter-lexer/dap"
+	"go-interpreter-lexer/evaluator"
+)
+
+/*
+	dapCommand runs the debug adapter on stdin and stdout, monkey dap. Stdin and stdout carry the protocol so
+	what the program prints is sent to the client as output events instead and io.readLine reads nothing.
+*/
+func dapCommand(args []string) int {
+	fs := flag.NewFlagSet("dap", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	s := dap.NewServer(os.Stdin, os.Stdout)
+	s.Config.IO.Files = evaluator.HostFS()
+
+	if err := s.Run(); err != nil {
+		fmt.Fprintln(os.Stderr, "monkey dap:", err)
+		return 1
+	}
+	return 0
+}
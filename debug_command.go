@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"go-interpreter-lexer/debugger"
+	"go-interpreter-lexer/diagnostic"
+	"go-interpreter-lexer/evaluator"
+	"go-interpreter-lexer/object"
+	"go-interpreter-lexer/resolver"
+)
+
+const debugHelp = `commands:
+  break LINE (b)     set a breakpoint on a line
+  clear LINE         remove the breakpoint on a line
+  continue (c)       run until the next breakpoint
+  step (s)           step to the next statement, into function calls
+  next (n)           step to the next statement, over function calls
+  out (o)            run until the current function returns
+  stack (bt)         print the call stack
+  frame N            select frame N of the stack for vars and print
+  vars               print the environments of the selected frame, innermost first
+  print EXPR (p)     evaluate an expression in the selected frame
+  watch EXPR         print an expression every time the program stops
+  unwatch N          remove a watch expression
+  list (l)           print the source around the current line
+  quit (q)           stop the program
+`
+
+// debugCommand runs a file under the debugger reading commands from stdin, monkey debug file.mk
+func debugCommand(args []string) int {
+	fs := flag.NewFlagSet("debug", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: monkey debug file.mk")
+		return 2
+	}
+	path := fs.Arg(0)
+
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	// the source is kept for the list command, so it is parsed here rather than read again by parseFile.
+	program, diags := parseSource(string(src))
+	if len(diags) != 0 {
+		printDiagnostics(os.Stderr, path, diags)
+		return 1
+	}
+	r := resolver.New(evaluator.BuiltinNames())
+	r.Resolve(program)
+	printDiagnostics(os.Stderr, path, r.Diagnostics())
+	if diagnostic.HasErrors(r.Diagnostics()) {
+		return 1
+	}
+
+	s := &debugSession{
+		in:    bufio.NewScanner(os.Stdin),
+		out:   os.Stdout,
+		path:  path,
+		lines: strings.Split(string(src), "\n"),
+	}
+	// the debugger reads its commands from stdin, so the program cannot.
+	env := evaluator.NewEnvironment(evaluator.Config{IO: evaluator.IO{Stdout: os.Stdout, Files: evaluator.HostFS()}})
+	d := debugger.New(s.stopped)
+	d.StopOnEntry()
+	fmt.Fprintln(s.out, "monkey debugger, type help for the list of commands")
+	result, err := d.Run(program, env)
+	if err != nil {
+		return 1
+	}
+	if errObj, ok := result.(*object.Error); ok {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", path, errObj.Inspect())
+		return 1
+	}
+	fmt.Fprintln(s.out, "program finished")
+	return 0
+}
+
+type debugSession struct {
+	in    *bufio.Scanner
+	out   io.Writer
+	path  string
+	lines []string
+	line  int
+	// frame is the frame selected with the frame command, 0 is the innermost.
+	frame int
+}
+
+func (s *debugSession) stopped(d *debugger.Debugger, stop debugger.Stop) debugger.Action {
+	s.line = stop.Pos.Line
+	s.frame = 0
+	fmt.Fprintf(s.out, "%s:%s: stopped (%s)\n", s.path, stop.Pos, stop.Reason)
+	s.list(s.line, 0)
+	for i, w := range d.Watches() {
+		fmt.Fprintf(s.out, "watch %d: %s = %s\n", i, w, d.Evaluate(w, 0).Inspect())
+	}
+
+	for {
+		fmt.Fprint(s.out, "(debug) ")
+		if !s.in.Scan() {
+			return debugger.Quit
+		}
+		cmd, arg := splitCommand(s.in.Text())
+		switch cmd {
+		case "":
+		case "c", "continue":
+			return debugger.Continue
+		case "s", "step":
+			return debugger.StepIn
+		case "n", "next":
+			return debugger.StepOver
+		case "o", "out":
+			return debugger.StepOut
+		case "q", "quit":
+			return debugger.Quit
+		case "b", "break", "clear":
+			line, err := strconv.Atoi(arg)
+			if err != nil {
+				fmt.Fprintf(s.out, "invalid line %q\n", arg)
+				continue
+			}
+			if cmd == "clear" {
+				d.ClearBreakpoint(line)
+			} else {
+				d.SetBreakpoint(line)
+			}
+			fmt.Fprintf(s.out, "breakpoints: %v\n", d.Breakpoints())
+		case "bt", "stack":
+			for i, f := range d.Frames() {
+				fmt.Fprintf(s.out, "#%d %s at %s", i, f.Name, f.Pos)
+				if f.Call.IsValid() {
+					fmt.Fprintf(s.out, ", called at %s", f.Call)
+				}
+				fmt.Fprintln(s.out)
+			}
+		case "frame":
+			n, err := strconv.Atoi(arg)
+			if err != nil || n < 0 || n >= len(d.Frames()) {
+				fmt.Fprintf(s.out, "invalid frame %q\n", arg)
+				continue
+			}
+			s.frame = n
+		case "vars":
+			s.vars(d.Frames()[s.frame].Env)
+		case "p", "print":
+			fmt.Fprintln(s.out, d.Evaluate(arg, s.frame).Inspect())
+		case "watch":
+			d.Watch(arg)
+		case "unwatch":
+			i, err := strconv.Atoi(arg)
+			if err != nil {
+				fmt.Fprintf(s.out, "invalid watch %q\n", arg)
+				continue
+			}
+			d.Unwatch(i)
+		case "l", "list":
+			s.list(s.line, 3)
+		case "h", "help":
+			fmt.Fprint(s.out, debugHelp)
+		default:
+			fmt.Fprintf(s.out, "unknown command %q, type help for the list of commands\n", cmd)
+		}
+	}
+}
+
+// vars prints every environment of a chain, from env itself to the global one.
+func (s *debugSession) vars(env *object.Environment) {
+	level := 0
+	for ; env != nil; env = env.Outer() {
+		name := fmt.Sprintf("enclosing %d", level)
+		switch {
+		case env.Outer() == nil:
+			name = "global"
+		case level == 0:
+			name = "local"
+		}
+		fmt.Fprintf(s.out, "%s:\n", name)
+		for _, n := range env.Names() {
+			v, _ := env.Get(n)
+			fmt.Fprintf(s.out, "  %s = %s\n", n, v.Inspect())
+		}
+		level++
+	}
+}
+
+// list prints the source lines within context lines of line, marking line itself.
+func (s *debugSession) list(line, context int) {
+	for n := line - context; n <= line+context; n++ {
+		if n < 1 || n > len(s.lines) {
+			continue
+		}
+		marker := "  "
+		if n == line {
+			marker = "=>"
+		}
+		fmt.Fprintf(s.out, "%s %4d  %s\n", marker, n, s.lines[n-1])
+	}
+}
+
+func splitCommand(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		return line[:i], strings.TrimSpace(line[i+1:])
+	}
+	return line, ""
+}
@@ -0,0 +1,289 @@
+/*
+	Package debugger runs a program under the control of a front end, the monkey debug command or a debug
+	adapter. It installs an evaluator hook that stops before statements on lines with a breakpoint or after
+	a step, and lets the front end look at the call stack, the environments of each frame and the value of
+	expressions while the program is stopped.
+*/
+package debugger
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/evaluator"
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/object"
+	"go-interpreter-lexer/parser"
+	"go-interpreter-lexer/token"
+)
+
+// Action is what the front end wants the program to do after it stopped.
+type Action int
+
+const (
+	Continue Action = iota
+	// StepIn stops at the next statement, inside a function if one is called.
+	StepIn
+	// StepOver stops at the next statement of the current function or of its callers.
+	StepOver
+	// StepOut stops at the next statement after the current function returns.
+	StepOut
+	// Quit abandons the program, Run returns ErrQuit.
+	Quit
+)
+
+// Stop describes why and where the program stopped.
+type Stop struct {
+	// Reason is "entry", "breakpoint" or "step".
+	Reason string
+	Node   ast.Statement
+	Pos    token.Position
+}
+
+// Frame is a function call in progress, the outermost frame is the program itself.
+type Frame struct {
+	// Name is the name the function was called by or declared with, "<program>" for the outermost frame
+	// and "<anonymous>" for a function without a name that was not called through an identifier.
+	Name string
+	// Call is the position of the call expression, invalid for the outermost frame.
+	Call token.Position
+	// Pos is the position of the node the frame is evaluating.
+	Pos token.Position
+	Env *object.Environment
+}
+
+// ErrQuit is returned by Run when the front end answers a stop with Quit.
+var ErrQuit = errors.New("debugger: quit")
+
+type Debugger struct {
+	onStop func(d *Debugger, s Stop) Action
+	// mu guards breakpoints, which front ends may change while the program runs.
+	mu          sync.Mutex
+	breakpoints map[int]bool
+	watches     []string
+
+	frames    []*Frame
+	action    Action
+	stepDepth int
+	// lastLine and lastDepth are where the program last stopped or passed, a line is only stopped at once
+	// for each time the frame reaches it.
+	lastLine, lastDepth int
+	evaluating          bool
+	entry               bool
+	// quit is set by Quit from another goroutine, the program is abandoned before its next node.
+	quit atomic.Bool
+}
+
+/*
+	New returns a debugger that calls onStop each time the program stops and carries on as the returned
+	action says. onStop runs on the goroutine evaluating the program, which is blocked until it returns.
+*/
+func New(onStop func(d *Debugger, s Stop) Action) *Debugger {
+	return &Debugger{onStop: onStop, breakpoints: make(map[int]bool)}
+}
+
+// StopOnEntry makes the program stop before its first statement.
+func (d *Debugger) StopOnEntry() {
+	d.entry = true
+}
+
+// Quit abandons the running program from another goroutine, Run returns ErrQuit before the next node is evaluated.
+func (d *Debugger) Quit() {
+	d.quit.Store(true)
+}
+
+func (d *Debugger) SetBreakpoint(line int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.breakpoints[line] = true
+}
+
+func (d *Debugger) ClearBreakpoint(line int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.breakpoints, line)
+}
+
+// ClearBreakpoints removes every breakpoint.
+func (d *Debugger) ClearBreakpoints() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.breakpoints = make(map[int]bool)
+}
+
+// Breakpoints returns the lines with a breakpoint in order.
+func (d *Debugger) Breakpoints() []int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	lines := make([]int, 0, len(d.breakpoints))
+	for line := range d.breakpoints {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+	return lines
+}
+
+// Watch adds an expression that front ends show each time the program stops.
+func (d *Debugger) Watch(expr string) {
+	d.watches = append(d.watches, expr)
+}
+
+// Unwatch removes the watch expression at index i, as numbered by Watches.
+func (d *Debugger) Unwatch(i int) {
+	if i >= 0 && i < len(d.watches) {
+		d.watches = append(d.watches[:i], d.watches[i+1:]...)
+	}
+}
+
+func (d *Debugger) Watches() []string {
+	return d.watches
+}
+
+/*
+	Run evaluates program in env under the debugger. It returns the result of the program, or ErrQuit if
+	the front end quit while it was stopped.
+*/
+func (d *Debugger) Run(program *ast.Program, env *object.Environment) (result object.Object, err error) {
+	d.frames = []*Frame{{Name: "<program>", Env: env}}
+	d.lastLine, d.lastDepth = 0, 0
+	d.action = Continue
+	if d.entry {
+		d.action = StepIn
+	}
+
+	prev := evaluator.SetHook(env, d)
+	defer func() {
+		evaluator.SetHook(env, prev)
+		if r := recover(); r != nil {
+			if r != ErrQuit {
+				panic(r)
+			}
+			result, err = nil, ErrQuit
+		}
+	}()
+	return evaluator.Eval(program, env), nil
+}
+
+// Frames returns the call stack, innermost frame first.
+func (d *Debugger) Frames() []Frame {
+	frames := make([]Frame, len(d.frames))
+	for i, f := range d.frames {
+		frames[len(d.frames)-1-i] = *f
+	}
+	return frames
+}
+
+/*
+	Evaluate evaluates an expression in the environment of a frame, numbered as by Frames. Errors, including
+	syntax errors in the expression, are returned as an *object.Error.
+*/
+func (d *Debugger) Evaluate(expr string, frame int) object.Object {
+	if frame < 0 || frame >= len(d.frames) {
+		return &object.Error{Message: "no such frame"}
+	}
+	p := parser.New(lexer.New(expr))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return &object.Error{Message: strings.TrimSpace(p.Errors()[0])}
+	}
+	d.evaluating = true
+	defer func() { d.evaluating = false }()
+	result := evaluator.Eval(program, d.frames[len(d.frames)-1-frame].Env)
+	if result == nil {
+		return evaluator.NULL
+	}
+	return result
+}
+
+// Eval implements evaluator.Hook, it is where the program stops.
+func (d *Debugger) Eval(node ast.Node, env *object.Environment) {
+	if d.quit.Load() {
+		panic(ErrQuit)
+	}
+	if d.evaluating || node == nil {
+		return
+	}
+	top := d.frames[len(d.frames)-1]
+	top.Env = env
+	pos := ast.Pos(node)
+	if pos.IsValid() {
+		top.Pos = pos
+	}
+
+	stmt, ok := node.(ast.Statement)
+	if !ok || !pos.IsValid() {
+		return
+	}
+	if _, ok := stmt.(*ast.BlockStatement); ok {
+		return
+	}
+	depth := len(d.frames)
+	if pos.Line == d.lastLine && depth == d.lastDepth {
+		return
+	}
+	d.lastLine, d.lastDepth = pos.Line, depth
+
+	reason := ""
+	switch {
+	case d.action == StepIn, d.action == StepOver && depth <= d.stepDepth, d.action == StepOut && depth < d.stepDepth:
+		reason = "step"
+		if d.entry {
+			reason = "entry"
+			d.entry = false
+		}
+	case d.hasBreakpoint(pos.Line):
+		reason = "breakpoint"
+	default:
+		return
+	}
+
+	d.action = d.onStop(d, Stop{Reason: reason, Node: stmt, Pos: pos})
+	d.stepDepth = depth
+	if d.action == Quit {
+		panic(ErrQuit)
+	}
+}
+
+func (d *Debugger) hasBreakpoint(line int) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.breakpoints[line]
+}
+
+// Call implements evaluator.Hook, calls of user functions push a frame.
+func (d *Debugger) Call(call *ast.CallExpression, fn object.Object, args []object.Object) {
+	if d.evaluating {
+		return
+	}
+	f, ok := fn.(*object.Function)
+	if !ok {
+		return
+	}
+	frame := &Frame{Name: "<anonymous>", Env: f.Env}
+	if f.Name != "" {
+		frame.Name = f.Name
+	}
+	if call != nil {
+		frame.Call = ast.Pos(call)
+		// the caller is shown at the call rather than at its last argument.
+		d.frames[len(d.frames)-1].Pos = frame.Call
+		if ident, ok := call.Function.(*ast.Identifier); ok {
+			frame.Name = ident.Value
+		}
+	}
+	d.frames = append(d.frames, frame)
+}
+
+// Return implements evaluator.Hook.
+func (d *Debugger) Return(call *ast.CallExpression, fn object.Object, result object.Object) {
+	if d.evaluating {
+		return
+	}
+	if _, ok := fn.(*object.Function); ok && len(d.frames) > 1 {
+		d.frames = d.frames[:len(d.frames)-1]
+	}
+}
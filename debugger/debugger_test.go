@@ -0,0 +1,128 @@
+package debugger
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/object"
+	"go-interpreter-lexer/parser"
+)
+
+const source = `let add = fn(a, b) {
+  let sum = a + b;
+  sum
+};
+let x = add(1, 2);
+let y = add(x, 3);
+y;`
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors %v", p.Errors())
+	}
+	return program
+}
+
+// script answers each stop with the next action and records where the program stopped as line:depth.
+func script(actions ...Action) (func(d *Debugger, s Stop) Action, *[]string) {
+	var stops []string
+	return func(d *Debugger, s Stop) Action {
+		stops = append(stops, fmt.Sprintf("%s %d:%d", s.Reason, s.Pos.Line, len(d.Frames())))
+		if len(actions) == 0 {
+			return Continue
+		}
+		a := actions[0]
+		actions = actions[1:]
+		return a
+	}, &stops
+}
+
+func TestStepping(t *testing.T) {
+	tests := []struct {
+		name        string
+		breakpoints []int
+		entry       bool
+		actions     []Action
+		expected    string
+	}{
+		{"breakpoints", []int{2, 6}, false, nil, "breakpoint 2:2, breakpoint 6:1, breakpoint 2:2"},
+		{"step in", nil, true, []Action{StepIn, StepIn, StepIn, StepIn, StepIn}, "entry 1:1, step 5:1, step 2:2, step 3:2, step 6:1, step 2:2"},
+		{"step over", nil, true, []Action{StepOver, StepOver, StepOver, StepOver}, "entry 1:1, step 5:1, step 6:1, step 7:1"},
+		{"step out", []int{2}, false, []Action{StepOut, Continue, StepOut}, "breakpoint 2:2, step 6:1, breakpoint 2:2, step 7:1"},
+	}
+
+	for _, tt := range tests {
+		onStop, stops := script(tt.actions...)
+		d := New(onStop)
+		for _, line := range tt.breakpoints {
+			d.SetBreakpoint(line)
+		}
+		if tt.entry {
+			d.StopOnEntry()
+		}
+		result, err := d.Run(parse(t, source), object.NewEnvironment())
+		if err != nil || result == nil {
+			t.Fatalf("%s: Run returned %v, %v", tt.name, result, err)
+		}
+		if got := strings.Join(*stops, ", "); got != tt.expected {
+			t.Errorf("%s: wrong stops.\nexpected=%s\ngot=%s", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestInspect(t *testing.T) {
+	var frames, values, names []string
+	d := New(func(d *Debugger, s Stop) Action {
+		for _, f := range d.Frames() {
+			frames = append(frames, fmt.Sprintf("%s %s %s", f.Name, f.Pos, f.Call))
+		}
+		values = append(values, d.Evaluate("a + b", 0).Inspect(), d.Evaluate("x", 1).Inspect(), d.Evaluate("sum", 0).Inspect())
+		values = append(values, d.Evaluate("let", 0).Inspect())
+		names = append(names, strings.Join(d.Frames()[0].Env.Names(), ","), strings.Join(d.Frames()[0].Env.Outer().Names(), ","))
+		return Quit
+	})
+	d.SetBreakpoint(3)
+	d.Watch("x")
+
+	_, err := d.Run(parse(t, source), object.NewEnvironment())
+	if err != ErrQuit {
+		t.Fatalf("expected ErrQuit, got %v", err)
+	}
+	if got := strings.Join(frames, "; "); got != "add 3:3 5:9; <program> 5:9 -" {
+		t.Errorf("wrong frames %s", got)
+	}
+	if got := strings.Join(values, "; "); got != "3; Error: identifier not found: x; 3; Error: expected next token to be IDENT got: EOF" {
+		t.Errorf("wrong values %s", got)
+	}
+	if got := strings.Join(names, "; "); got != "a,b,sum; add" {
+		t.Errorf("wrong environment names %s", got)
+	}
+	if w := d.Watches(); len(w) != 1 || w[0] != "x" {
+		t.Errorf("wrong watches %v", w)
+	}
+}
+
+func TestQuitRemovesHook(t *testing.T) {
+	stops := 0
+	d := New(func(d *Debugger, s Stop) Action {
+		stops++
+		return Quit
+	})
+	d.StopOnEntry()
+	env := object.NewEnvironment()
+	d.Run(parse(t, source), env)
+
+	// with the hook gone a second debugger only sees its own breakpoints, even in the same environment.
+	onStop, got := script()
+	d2 := New(onStop)
+	d2.SetBreakpoint(7)
+	d2.Run(parse(t, source), env)
+	if stops != 1 || strings.Join(*got, ",") != "breakpoint 7:1" {
+		t.Errorf("wrong stops %d %v", stops, *got)
+	}
+}
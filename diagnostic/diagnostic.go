@@ -0,0 +1,63 @@
+/*
+	Package diagnostic holds the structured form of the problems reported about a program by the parser and
+	the passes that run over the ast before it is evaluated.
+*/
+package diagnostic
+
+import (
+	"fmt"
+	"sort"
+
+	"go-interpreter-lexer/token"
+)
+
+type Severity int
+
+const (
+	Error Severity = iota
+	Warning
+	Info
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+/*
+	Diagnostic is a single problem found in the source. Code is a short stable name for the kind of problem,
+	e.g. "syntax" or "undefined", that tools can use to filter diagnostics.
+*/
+type Diagnostic struct {
+	Pos      token.Position
+	Severity Severity
+	Code     string
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", d.Pos, d.Severity, d.Message, d.Code)
+}
+
+// HasErrors reports whether any of the diagnostics is an error rather than a warning.
+func HasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// Sort orders diagnostics by position, keeping the order of diagnostics at the same position.
+func Sort(diags []Diagnostic) {
+	sort.SliceStable(diags, func(i, j int) bool {
+		return diags[i].Pos.Before(diags[j].Pos)
+	})
+}
@@ -0,0 +1,136 @@
+package evaluator
+
+import (
+	"sort"
+	"strconv"
+
+	"go-interpreter-lexer/object"
+)
+
+/*
+	assertions are the builtins of tests. They are not builtins of every program, the test runner declares
+	them in the environment of each test so other programs keep the names.
+*/
+var assertions = map[string]*object.Builtin{
+	"assert": {Fn: assert,
+		Doc: "assert(cond, msg) fails with msg unless cond is truthy, msg is optional.",
+	},
+	"assertEq": {Fn: assertEq,
+		Doc: "assertEq(got, want) fails unless got and want are equal, arrays and hashes are compared element by element.",
+	},
+	"assertError": {Fn: assertError,
+		Doc: "assertError(fn) calls fn without arguments and fails unless it returns an error, it returns the error message.",
+	},
+}
+
+// AssertionNames returns the sorted names of the assertions, the names a test can use on top of BuiltinNames.
+func AssertionNames() []string {
+	names := make([]string, 0, len(assertions))
+	for name := range assertions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DeclareAssertions binds the assertions in env, the global environment of a test.
+func DeclareAssertions(env *object.Environment) {
+	for name, b := range assertions {
+		env.Set(name, b)
+	}
+}
+
+func assert(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return newError("wrong number of arguments to `assert` got %d, wanted 1 or 2", len(args))
+	}
+	if isTruthy(args[0]) {
+		return NULL
+	}
+	if len(args) == 2 {
+		return newError("assertion failed: %s", args[1].Inspect())
+	}
+	return newError("assertion failed")
+}
+
+func assertEq(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments to `assertEq` got %d, wanted 2", len(args))
+	}
+	if !objectsEqual(args[0], args[1]) {
+		return newError("assertion failed: got %s, want %s", describe(args[0]), describe(args[1]))
+	}
+	return NULL
+}
+
+func assertError(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments to `assertError` got %d, wanted 1", len(args))
+	}
+	switch args[0].(type) {
+	case *object.Function, *object.Builtin:
+	default:
+		return newError("argument for `assertError` is suppose to be a function but got %s", args[0].Type())
+	}
+	result := applyFunction(nil, env, args[0], nil)
+	if errObj, ok := result.(*object.Error); ok {
+		// exit is not an error to catch, it still ends the program.
+		if errObj.Exit {
+			return errObj
+		}
+		return &object.String{Value: errObj.Message}
+	}
+	if result == nil {
+		result = NULL
+	}
+	return newError("assertion failed: expected an error, got %s", describe(result))
+}
+
+// objectsEqual compares values rather than identities, functions are only equal to themselves.
+func objectsEqual(a, b object.Object) bool {
+	switch a := a.(type) {
+	case *object.Integer, *object.BigInt, *object.Decimal:
+		return isNumber(b) && numbersEqual(a, b)
+	case *object.String:
+		b, ok := b.(*object.String)
+		return ok && a.Value == b.Value
+	case *object.Time:
+		b, ok := b.(*object.Time)
+		return ok && a.Value.Equal(b.Value)
+	case *object.Duration:
+		b, ok := b.(*object.Duration)
+		return ok && a.Value == b.Value
+	case *object.Array:
+		b, ok := b.(*object.Array)
+		if !ok || len(a.Elements) != len(b.Elements) {
+			return false
+		}
+		for i := range a.Elements {
+			if !objectsEqual(a.Elements[i], b.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case *object.Hash:
+		b, ok := b.(*object.Hash)
+		if !ok || len(a.Pairs) != len(b.Pairs) {
+			return false
+		}
+		for key, pair := range a.Pairs {
+			other, ok := b.Pairs[key]
+			if !ok || !objectsEqual(pair.Value, other.Value) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+// describe is the value of an object in a failure message, strings are quoted to tell "1" from 1.
+func describe(obj object.Object) string {
+	if s, ok := obj.(*object.String); ok {
+		return strconv.Quote(s.Value)
+	}
+	return obj.Inspect()
+}
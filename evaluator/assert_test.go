@@ -0,0 +1,42 @@
+package evaluator
+
+import (
+	"testing"
+
+	"go-interpreter-lexer/object"
+)
+
+func TestAssertions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`assert(1 < 2)`, "null"},
+		{`assert(false, "no")`, "assertion failed: no"},
+		{`assertEq([1, 2.50], [1n, 2.5])`, "null"},
+		{`assertEq(time.duration("1s"), time.duration("1000ms"))`, "null"},
+		{`assertEq(time.fromUnix(5), time.in(time.fromUnix(5), "Asia/Tokyo"))`, "null"},
+		{`assertEq(time.fromUnix(5), time.fromUnix(6))`, "assertion failed: got 1970-01-01T00:00:05Z, want 1970-01-01T00:00:06Z"},
+		{`uniq([time.duration("1s"), time.duration("1s"), time.duration("2s")])`, "[1s, 2s]"},
+		{`assertError(fn() { 1 / 0 })`, "division by zero: 1 / 0"},
+		{`assertError(fn() { 1 })`, "assertion failed: expected an error, got 1"},
+	}
+
+	for _, tt := range tests {
+		env := object.NewEnvironment()
+		DeclareAssertions(env)
+		checkInspect(t, tt.input, testEvalIn(env, tt.input), tt.expected)
+	}
+}
+
+func TestAssertionsOnlyInTests(t *testing.T) {
+	testInspect(t, `assertEq(1, 1)`, "identifier not found: assertEq")
+	testInspect(t, `let assert = fn(x) { x * 2 }; assert(2)`, "4")
+	for _, name := range AssertionNames() {
+		for _, builtin := range BuiltinNames() {
+			if name == builtin {
+				t.Errorf("%s is a builtin of every program", name)
+			}
+		}
+	}
+}
@@ -3,10 +3,11 @@ package evaluator
 import (
 	"go-interpreter-lexer/object"
 	"fmt"
+	"sort"
 )
 
 var builtins = map[string]*object.Builtin{
-	"len": &object.Builtin{Fn: func(args ...object.Object) object.Object{
+	"len": &object.Builtin{Fn: func(env *object.Environment, args ...object.Object) object.Object{
 			if len(args) != 1{
 				return newError("wrong number of arguments. got %d, want=1", len(args))
 			}
@@ -19,8 +20,9 @@ var builtins = map[string]*object.Builtin{
 					return newError("argument to `len` not supported, got %s", args[0].Type())
 			}
 		},
+		Doc: "len(x) returns the number of elements of an array or the number of bytes of a string.",
 	},
-	"first" : &object.Builtin{ Fn: func(args ...object.Object) object.Object{
+	"first" : &object.Builtin{ Fn: func(env *object.Environment, args ...object.Object) object.Object{
 		if len(args) != 1{
 			return newError("wrong number of arguments to first function got %d, wanted 1", len(args))
 		}
@@ -33,8 +35,9 @@ var builtins = map[string]*object.Builtin{
 		}
 		return NULL
 	  },
+	  Doc: "first(arr) returns the first element of an array or null when it is empty.",
 	},
-	"last" : &object.Builtin{ Fn: func(args ...object.Object) object.Object{
+	"last" : &object.Builtin{ Fn: func(env *object.Environment, args ...object.Object) object.Object{
 		if len(args) != 1{
 			return newError("wrong number of arguments to `last` function got %d, wanted 1", len(args))
 		}
@@ -47,12 +50,45 @@ var builtins = map[string]*object.Builtin{
 		}
 		return NULL
 	  },
+	  Doc: "last(arr) returns the last element of an array or null when it is empty.",
 	},
-	"puts": &object.Builtin{Fn: func(args ...object.Object) object.Object{
+	"puts": &object.Builtin{Fn: func(env *object.Environment, args ...object.Object) object.Object{
+			stdout := stateOf(env).io.Stdout
 			for _, arg := range args{
-				fmt.Println(arg.Inspect())
+				fmt.Fprintln(stdout, arg.Inspect())
 			}
 			return NULL
 		},
+		Doc: "puts(args...) prints each argument on its own line to the standard output and returns null.",
 	},
 }
+
+// modules holds the builtin modules by name, their members are reached with member access.
+var modules = map[string]*object.Module{}
+
+// BuiltinNames returns the sorted names of the builtin functions and modules, for passes that need to know them up front.
+func BuiltinNames() []string{
+	names := make([]string, 0, len(builtins) + len(modules))
+	for name := range builtins{
+		names = append(names, name)
+	}
+	for name := range modules{
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuiltinDoc returns the documentation of a builtin function, module or assertion, empty if there is none called name.
+func BuiltinDoc(name string) string{
+	if b, ok := builtins[name]; ok {
+		return b.Doc
+	}
+	if b, ok := assertions[name]; ok {
+		return b.Doc
+	}
+	if m, ok := modules[name]; ok {
+		return m.Doc
+	}
+	return ""
+}
@@ -0,0 +1,514 @@
+package evaluator
+
+import (
+	"sort"
+
+	"go-interpreter-lexer/object"
+)
+
+// the higher-order builtins call back into the evaluator so they are added once the builtins map exists.
+func init() {
+	for name, b := range map[string]*object.Builtin{
+		"push": {Fn: push,
+			Doc: "push(arr, values...) returns a new array with the values added to the end of arr.",
+		},
+		"rest": {Fn: rest,
+			Doc: "rest(arr) returns a new array with every element of arr but the first, null when arr is empty.",
+		},
+		"concat": {Fn: concat,
+			Doc: "concat(arrs...) returns a new array with the elements of every array in order.",
+		},
+		"slice": {Fn: slice,
+			Doc: "slice(arr, start, end) returns the elements from start up to but not including end, end is optional and negative indexes count from the end.",
+		},
+		"map": {Fn: mapArray,
+			Doc: "map(arr, fn) returns a new array with the result of calling fn on each element.",
+		},
+		"filter": {Fn: filter,
+			Doc: "filter(arr, fn) returns a new array with the elements for which fn returns a truthy value.",
+		},
+		"reduce": {Fn: reduce,
+			Doc: "reduce(arr, fn, initial) folds the array from the left with fn(acc, element), without initial the first element is the start.",
+		},
+		"find": {Fn: find,
+			Doc: "find(arr, fn) returns the first element for which fn returns a truthy value, null if there is none.",
+		},
+		"any": {Fn: anyElement,
+			Doc: "any(arr, fn) reports whether fn returns a truthy value for at least one element.",
+		},
+		"all": {Fn: allElements,
+			Doc: "all(arr, fn) reports whether fn returns a truthy value for every element.",
+		},
+		"sort": {Fn: sortArray,
+			Doc: "sort(arr, less) returns a sorted copy of an array of integers or strings, less(a, b) is optional and reports whether a goes before b.",
+		},
+		"reverse": {Fn: reverse,
+			Doc: "reverse(arr) returns a new array with the elements in the opposite order.",
+		},
+		"zip": {Fn: zip,
+			Doc: "zip(arrs...) returns an array of arrays holding the elements at the same index, as long as the shortest array.",
+		},
+		"range": {Fn: rangeArray,
+			Doc: "range(start, end, step) returns the integers from start up to but not including end, start and step are optional.",
+		},
+		"flatten": {Fn: flatten,
+			Doc: "flatten(arr) returns a new array with the elements of the arrays in arr in their place, one level deep.",
+		},
+		"uniq": {Fn: uniq,
+			Doc: "uniq(arr) returns a new array without the elements equal to one before them.",
+		},
+	} {
+		builtins[name] = b
+	}
+}
+
+// arrayArg returns the argument at i if it is an array.
+func arrayArg(name string, args []object.Object, i int) (*object.Array, *object.Error) {
+	arr, ok := args[i].(*object.Array)
+	if !ok {
+		return nil, newError("argument %d for `%s` is suppose to be an array but got %s", i+1, name, args[i].Type())
+	}
+	return arr, nil
+}
+
+// functionArg checks that the argument at i can be called.
+func functionArg(name string, args []object.Object, i int) *object.Error {
+	switch args[i].(type) {
+	case *object.Function, *object.Builtin:
+		return nil
+	}
+	return newError("argument %d for `%s` is suppose to be a function but got %s", i+1, name, args[i].Type())
+}
+
+func integerArg(name string, args []object.Object, i int) (int64, *object.Error) {
+	n, ok := args[i].(*object.Integer)
+	if !ok {
+		return 0, newError("argument %d for `%s` is suppose to be an integer but got %s", i+1, name, args[i].Type())
+	}
+	return n.Value, nil
+}
+
+func wrongArguments(name string, got int, want string) *object.Error {
+	return newError("wrong number of arguments to `%s` got %d, wanted %s", name, got, want)
+}
+
+// callback calls a function passed to the builtin called in env, a function that evaluates to nothing returns null.
+func callback(env *object.Environment, fn object.Object, args ...object.Object) object.Object {
+	result := applyFunction(nil, env, fn, args)
+	if result == nil {
+		return NULL
+	}
+	return result
+}
+
+// arrayAndFunction checks the arguments of the builtins that take an array and a function.
+func arrayAndFunction(name string, args []object.Object) (*object.Array, *object.Error) {
+	if len(args) != 2 {
+		return nil, wrongArguments(name, len(args), "2")
+	}
+	arr, err := arrayArg(name, args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := functionArg(name, args, 1); err != nil {
+		return nil, err
+	}
+	return arr, nil
+}
+
+func copyElements(elements []object.Object) []object.Object {
+	return append(make([]object.Object, 0, len(elements)), elements...)
+}
+
+func push(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) < 1 {
+		return wrongArguments("push", len(args), "at least 1")
+	}
+	arr, err := arrayArg("push", args, 0)
+	if err != nil {
+		return err
+	}
+	return &object.Array{Elements: append(copyElements(arr.Elements), args[1:]...)}
+}
+
+func rest(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return wrongArguments("rest", len(args), "1")
+	}
+	arr, err := arrayArg("rest", args, 0)
+	if err != nil {
+		return err
+	}
+	if len(arr.Elements) == 0 {
+		return NULL
+	}
+	return &object.Array{Elements: copyElements(arr.Elements[1:])}
+}
+
+func concat(env *object.Environment, args ...object.Object) object.Object {
+	elements := []object.Object{}
+	for i := range args {
+		arr, err := arrayArg("concat", args, i)
+		if err != nil {
+			return err
+		}
+		elements = append(elements, arr.Elements...)
+	}
+	return &object.Array{Elements: elements}
+}
+
+func slice(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) < 2 || len(args) > 3 {
+		return wrongArguments("slice", len(args), "2 or 3")
+	}
+	arr, err := arrayArg("slice", args, 0)
+	if err != nil {
+		return err
+	}
+	length := int64(len(arr.Elements))
+	start, err := integerArg("slice", args, 1)
+	if err != nil {
+		return err
+	}
+	end := length
+	if len(args) == 3 {
+		if end, err = integerArg("slice", args, 2); err != nil {
+			return err
+		}
+	}
+	start, end = clampIndex(start, length), clampIndex(end, length)
+	if start >= end {
+		return &object.Array{Elements: []object.Object{}}
+	}
+	return &object.Array{Elements: copyElements(arr.Elements[start:end])}
+}
+
+// clampIndex turns a negative index into one counted from the end and keeps it within 0 and length.
+func clampIndex(i, length int64) int64 {
+	if i < 0 {
+		i += length
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > length {
+		return length
+	}
+	return i
+}
+
+func mapArray(env *object.Environment, args ...object.Object) object.Object {
+	arr, err := arrayAndFunction("map", args)
+	if err != nil {
+		return err
+	}
+	elements := make([]object.Object, len(arr.Elements))
+	for i, el := range arr.Elements {
+		result := callback(env, args[1], el)
+		if isError(result) {
+			return result
+		}
+		elements[i] = result
+	}
+	return &object.Array{Elements: elements}
+}
+
+func filter(env *object.Environment, args ...object.Object) object.Object {
+	arr, err := arrayAndFunction("filter", args)
+	if err != nil {
+		return err
+	}
+	elements := []object.Object{}
+	for _, el := range arr.Elements {
+		result := callback(env, args[1], el)
+		if isError(result) {
+			return result
+		}
+		if isTruthy(result) {
+			elements = append(elements, el)
+		}
+	}
+	return &object.Array{Elements: elements}
+}
+
+func reduce(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) < 2 || len(args) > 3 {
+		return wrongArguments("reduce", len(args), "2 or 3")
+	}
+	arr, err := arrayArg("reduce", args, 0)
+	if err != nil {
+		return err
+	}
+	if err := functionArg("reduce", args, 1); err != nil {
+		return err
+	}
+	elements := arr.Elements
+	var acc object.Object
+	if len(args) == 3 {
+		acc = args[2]
+	} else {
+		if len(elements) == 0 {
+			return newError("`reduce` of an empty array without an initial value")
+		}
+		acc, elements = elements[0], elements[1:]
+	}
+	for _, el := range elements {
+		acc = callback(env, args[1], acc, el)
+		if isError(acc) {
+			return acc
+		}
+	}
+	return acc
+}
+
+func find(env *object.Environment, args ...object.Object) object.Object {
+	arr, err := arrayAndFunction("find", args)
+	if err != nil {
+		return err
+	}
+	for _, el := range arr.Elements {
+		result := callback(env, args[1], el)
+		if isError(result) {
+			return result
+		}
+		if isTruthy(result) {
+			return el
+		}
+	}
+	return NULL
+}
+
+func anyElement(env *object.Environment, args ...object.Object) object.Object {
+	arr, err := arrayAndFunction("any", args)
+	if err != nil {
+		return err
+	}
+	for _, el := range arr.Elements {
+		result := callback(env, args[1], el)
+		if isError(result) {
+			return result
+		}
+		if isTruthy(result) {
+			return TRUE
+		}
+	}
+	return FALSE
+}
+
+func allElements(env *object.Environment, args ...object.Object) object.Object {
+	arr, err := arrayAndFunction("all", args)
+	if err != nil {
+		return err
+	}
+	for _, el := range arr.Elements {
+		result := callback(env, args[1], el)
+		if isError(result) {
+			return result
+		}
+		if !isTruthy(result) {
+			return FALSE
+		}
+	}
+	return TRUE
+}
+
+/*
+	sortArray sorts a copy of the array, the sort is stable. Without a comparator the elements must all be
+	integers or all be strings. The first error returned by the comparator stops the sort.
+*/
+func sortArray(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return wrongArguments("sort", len(args), "1 or 2")
+	}
+	arr, err := arrayArg("sort", args, 0)
+	if err != nil {
+		return err
+	}
+	elements := copyElements(arr.Elements)
+
+	var less func(a, b object.Object) bool
+	var failed object.Object
+	if len(args) == 2 {
+		if err := functionArg("sort", args, 1); err != nil {
+			return err
+		}
+		less = func(a, b object.Object) bool {
+			if failed != nil {
+				return false
+			}
+			result := callback(env, args[1], a, b)
+			if isError(result) {
+				failed = result
+				return false
+			}
+			return isTruthy(result)
+		}
+	} else {
+		if err := sortable("sort", elements); err != nil {
+			return err
+		}
+		less = lessThan
+	}
+
+	sort.SliceStable(elements, func(i, j int) bool {
+		return less(elements[i], elements[j])
+	})
+	if failed != nil {
+		return failed
+	}
+	return &object.Array{Elements: elements}
+}
+
+// sortable checks that elements can be ordered by lessThan.
+func sortable(name string, elements []object.Object) *object.Error {
+	if len(elements) == 0 {
+		return nil
+	}
+	first := elements[0].Type()
+	for _, el := range elements {
+		if el.Type() != first || first != object.INTEGER_OBJ && first != object.STRING_OBJ {
+			return newError("`%s` without a comparator needs integers or strings, got %s and %s", name, first, el.Type())
+		}
+	}
+	return nil
+}
+
+// lessThan orders two integers or two strings.
+func lessThan(a, b object.Object) bool {
+	switch a := a.(type) {
+	case *object.Integer:
+		return a.Value < b.(*object.Integer).Value
+	case *object.String:
+		return a.Value < b.(*object.String).Value
+	}
+	return false
+}
+
+func reverse(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return wrongArguments("reverse", len(args), "1")
+	}
+	arr, err := arrayArg("reverse", args, 0)
+	if err != nil {
+		return err
+	}
+	elements := make([]object.Object, len(arr.Elements))
+	for i, el := range arr.Elements {
+		elements[len(elements)-1-i] = el
+	}
+	return &object.Array{Elements: elements}
+}
+
+func zip(env *object.Environment, args ...object.Object) object.Object {
+	arrays := make([]*object.Array, len(args))
+	length := -1
+	for i := range args {
+		arr, err := arrayArg("zip", args, i)
+		if err != nil {
+			return err
+		}
+		arrays[i] = arr
+		if length < 0 || len(arr.Elements) < length {
+			length = len(arr.Elements)
+		}
+	}
+	elements := []object.Object{}
+	for i := 0; i < length; i++ {
+		tuple := make([]object.Object, len(arrays))
+		for j, arr := range arrays {
+			tuple[j] = arr.Elements[i]
+		}
+		elements = append(elements, &object.Array{Elements: tuple})
+	}
+	return &object.Array{Elements: elements}
+}
+
+// maxRange is the largest array range builds, a mistaken bound should fail rather than exhaust memory.
+const maxRange = 1 << 24
+
+func rangeArray(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 3 {
+		return wrongArguments("range", len(args), "1 to 3")
+	}
+	bounds := []int64{0, 0, 1}
+	for i := range args {
+		n, err := integerArg("range", args, i)
+		if err != nil {
+			return err
+		}
+		bounds[i] = n
+	}
+	if len(args) == 1 {
+		bounds[0], bounds[1] = 0, bounds[0]
+	}
+	start, end, step := bounds[0], bounds[1], bounds[2]
+	if step == 0 {
+		return newError("`range` step cannot be 0")
+	}
+
+	// the count is worked out in uint64, the distance between two int64 bounds does not always fit in an int64.
+	count := uint64(0)
+	if step > 0 && end > start {
+		count = (uint64(end)-uint64(start)-1)/uint64(step) + 1
+	} else if step < 0 && start > end {
+		count = (uint64(start)-uint64(end)-1)/(0-uint64(step)) + 1
+	}
+	if count > maxRange {
+		return newError("`range` of %d elements is too large", count)
+	}
+	elements := make([]object.Object, count)
+	for i := range elements {
+		elements[i] = &object.Integer{Value: start + int64(i)*step}
+	}
+	return &object.Array{Elements: elements}
+}
+
+func flatten(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return wrongArguments("flatten", len(args), "1")
+	}
+	arr, err := arrayArg("flatten", args, 0)
+	if err != nil {
+		return err
+	}
+	elements := []object.Object{}
+	for _, el := range arr.Elements {
+		if inner, ok := el.(*object.Array); ok {
+			elements = append(elements, inner.Elements...)
+		} else {
+			elements = append(elements, el)
+		}
+	}
+	return &object.Array{Elements: elements}
+}
+
+// uniq keeps the first of the elements that are equal by objectsEqual, hashable ones are found by key.
+func uniq(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return wrongArguments("uniq", len(args), "1")
+	}
+	arr, err := arrayArg("uniq", args, 0)
+	if err != nil {
+		return err
+	}
+	seen := make(map[object.HashKey]bool)
+	elements := []object.Object{}
+outer:
+	for _, el := range arr.Elements {
+		if h, ok := el.(object.Hashable); ok {
+			key := h.HashKey()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		} else {
+			for _, kept := range elements {
+				if objectsEqual(el, kept) {
+					continue outer
+				}
+			}
+		}
+		elements = append(elements, el)
+	}
+	return &object.Array{Elements: elements}
+}
@@ -0,0 +1,93 @@
+package evaluator
+
+import (
+	"testing"
+
+	"go-interpreter-lexer/object"
+)
+
+// testInspect checks that input evaluates to a value printed as expected, or to an error with that message.
+func testInspect(t *testing.T, input string, expected string) {
+	t.Helper()
+	checkInspect(t, input, testEval(input), expected)
+}
+
+// testInspectWith is testInspect for a program evaluated with cfg.
+func testInspectWith(t *testing.T, cfg Config, input string, expected string) {
+	t.Helper()
+	checkInspect(t, input, testEvalIn(NewEnvironment(cfg), input), expected)
+}
+
+func checkInspect(t *testing.T, input string, evaluated object.Object, expected string) {
+	t.Helper()
+	if evaluated == nil {
+		t.Errorf("%q: evaluated to nothing", input)
+		return
+	}
+	got := evaluated.Inspect()
+	if errObj, ok := evaluated.(*object.Error); ok {
+		got = errObj.Message
+	}
+	if got != expected {
+		t.Errorf("%q: expected %q got %q", input, expected, got)
+	}
+}
+
+func TestCollectionBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let a = [1]; let b = push(a, 2, 3); [a, b]", "[[1], [1, 2, 3]]"},
+		{"rest([1, 2, 3])", "[2, 3]"},
+		{"rest([])", "null"},
+		{"concat([1], [], [2, 3])", "[1, 2, 3]"},
+		{"concat()", "[]"},
+		{"slice([1, 2, 3, 4], 1, 3)", "[2, 3]"},
+		{"slice([1, 2, 3, 4], -2)", "[3, 4]"},
+		{"slice([1, 2, 3], 2, 1)", "[]"},
+		{"slice([1, 2, 3], 0, 10)", "[1, 2, 3]"},
+		{"map([1, 2, 3], fn(x) { x * 2 })", "[2, 4, 6]"},
+		{"map([[1], [2, 3]], len)", "[1, 2]"},
+		{"filter([1, 2, 3, 4], fn(x) { x > 2 })", "[3, 4]"},
+		{"reduce([1, 2, 3], fn(acc, x) { acc + x })", "6"},
+		{"reduce([1, 2, 3], fn(acc, x) { push(acc, x * x) }, [])", "[1, 4, 9]"},
+		{"find([1, 2, 3], fn(x) { x > 1 })", "2"},
+		{"find([1, 2, 3], fn(x) { x > 5 })", "null"},
+		{"[any([1, 2], fn(x) { x > 1 }), any([], fn(x) { true })]", "[true, false]"},
+		{"[all([1, 2], fn(x) { x > 1 }), all([], fn(x) { false })]", "[false, true]"},
+		{"sort([3, 1, 2])", "[1, 2, 3]"},
+		{`sort(["b", "c", "a"])`, `[a, b, c]`},
+		{"sort([3, 1, 2], fn(a, b) { a > b })", "[3, 2, 1]"},
+		{"sort([[2, 1], [1, 2], [2, 0]], fn(a, b) { first(a) < first(b) })", "[[1, 2], [2, 1], [2, 0]]"},
+		{"let a = [2, 1]; sort(a); a", "[2, 1]"},
+		{"reverse([1, 2, 3])", "[3, 2, 1]"},
+		{`zip([1, 2, 3], ["a", "b"])`, "[[1, a], [2, b]]"},
+		{"range(4)", "[0, 1, 2, 3]"},
+		{"range(2, 5)", "[2, 3, 4]"},
+		{"range(10, 0, -3)", "[10, 7, 4, 1]"},
+		{"range(3, 1)", "[]"},
+		{"range(-9223372036854775807, 9223372036854775807, 9223372036854775807)", "[-9223372036854775807, 0]"},
+		{"range(0, 9223372036854775807, 4611686018427387904)", "[0, 4611686018427387904]"},
+		{"let min = -9223372036854775807 - 1; range(9223372036854775807, min, min)", "[9223372036854775807, -1]"},
+		{"flatten([1, [2, 3], [[4]]])", "[1, 2, 3, [4]]"},
+		{`uniq([1, 2, 1, "1", [1], [1], true, true])`, "[1, 2, 1, [1], true]"},
+
+		{"push(1, 2)", "argument 1 for `push` is suppose to be an array but got INTEGER"},
+		{"map([1], 1)", "argument 2 for `map` is suppose to be a function but got INTEGER"},
+		{"map([1])", "wrong number of arguments to `map` got 1, wanted 2"},
+		{"map([1, 2], fn(x) { x + true })", "type mismatch: INTEGER + BOOLEAN"},
+		{"map([1], fn(a, b) { a })", "wrong number of arguments: want=2, got=1"},
+		{"reduce([], fn(acc, x) { acc })", "`reduce` of an empty array without an initial value"},
+		{`sort([1, "a"])`, "`sort` without a comparator needs integers or strings, got INTEGER and STRING"},
+		{"sort([2, 1], fn(a, b) { a + true })", "type mismatch: INTEGER + BOOLEAN"},
+		{"range(1, 2, 0)", "`range` step cannot be 0"},
+		{"range(100000000)", "`range` of 100000000 elements is too large"},
+		{"range(-9223372036854775807, 9223372036854775807)", "`range` of 18446744073709551614 elements is too large"},
+		{`slice([1], "a")`, "argument 2 for `slice` is suppose to be an integer but got STRING"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, tt.input, tt.expected)
+	}
+}
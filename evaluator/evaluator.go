@@ -4,6 +4,8 @@ import (
 	"go-interpreter-lexer/ast"
 	"go-interpreter-lexer/object"
 	"fmt"
+	"math"
+	"math/big"
 )
 
 var(
@@ -12,7 +14,20 @@ var(
 	NULL = &object.Null{}
 )
 
+/*
+	MaxCallDepth is the number of nested function calls after which a call fails with an error instead of
+	growing the stack until the process dies. Each evaluation counts its own calls, see state.
+*/
+var MaxCallDepth = 10000
+
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	// the parser leaves out the expressions it could not parse.
+	if node == nil {
+		return newError("missing expression")
+	}
+	if hook := stateOf(env).hook; hook != nil {
+		hook.Eval(node, env)
+	}
 	switch node := node.(type){
 		case *ast.Program:
 			return evalProgram(node, env)
@@ -20,6 +35,10 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return Eval(node.Expression, env)
 		case *ast.IntegerLiteral:
 			return &object.Integer{Value: node.Value}
+		case *ast.BigIntLiteral:
+			return &object.BigInt{Value: node.Value}
+		case *ast.DecimalLiteral:
+			return &object.Decimal{Unscaled: node.Unscaled, Scale: node.Scale}
 		case *ast.Boolean:
 			return nativeBoolToBooleanObject(node.Value)
 		case *ast.PrefixExpression:
@@ -43,6 +62,9 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		case *ast.IfExpression:
 			return evalIfExpression(node, env)
 		case *ast.ReturnStatement:
+			if node.ReturnValue == nil {
+				return &object.ReturnValue{Value: NULL}
+			}
 			val := Eval(node.ReturnValue, env)
 			if isError(val){
 				return val
@@ -54,22 +76,24 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 					return val
 				}
 				env.Set(node.Name.Value, val)
+		case *ast.FunctionStatement:
+			declareFunction(node, env)
 		case *ast.Identifier:
 			return  evalIdentifier(node, env)
 		case *ast.FunctionLiteral:
-				params := node.Parameters
-				body := node.Body
-				return &object.Function{Parameters: params, Body: body, Env: env}
+				return newFunction(node, env)
 		case *ast.CallExpression:
 				function := Eval(node.Function, env)
 				if isError(function){
 					return function
 				}
-				args := evalExpressions(node.Arguments, env)
+				args := evalArguments(node.Arguments, env)
 				if len(args) == 1 && isError(args[0]){
 					return args[0]
 				}
-				return applyFunction(function, args)
+				return applyFunction(node, env, function, args)
+		case *ast.SpreadExpression:
+			return newError("... can only be used before the arguments of a call")
 		case *ast.StringLiteral:
 			return &object.String{Value: node.Value}
 		case *ast.ArrayLiteral:
@@ -89,6 +113,12 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 				return index
 			}
 			return evalIndexExpression(left, index)
+		case *ast.MemberExpression:
+			object := Eval(node.Object, env)
+			if isError(object){
+				return object
+			}
+			return evalMemberExpression(object, node.Property.Value)
 		case *ast.HashLiteral:
 			return evalHashLiteral(node,env)
 	}
@@ -96,9 +126,10 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 }
 
 func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object{
-	pairs := make(map[object.HashKey]object.HashPair)
+	hash := object.NewHash()
 
-	for keyNode, valueNode := range node.Pairs{
+	for _, keyNode := range node.Keys(){
+		valueNode := node.Pairs[keyNode]
 		key := Eval(keyNode, env)
 		if isError(key){
 			return key
@@ -112,10 +143,9 @@ func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Obje
 			return value
 		}
 
-		hashed := hashKey.HashKey()
-		pairs[hashed] = object.HashPair{Key: key, Value: value}
+		hash.Set(hashKey.HashKey(), object.HashPair{Key: key, Value: value})
 	}
-	return &object.Hash{Pairs: pairs}
+	return hash
 }
 
 
@@ -126,11 +156,38 @@ func evalIndexExpression(left, index object.Object) object.Object{
 		case left.Type() == object.HASH_OBJ:
 			returnVal := evalHashIndexExpression(left, index)
 			return returnVal
+		case left.Type() == object.STRING_OBJ && index.Type() == object.INTEGER_OBJ:
+			return evalStringIndexExpression(left, index)
 		default:
 			return newError("index operator not supported: %s", left.Type())
 	}
 }
 
+// evalStringIndexExpression returns the byte at the index as a string, like len it counts bytes.
+func evalStringIndexExpression(str, index object.Object) object.Object{
+	value := str.(*object.String).Value
+	idx := index.(*object.Integer).Value
+	if idx < 0 || idx >= int64(len(value)){
+		return NULL
+	}
+	return &object.String{Value: value[idx:idx+1]}
+}
+
+// evalMemberExpression looks up name in a module, or the string key name in a hash.
+func evalMemberExpression(obj object.Object, name string) object.Object{
+	switch obj := obj.(type){
+		case *object.Module:
+			if member, ok := obj.Members[name]; ok{
+				return member
+			}
+			return newError("module %s has no member %s", obj.Name, name)
+		case *object.Hash:
+			return evalHashIndexExpression(obj, &object.String{Value: name})
+		default:
+			return newError("member access not supported: %s", obj.Type())
+	}
+}
+
 func evalHashIndexExpression(hash, index object.Object) object.Object{
  	hashObject := hash.(*object.Hash)
  	key, ok := index.(object.Hashable)
@@ -155,24 +212,134 @@ func evalArrayIndexExpression(array, index object.Object) object.Object{
 	return arrayObject.Elements[idx]
 }
 
-func applyFunction(fn object.Object, args []object.Object) object.Object{
+/*
+	applyFunction calls fn with args from env, the environment of the caller that builtins get. call is the
+	call expression being evaluated, nil when a builtin calls back into a function.
+*/
+func applyFunction(call *ast.CallExpression, env *object.Environment, fn object.Object, args []object.Object) object.Object{
+	if hook := stateOf(env).hook; hook != nil {
+		hook.Call(call, fn, args)
+		result := callFunction(env, fn, args)
+		hook.Return(call, fn, result)
+		return result
+	}
+	return callFunction(env, fn, args)
+}
+
+/*
+	ApplyFunction calls fn with args from outside a program as if it was called in env, hooks see a call
+	without a call expression.
+*/
+func ApplyFunction(env *object.Environment, fn object.Object, args ...object.Object) object.Object{
+	return applyFunction(nil, env, fn, args)
+}
+
+func callFunction(env *object.Environment, fn object.Object, args []object.Object) object.Object{
 	switch function := fn.(type){
 		case *object.Function:
-			extendedEnv := extendedFunctionEnv(function,args)
+			st := stateOf(function.Env)
+			if st.depth >= MaxCallDepth{
+				return newError("maximum call depth of %d exceeded", MaxCallDepth)
+			}
+			st.depth++
+			defer func(){ st.depth-- }()
+			extendedEnv, err := extendedFunctionEnv(function,args)
+			if err != nil {
+				return err
+			}
+			declareFunctions(function.Body.Statements, extendedEnv)
 			evaluated := Eval(function.Body,extendedEnv)
 			return unwrapReturnValue(evaluated)
 		case *object.Builtin:
-			return function.Fn(args ...)
+			return function.Fn(env, args ...)
 	}
 	return newError("not a valid function: %s", fn.Type())
 }
 
-func extendedFunctionEnv(fn *object.Function, args []object.Object) *object.Environment{
+/*
+	extendedFunctionEnv binds the parameters of fn to args in a new environment. Parameters left without an
+	argument take their default value, evaluated in the new environment so it can use the parameters before
+	it, and a rest parameter takes the remaining arguments as an array.
+*/
+func extendedFunctionEnv(fn *object.Function, args []object.Object) (*object.Environment, *object.Error){
 	env := object.NewEnclosedEnvironment(fn.Env)
+	required := 0
+	for i := range fn.Parameters{
+		if (i >= len(fn.Defaults) || fn.Defaults[i] == nil) && !(fn.Rest && i == len(fn.Parameters)-1) {
+			required = i + 1
+		}
+	}
+	params := len(fn.Parameters)
+	if fn.Rest {
+		params--
+	}
+	if len(args) < required || !fn.Rest && len(args) > params{
+		if fn.Name != "" {
+			return nil, newError("wrong number of arguments to %s: want=%s, got=%d", fn.Name, arity(required, params, fn.Rest), len(args))
+		}
+		return nil, newError("wrong number of arguments: want=%s, got=%d", arity(required, params, fn.Rest), len(args))
+	}
+
 	for paramIdx, param := range fn.Parameters{
-		env.Set(param.Value, args[paramIdx])
+		switch {
+			case fn.Rest && paramIdx == params:
+				rest := []object.Object{}
+				if paramIdx < len(args){
+					rest = append(rest, args[paramIdx:]...)
+				}
+				env.Set(param.Value, &object.Array{Elements: rest})
+			case paramIdx < len(args):
+				env.Set(param.Value, args[paramIdx])
+			default:
+				val := Eval(fn.Defaults[paramIdx], env)
+				if errObj, ok := val.(*object.Error); ok {
+					return nil, errObj
+				}
+				env.Set(param.Value, val)
+		}
+	}
+	return env, nil
+}
+
+// arity describes the number of arguments a function takes, 2, 1 to 2 or at least 1.
+func arity(required, params int, rest bool) string{
+	switch {
+		case rest:
+			return fmt.Sprintf("at least %d", required)
+		case required == params:
+			return fmt.Sprintf("%d", params)
+		default:
+			return fmt.Sprintf("%d to %d", required, params)
+	}
+}
+
+// evalArguments evaluates the arguments of a call, the elements of a spread array become separate arguments.
+func evalArguments(exps []ast.Expression, env *object.Environment) []object.Object{
+	var result []object.Object
+	for _, e := range exps{
+		spread, ok := e.(*ast.SpreadExpression)
+		if !ok {
+			evaluated := Eval(e, env)
+			if isError(evaluated){
+				return []object.Object{evaluated}
+			}
+			result = append(result, evaluated)
+			continue
+		}
+		if hook := stateOf(env).hook; hook != nil {
+			hook.Eval(spread, env)
+		}
+		evaluated := Eval(spread.Value, env)
+		if isError(evaluated){
+			return []object.Object{evaluated}
+		}
+		arr, ok := evaluated.(*object.Array)
+		if !ok {
+			return []object.Object{newError("cannot spread %s, only arrays can be spread", evaluated.Type())}
+		}
+		result = append(result, arr.Elements...)
 	}
-	return env
+	return result
 }
 
 func unwrapReturnValue(obj object.Object) object.Object{
@@ -197,7 +364,13 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 }
 
 func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object{
-
+	// the resolved depth is tried first, the binding may not exist there yet (a let in a branch that
+	// was not taken) in which case the whole chain is searched as usual.
+	if node.Depth > 0 {
+		if val, ok := env.GetAt(node.Depth, node.Value); ok {
+			return val
+		}
+	}
 	val, ok := env.Get(node.Value)
 	if ok {
 		return val
@@ -205,8 +378,11 @@ func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object
 	if builtin, ok := builtins[node.Value]; ok{
 		return builtin
 	}
+	if module, ok := modules[node.Value]; ok{
+		return module
+	}
 
-	return newError("identifier not found: "+node.Value)
+	return newError("identifier not found: %s", node.Value)
 }
 
 func evalBlockStatements(block *ast.BlockStatement, env *object.Environment) object.Object{
@@ -219,11 +395,39 @@ func evalBlockStatements(block *ast.BlockStatement, env *object.Environment) obj
 			}
 		}
 	}
+	// a block is an expression, an empty one or one ending in a let is null.
+	if result == nil {
+		return NULL
+	}
 	return result
 }
 
+/*
+	declareFunctions binds the functions declared by stmts before any of them run, which lets a function call
+	one declared after it. Declarations nested in blocks are bound when they are reached.
+*/
+func declareFunctions(stmts []ast.Statement, env *object.Environment){
+	for _, stmt := range stmts{
+		if fs, ok := stmt.(*ast.FunctionStatement); ok {
+			declareFunction(fs, env)
+		}
+	}
+}
+
+func declareFunction(fs *ast.FunctionStatement, env *object.Environment){
+	if fs.Name == nil || fs.Function == nil {
+		return
+	}
+	env.Set(fs.Name.Value, newFunction(fs.Function, env))
+}
+
+func newFunction(fl *ast.FunctionLiteral, env *object.Environment) *object.Function{
+	return &object.Function{Name: fl.Name, Parameters: fl.Parameters, Defaults: fl.Defaults, Rest: fl.Rest, Body: fl.Body, Env: env}
+}
+
 func evalProgram(program *ast.Program, env *object.Environment) object.Object{
 	var result object.Object
+	declareFunctions(program.Statements, env)
 	for _, stmt := range program.Statements{
 		result = Eval(stmt, env)
 		switch result := result.(type){
@@ -240,6 +444,9 @@ func evalProgram(program *ast.Program, env *object.Environment) object.Object{
 func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Object{
 	//fmt.Printf("condition %s\n", ie.Condition.String())
 	condition := Eval(ie.Condition, env)
+	if isError(condition){
+		return condition
+	}
 
 	if isTruthy(condition) {
 		//fmt.Printf("consequence type: %T value: %s\n",ie.Consequence, ie.Consequence.String())
@@ -280,27 +487,42 @@ func evalInfixExpression(operator string, left object.Object, right object.Objec
 	switch {
 		case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		  return evalIntegerInfixExpression(operator, left, right)
+		case isNumber(left) && isNumber(right):
+			return evalNumberInfixExpression(operator, left, right)
+		case isTemporal(left) || isTemporal(right):
+			return evalTimeInfixExpression(operator, left, right)
+		case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
+			return evalStringInfixExpression(operator, left, right)
 		case operator == "==":
 			return nativeBoolToBooleanObject(left == right)
 		case operator == "!=" :
 			return nativeBoolToBooleanObject(left != right)
 		case left.Type() != right.Type():
 		  	return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
-		case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
-			return evalStringInfixExpression(operator, left, right)
 		default :
 			return newError("unknown operator: %s %s %s",left.Type(), operator, right.Type())
 	}
 }
 
+// evalStringInfixExpression concatenates strings and compares them by value, byte by byte.
 func evalStringInfixExpression(operator string, left, right object.Object) object.Object{
-	if operator != "+"{
-		return newError("unknown operator: %s %s %s", left.Type(), operator ,right.Type())
-	}
 	leftVal := left.(*object.String).Value
 	rightVal := right.(*object.String).Value
 
-	return &object.String{Value: leftVal + rightVal}
+	switch operator{
+		case "+":
+			return &object.String{Value: leftVal + rightVal}
+		case "==":
+			return nativeBoolToBooleanObject(leftVal == rightVal)
+		case "!=":
+			return nativeBoolToBooleanObject(leftVal != rightVal)
+		case "<":
+			return nativeBoolToBooleanObject(leftVal < rightVal)
+		case ">":
+			return nativeBoolToBooleanObject(leftVal > rightVal)
+		default:
+			return newError("unknown operator: %s %s %s", left.Type(), operator ,right.Type())
+	}
 }
 
 func evalIntegerInfixExpression(operator string, leftVal object.Object, rightVal object.Object) object.Object{
@@ -308,18 +530,26 @@ func evalIntegerInfixExpression(operator string, leftVal object.Object, rightVal
 	right := rightVal.(*object.Integer).Value
 
 	switch operator{
-		case "+":
-			return &object.Integer{Value: (left + right)}
-		case "-" :
-			return &object.Integer{Value: (left - right)}
-		case "*" :
-			return &object.Integer{Value: (left * right)}
-		case "/":
+		case "+", "-", "*":
+			result, ok := checkedArithmetic(operator, left, right)
+			if !ok {
+				return evalBigIntInfixExpression(operator, big.NewInt(left), big.NewInt(right))
+			}
+			return &object.Integer{Value: result}
+		case "/", "%":
 			if right == 0{
-				return NULL
-			}else {
-				return &object.Integer{Value: (left / right)}
+				return newError("division by zero: %d %s %d", left, operator, right)
+			}
+			if left == math.MinInt64 && right == -1{
+				if operator == "%"{
+					return &object.Integer{Value: 0}
+				}
+				return evalBigIntInfixExpression(operator, big.NewInt(left), big.NewInt(right))
 			}
+			if operator == "%"{
+				return &object.Integer{Value: (left % right)}
+			}
+			return &object.Integer{Value: (left / right)}
 		case ">":
 			return nativeBoolToBooleanObject(left > right)
 		case "<":
@@ -329,7 +559,7 @@ func evalIntegerInfixExpression(operator string, leftVal object.Object, rightVal
 		case "!=":
 			return nativeBoolToBooleanObject(left != right)
 		default:
-			return newError("unknown operator: %s %s", leftVal.Type(), operator, rightVal.Type())
+			return newError("unknown operator: %s %s %s", leftVal.Type(), operator, rightVal.Type())
 	}
 }
 
@@ -348,9 +578,12 @@ func evalBangOperatorExpression(right object.Object) object.Object{
 
 func evalMinusOperatorExpression(right object.Object) object.Object{
 	if right.Type() != object.INTEGER_OBJ{
-		return newError("unknown operator: -%s", right.Type())
+		return negateNumber(right)
 	}
 	value := right.(*object.Integer).Value
+	if value == math.MinInt64{
+		return &object.BigInt{Value: new(big.Int).Neg(big.NewInt(value))}
+	}
 	return &object.Integer{Value: -value}
 }
 
@@ -1,10 +1,12 @@
 package evaluator
 
 import (
+	"sync"
 	"testing"
 	"go-interpreter-lexer/object"
 	"go-interpreter-lexer/lexer"
 	"go-interpreter-lexer/parser"
+	"go-interpreter-lexer/resolver"
 )
 
 func TestEvaluateIntegerExpression(t *testing.T){
@@ -23,6 +25,10 @@ func TestEvaluateIntegerExpression(t *testing.T){
 		{"20 + 2 * -10", 0},
 		{"50 / 2 * 2 + 10 ", 60},
 		{"2 * (5 + 10)", 30},
+		{"17 % 5", 2},
+		{"-17 % 5", -2},
+		{"2 + 10 % 4 * 3", 8},
+		{"-9223372036854775807 - 1", -9223372036854775807 - 1},
 	}
 
 	for _, tt := range tests{
@@ -65,10 +71,14 @@ func TestEvaluateBoolExpressions(t *testing.T){
 }
 
 func testEval (input string) object.Object{
+	return testEvalIn(object.NewEnvironment(), input)
+}
+
+// testEvalIn evaluates input in env, an environment from NewEnvironment for the tests of what the host sets up.
+func testEvalIn(env *object.Environment, input string) object.Object{
 	l := lexer.New(input)
 	p := parser.New(l)
 	program := p.ParseProgram()
-	env := object.NewEnvironment()
 
 	return Eval(program, env)
 }
@@ -159,6 +169,7 @@ func TestReturnStatements(t *testing.T){
 		{ "return 10; 9; ", 10},
 		{ "return 2 * 5; 9;", 10},
 		{"9; return 2*5; 9;", 10},
+		{"return 10", 10},
 		{`if (10>1) {
 		if ( 10>1) {
 			return 10;
@@ -190,6 +201,16 @@ func TestErrorHandling (t *testing.T){
 		{"foobar", "identifier not found: foobar"},
 		{`"Hello"-"World"`, "unknown operator: STRING - STRING"},
 		{`{"name": "Monkey"}[fn(x) {x}];`, "unusable as hash key: FUNCTION"},
+		{"let f = fn(a, b) { a }; f(1);", "wrong number of arguments to f: want=2, got=1"},
+		{"fn(a, b) { a }(1);", "wrong number of arguments: want=2, got=1"},
+		{"let f = fn() { f() }; f();", "maximum call depth of 10000 exceeded"},
+		{"-if (false) { 1 }", "unknown operator: -NULL"},
+		{"1 + ;", "missing expression"},
+		{"let x = [1, 2][;", "missing expression"},
+		{"1 / 0", "division by zero: 1 / 0"},
+		{"5 % 0", "division by zero: 5 % 0"},
+		{"let z = 0; if (10 / z) { 1 } else { 2 }", "division by zero: 10 / 0"},
+		{"if (missing) { 1 }", "identifier not found: missing"},
 	}
 
 	for _, tt :=range tests{
@@ -205,6 +226,27 @@ func TestErrorHandling (t *testing.T){
 	}
 }
 
+func TestCallDepthPerEvaluation(t *testing.T){
+	defer func(limit int){ MaxCallDepth = limit }(MaxCallDepth)
+	MaxCallDepth = 100
+
+	// each evaluation goes 90 calls deep, they only fit under the limit if they are counted apart.
+	input := "let f = fn(n) { if (n == 0) { 0 } else { f(n - 1) } }; f(89); f(89); f(89)"
+	var wg sync.WaitGroup
+	results := make([]object.Object, 8)
+	for i := range results{
+		wg.Add(1)
+		go func(i int){
+			defer wg.Done()
+			results[i] = testEval(input)
+		}(i)
+	}
+	wg.Wait()
+	for _, result := range results{
+		testIntegerObject(t, result, 0)
+	}
+}
+
 func TestLetStatements(t *testing.T){
 	tests := []struct{
 		input string
@@ -263,6 +305,78 @@ func TestFunctionApplication(t *testing.T){
 	}
 }
 
+func TestFunctionDeclarations(t *testing.T){
+	tests := []struct{
+		input string
+		expected int64
+	}{
+		{"fn add(a, b) { a + b } add(2, 3);", 5},
+		{"let x = double(4); fn double(n) { n * 2 }; x;", 8},
+		{`fn isEven(n) { if (n == 0) { true } else { isOdd(n - 1) } }
+		fn isOdd(n) { if (n == 0) { false } else { isEven(n - 1) } }
+		if (isEven(10)) { 1 } else { 0 }`, 1},
+		{"fn outer() { let x = inner() + 1; fn inner() { 41 } x } outer();", 42},
+		{"fn f() { 1 } let g = f; g();", 1},
+	}
+	for _, tt := range tests{
+		testIntegerObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestFunctionNames(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"fn add(a, b) { a + b } add;", "fn add(a, b){\n(a + b)}\n"},
+		{"let inc = fn(x) { x + 1 }; inc;", "fn inc(x){\n(x + 1)}\n"},
+		{"fn(x) { x };", "fn(x){\nx}\n"},
+	}
+	for _, tt := range tests{
+		evaluated := testEval(tt.input)
+		if evaluated == nil || evaluated.Inspect() != tt.expected {
+			t.Errorf("%q: expected %q got %v", tt.input, tt.expected, evaluated)
+		}
+	}
+}
+
+func TestDefaultAndRestParameters(t *testing.T){
+	tests := []struct{
+		input string
+		expected interface{}
+	}{
+		{"let f = fn(a, b = 10) { a + b }; f(1);", 11},
+		{"let f = fn(a, b = 10) { a + b }; f(1, 2);", 3},
+		{"let f = fn(a, b = a * 2) { a + b }; f(3);", 9},
+		{"let f = fn(first, ...rest) { len(rest) }; f(1);", 0},
+		{"let f = fn(first, ...rest) { rest[1] }; f(1, 2, 3);", 3},
+		{"let f = fn(a, b, c) { a * 100 + b * 10 + c }; f(...[1, 2, 3]);", 123},
+		{"let f = fn(a, b, c) { a * 100 + b * 10 + c }; f(1, ...[2], ...[3]);", 123},
+		{"let f = fn(...xs) { len(xs) }; f(...[], 1, ...[2, 3]);", 3},
+		{"let f = fn(a, b = 10) { a }; f();", "wrong number of arguments to f: want=1 to 2, got=0"},
+		{"fn(a, b) { a }(1, 2, 3);", "wrong number of arguments: want=2, got=3"},
+		{"let f = fn(a, ...rest) { a }; f();", "wrong number of arguments to f: want=at least 1, got=0"},
+		{"let f = fn(a = x) { a }; f();", "identifier not found: x"},
+		{"let f = fn(a) { a }; f(...1);", "cannot spread INTEGER, only arrays can be spread"},
+	}
+	for _, tt := range tests{
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type){
+			case int:
+				testIntegerObject(t, evaluated, int64(expected))
+			case string:
+				errObj, ok := evaluated.(*object.Error)
+				if !ok {
+					t.Errorf("%q: expected an error got %T(%+v)", tt.input, evaluated, evaluated)
+					continue
+				}
+				if errObj.Message != expected {
+					t.Errorf("%q: wrong error message. expected=%q, got=%q", tt.input, expected, errObj.Message)
+				}
+		}
+	}
+}
+
 func TestClosures(t *testing.T){
 	input := `
 	let newAdder = fn(x) {
@@ -323,7 +437,7 @@ func TestBuiltInFunction(t *testing.T){
 		case string:
 			errObj, ok := evaluated.(*object.Error)
 			if !ok{
-				t.Errorf("object is not error got.%T(+v)", evaluated, evaluated)
+				t.Errorf("object is not error got.%T(%+v)", evaluated, evaluated)
 				continue
 			}
 			if errObj.Message != expected{
@@ -437,4 +551,29 @@ func TestHashIndexExpression(t *testing.T){
 			testNullObject(t, evaluated)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestResolvedIdentifiers(t *testing.T){
+	tests := []struct{
+		input string
+		expected int64
+	}{
+		{"let x = 1; let f = fn() { let y = x + 1; let x = 10; x + y }; f();", 12},
+		{"let x = 1; let f = fn(c) { if (c) { let x = 5; }; x }; f(false);", 1},
+		{"let x = 1; let f = fn(c) { if (c) { let x = 5; }; x }; f(true);", 5},
+		{"let a = 2; let f = fn(b) { fn(c) { a * b * c } }; f(3)(4);", 24},
+	}
+
+	for _, tt := range tests{
+		program := parser.New(lexer.New(tt.input)).ParseProgram()
+		r := resolver.New(BuiltinNames())
+		r.Resolve(program)
+		testIntegerObject(t, Eval(program, object.NewEnvironment()), tt.expected)
+	}
+}
+
+func TestTypeAnnotationsIgnored(t *testing.T){
+	input := `let add = fn(a: int, b: int): int { a + b };
+	let xs: [int] = [1, 2];
+	add(xs[0], xs[1]);`
+	testIntegerObject(t, testEval(input), 3)
+}
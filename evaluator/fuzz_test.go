@@ -0,0 +1,41 @@
+package evaluator
+
+import (
+	"os"
+	"testing"
+
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/object"
+	"go-interpreter-lexer/parser"
+)
+
+var fuzzSeeds = []string{
+	`let add = fn(a, b) { a + b; }; add(1, 2 * 3);`,
+	`let fib = fn(n) { if (n < 2) { return n; } fib(n - 1) + fib(n - 2) }; fib(5);`,
+	`{"a": 1, true: [2]}["a"] == !len("x")`,
+	`let f = fn(a, b) { a }; f(1);`,
+	`let f = fn() { f() }; f();`,
+	`-if (true) {}`,
+	`puts(fn() { let x = 1; }())`,
+	`a[`,
+	`first([]) + last([1])[0]`,
+}
+
+// FuzzEval checks that evaluating any program, even one the parser reported errors for, does not panic.
+func FuzzEval(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add(s)
+	}
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		f.Fatal(err)
+	}
+	defer devNull.Close()
+	f.Fuzz(func(t *testing.T, input string) {
+		program := parser.New(lexer.New(input)).ParseProgram()
+		stdout := os.Stdout
+		os.Stdout = devNull
+		defer func() { os.Stdout = stdout }()
+		Eval(program, object.NewEnvironment())
+	})
+}
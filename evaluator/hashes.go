@@ -0,0 +1,152 @@
+package evaluator
+
+import "go-interpreter-lexer/object"
+
+// the hash builtins list pairs in insertion order and return new hashes rather than changing their argument.
+func init() {
+	for name, b := range map[string]*object.Builtin{
+		"keys": {Fn: keys,
+			Doc: "keys(h) returns an array of the keys of a hash in the order they were added.",
+		},
+		"values": {Fn: values,
+			Doc: "values(h) returns an array of the values of a hash in the order their keys were added.",
+		},
+		"entries": {Fn: entries,
+			Doc: "entries(h) returns an array of [key, value] arrays in the order the keys were added.",
+		},
+		"has": {Fn: has,
+			Doc: "has(h, key) reports whether a hash has a pair with the key.",
+		},
+		"delete": {Fn: deleteKeys,
+			Doc: "delete(h, keys...) returns a new hash without the pairs of the keys.",
+		},
+		"merge": {Fn: merge,
+			Doc: "merge(hashes...) returns a new hash with the pairs of every hash, a key keeps the value of the last hash that has it.",
+		},
+	} {
+		builtins[name] = b
+	}
+}
+
+// hashArg returns the argument at i if it is a hash.
+func hashArg(name string, args []object.Object, i int) (*object.Hash, *object.Error) {
+	h, ok := args[i].(*object.Hash)
+	if !ok {
+		return nil, newError("argument %d for `%s` is suppose to be a hash but got %s", i+1, name, args[i].Type())
+	}
+	return h, nil
+}
+
+func hashKeyArg(args []object.Object, i int) (object.HashKey, *object.Error) {
+	key, ok := args[i].(object.Hashable)
+	if !ok {
+		return object.HashKey{}, newError("unusable as hash key: %s", args[i].Type())
+	}
+	return key.HashKey(), nil
+}
+
+// hashPairs checks the single hash argument of keys, values and entries and returns its pairs.
+func hashPairs(name string, args []object.Object) ([]object.HashPair, *object.Error) {
+	if len(args) != 1 {
+		return nil, wrongArguments(name, len(args), "1")
+	}
+	h, err := hashArg(name, args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return h.Ordered(), nil
+}
+
+func keys(env *object.Environment, args ...object.Object) object.Object {
+	pairs, err := hashPairs("keys", args)
+	if err != nil {
+		return err
+	}
+	elements := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		elements[i] = pair.Key
+	}
+	return &object.Array{Elements: elements}
+}
+
+func values(env *object.Environment, args ...object.Object) object.Object {
+	pairs, err := hashPairs("values", args)
+	if err != nil {
+		return err
+	}
+	elements := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		elements[i] = pair.Value
+	}
+	return &object.Array{Elements: elements}
+}
+
+func entries(env *object.Environment, args ...object.Object) object.Object {
+	pairs, err := hashPairs("entries", args)
+	if err != nil {
+		return err
+	}
+	elements := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		elements[i] = &object.Array{Elements: []object.Object{pair.Key, pair.Value}}
+	}
+	return &object.Array{Elements: elements}
+}
+
+func has(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return wrongArguments("has", len(args), "2")
+	}
+	h, err := hashArg("has", args, 0)
+	if err != nil {
+		return err
+	}
+	key, err := hashKeyArg(args, 1)
+	if err != nil {
+		return err
+	}
+	_, ok := h.Pairs[key]
+	return nativeBoolToBooleanObject(ok)
+}
+
+// copyHash returns a hash with the pairs of h in the same order.
+func copyHash(h *object.Hash) *object.Hash {
+	c := object.NewHash()
+	for _, pair := range h.Ordered() {
+		c.Set(pair.Key.(object.Hashable).HashKey(), pair)
+	}
+	return c
+}
+
+func deleteKeys(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) < 1 {
+		return wrongArguments("delete", len(args), "at least 1")
+	}
+	h, err := hashArg("delete", args, 0)
+	if err != nil {
+		return err
+	}
+	result := copyHash(h)
+	for i := range args[1:] {
+		key, err := hashKeyArg(args, i+1)
+		if err != nil {
+			return err
+		}
+		result.Delete(key)
+	}
+	return result
+}
+
+func merge(env *object.Environment, args ...object.Object) object.Object {
+	result := object.NewHash()
+	for i := range args {
+		h, err := hashArg("merge", args, i)
+		if err != nil {
+			return err
+		}
+		for _, pair := range h.Ordered() {
+			result.Set(pair.Key.(object.Hashable).HashKey(), pair)
+		}
+	}
+	return result
+}
@@ -0,0 +1,32 @@
+package evaluator
+
+import "testing"
+
+func TestHashBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"b": 1, "a": 2, 3: 3, true: 4}`, "{b:1, a:2, 3:3, true:4}"},
+		{`keys({"b": 1, "a": 2, "c": 3})`, "[b, a, c]"},
+		{`values({"b": 1, "a": 2, "c": 3})`, "[1, 2, 3]"},
+		{`entries({"b": 1, "a": 2})`, "[[b, 1], [a, 2]]"},
+		{`keys({})`, "[]"},
+		{`[has({"a": 1}, "a"), has({"a": 1}, "b"), has({1: if (false) { 1 }}, 1)]`, "[true, false, true]"},
+		{`let h = {"a": 1, "b": 2, "c": 3}; [delete(h, "a", "c", "x"), h]`, "[{b:2}, {a:1, b:2, c:3}]"},
+		{`delete({"a": 1, "b": 2}, "a")["b"]`, "2"},
+		{`merge({"a": 1, "b": 2}, {"c": 3, "a": 4})`, "{a:4, b:2, c:3}"},
+		{`merge()`, "{}"},
+		{`keys(merge(delete({"a": 1, "b": 2}, "a"), {"a": 3}))`, "[b, a]"},
+
+		{`keys([1])`, "argument 1 for `keys` is suppose to be a hash but got ARRAY"},
+		{`has({}, [1])`, "unusable as hash key: ARRAY"},
+		{`delete({}, fn() {})`, "unusable as hash key: FUNCTION"},
+		{`merge({}, 1)`, "argument 2 for `merge` is suppose to be a hash but got INTEGER"},
+		{`values()`, "wrong number of arguments to `values` got 0, wanted 1"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, tt.input, tt.expected)
+	}
+}
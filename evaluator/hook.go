@@ -0,0 +1,80 @@
+package evaluator
+
+import (
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/object"
+)
+
+/*
+	Hook is notified by the evaluator as a program runs, debuggers, tracers and coverage tools are built on
+	it. An evaluation has at most one hook, the Hook of its Config or the one installed with SetHook.
+*/
+type Hook interface {
+	// Eval is called before node is evaluated in env.
+	Eval(node ast.Node, env *object.Environment)
+	// Call is called before fn is applied to args. call is nil when the function is called by a builtin.
+	Call(call *ast.CallExpression, fn object.Object, args []object.Object)
+	// Return is called with the result of the call, an *object.Error if it failed.
+	Return(call *ast.CallExpression, fn object.Object, result object.Object)
+}
+
+/*
+	SetHook installs h in the evaluation env belongs to and returns the hook it replaces, nil removes the
+	hook. Other evaluations keep theirs.
+*/
+func SetHook(env *object.Environment, h Hook) Hook {
+	st := stateOf(env)
+	prev := st.hook
+	st.hook = h
+	return prev
+}
+
+// MultiHook returns a hook that passes every event on to each of hooks in turn.
+func MultiHook(hooks ...Hook) Hook {
+	return multiHook(hooks)
+}
+
+type multiHook []Hook
+
+func (m multiHook) Eval(node ast.Node, env *object.Environment) {
+	for _, h := range m {
+		h.Eval(node, env)
+	}
+}
+
+func (m multiHook) Call(call *ast.CallExpression, fn object.Object, args []object.Object) {
+	for _, h := range m {
+		h.Call(call, fn, args)
+	}
+}
+
+func (m multiHook) Return(call *ast.CallExpression, fn object.Object, result object.Object) {
+	for _, h := range m {
+		h.Return(call, fn, result)
+	}
+}
+
+/*
+	CallName is the name hooks report a call under: the identifier the function was called through, the name
+	the function was declared with, or for other calls of an anonymous function fn@ and the position of its body.
+*/
+func CallName(call *ast.CallExpression, fn object.Object) string {
+	if call != nil {
+		if ident, ok := call.Function.(*ast.Identifier); ok {
+			return ident.Value
+		}
+	}
+	switch fn := fn.(type) {
+	case *object.Function:
+		if fn.Name != "" {
+			return fn.Name
+		}
+		if fn.Body != nil {
+			return "fn@" + fn.Body.Token.Pos.String()
+		}
+		return "fn"
+	case *object.Builtin:
+		return "builtin"
+	}
+	return string(fn.Type())
+}
@@ -0,0 +1,35 @@
+package evaluator
+
+import (
+	"testing"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/object"
+)
+
+// callCounter is a hook counting the calls it sees.
+type callCounter struct{ calls int }
+
+func (c *callCounter) Eval(node ast.Node, env *object.Environment) {}
+func (c *callCounter) Call(call *ast.CallExpression, fn object.Object, args []object.Object) {
+	c.calls++
+}
+func (c *callCounter) Return(call *ast.CallExpression, fn object.Object, result object.Object) {}
+
+func TestHookPerEnvironment(t *testing.T) {
+	counter := &callCounter{}
+	hooked := NewEnvironment(Config{Hook: counter})
+	testEvalIn(hooked, "let f = fn(x) { x }; f(1); f(2)")
+	testEval("let f = fn(x) { x }; f(1); f(2); f(3)")
+	if counter.calls != 2 {
+		t.Errorf("the hook saw %d calls, want the 2 of its own environment", counter.calls)
+	}
+
+	if prev := SetHook(hooked, nil); prev != counter {
+		t.Errorf("SetHook replaced %v", prev)
+	}
+	testEvalIn(hooked, "f(3)")
+	if counter.calls != 2 {
+		t.Errorf("the removed hook saw %d calls", counter.calls)
+	}
+}
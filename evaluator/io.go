@@ -0,0 +1,186 @@
+package evaluator
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"go-interpreter-lexer/object"
+)
+
+// the io module reads and writes files, standard input and output through the IO of the Config the host set.
+func init() {
+	modules["io"] = &object.Module{Name: "io",
+		Doc: "io reads and writes files and the standard input and output the host allows, file access is denied unless the host gives a file system.",
+		Members: map[string]*object.Builtin{
+			"readFile": {Fn: readFile,
+				Doc: "io.readFile(path) returns the content of a file as a string.",
+			},
+			"writeFile": {Fn: writeFile,
+				Doc: "io.writeFile(path, s) writes s to a file, replacing what it held, and returns null.",
+			},
+			"listDir": {Fn: listDir,
+				Doc: "io.listDir(path) returns the sorted names in a directory, directories end with /. path is optional and is the current directory by default.",
+			},
+			"readLine": {Fn: readLine,
+				Doc: "io.readLine() returns the next line of the standard input without its line ending, null at the end of the input.",
+			},
+			"print": {Fn: printValues,
+				Doc: "io.print(args...) writes the arguments one after another without a newline and returns null.",
+			},
+		},
+	}
+}
+
+/*
+	IO is where programs read and write, puts and io.print write to Stdout and io.readLine reads from Stdin.
+	Files is the file system of io.readFile and io.listDir, io.writeFile also needs it to be a WriteFS. A nil
+	Stdout discards what is written, a nil Stdin is empty and nil Files denies file access, a host running
+	programs for untrusted users leaves Files nil or gives RootFS of a directory of their own.
+*/
+type IO struct {
+	Stdout io.Writer
+	Stdin  io.Reader
+	Files  fs.FS
+}
+
+// WriteFS is a file system io.writeFile can write to.
+type WriteFS interface {
+	fs.FS
+	WriteFile(name string, data []byte) error
+}
+
+// HostFS returns the files of the machine, names are paths as the operating system takes them.
+func HostFS() WriteFS {
+	return hostFS{}
+}
+
+type hostFS struct{}
+
+func (hostFS) Open(name string) (fs.File, error)          { return os.Open(name) }
+func (hostFS) ReadFile(name string) ([]byte, error)       { return os.ReadFile(name) }
+func (hostFS) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+func (hostFS) WriteFile(name string, data []byte) error   { return os.WriteFile(name, data, 0644) }
+
+/*
+	RootFS returns the files under dir, names are relative to dir and neither .. nor a symbolic link can
+	reach outside it.
+*/
+func RootFS(dir string) (WriteFS, error) {
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	return rootFS{FS: root.FS(), root: root}, nil
+}
+
+type rootFS struct {
+	fs.FS
+	root *os.Root
+}
+
+func (r rootFS) WriteFile(name string, data []byte) error { return r.root.WriteFile(name, data, 0644) }
+
+func pathArg(name string, args []object.Object, i int) (string, *object.Error) {
+	s, ok := args[i].(*object.String)
+	if !ok {
+		return "", newError("argument %d for `io.%s` is suppose to be a string but got %s", i+1, name, args[i].Type())
+	}
+	return s.Value, nil
+}
+
+func readFile(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return wrongArguments("io.readFile", len(args), "1")
+	}
+	path, err := pathArg("readFile", args, 0)
+	if err != nil {
+		return err
+	}
+	files := stateOf(env).io.Files
+	if files == nil {
+		return newError("io.readFile: file access is not allowed")
+	}
+	data, readErr := fs.ReadFile(files, path)
+	if readErr != nil {
+		return newError("io.readFile: %s", readErr)
+	}
+	return &object.String{Value: string(data)}
+}
+
+func writeFile(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return wrongArguments("io.writeFile", len(args), "2")
+	}
+	path, err := pathArg("writeFile", args, 0)
+	if err != nil {
+		return err
+	}
+	content, ok := args[1].(*object.String)
+	if !ok {
+		return newError("argument 2 for `io.writeFile` is suppose to be a string but got %s", args[1].Type())
+	}
+	files, ok := stateOf(env).io.Files.(WriteFS)
+	if !ok {
+		return newError("io.writeFile: writing files is not allowed")
+	}
+	if writeErr := files.WriteFile(path, []byte(content.Value)); writeErr != nil {
+		return newError("io.writeFile: %s", writeErr)
+	}
+	return NULL
+}
+
+func listDir(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) > 1 {
+		return wrongArguments("io.listDir", len(args), "0 or 1")
+	}
+	path := "."
+	if len(args) == 1 {
+		p, err := pathArg("listDir", args, 0)
+		if err != nil {
+			return err
+		}
+		path = p
+	}
+	files := stateOf(env).io.Files
+	if files == nil {
+		return newError("io.listDir: file access is not allowed")
+	}
+	entries, readErr := fs.ReadDir(files, path)
+	if readErr != nil {
+		return newError("io.listDir: %s", readErr)
+	}
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Name()
+		if e.IsDir() {
+			names[i] += "/"
+		}
+	}
+	return stringArray(names)
+}
+
+func readLine(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return wrongArguments("io.readLine", len(args), "0")
+	}
+	line, err := stateOf(env).lines.ReadString('\n')
+	if err != nil && line == "" {
+		if err == io.EOF {
+			return NULL
+		}
+		return newError("io.readLine: %s", err)
+	}
+	line = strings.TrimSuffix(line, "\n")
+	return &object.String{Value: strings.TrimSuffix(line, "\r")}
+}
+
+func printValues(env *object.Environment, args ...object.Object) object.Object {
+	stdout := stateOf(env).io.Stdout
+	for _, arg := range args {
+		fmt.Fprint(stdout, arg.Inspect())
+	}
+	return NULL
+}
@@ -0,0 +1,69 @@
+package evaluator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestIOModule(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "in.txt"), []byte("line one\nline two"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	root, err := RootFS(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`io.readFile("in.txt")`, "line one\nline two"},
+		{`io.writeFile("out.txt", "saved")`, "null"},
+		{`io.readFile("out.txt")`, "saved"},
+		{`io.listDir()`, "[in.txt, out.txt, sub/]"},
+		{`io.writeFile("sub/a.txt", "a"); io.listDir("sub")`, "[a.txt]"},
+		{`io.readFile("missing.txt")`, "io.readFile: openat missing.txt: no such file or directory"},
+		{`io.readFile("../in.txt")`, "io.readFile: open ../in.txt: invalid argument"},
+		{`io.writeFile("../escape.txt", "x")`, "io.writeFile: openat ../escape.txt: path escapes from parent"},
+		{`io.readFile(1)`, "argument 1 for `io.readFile` is suppose to be a string but got INTEGER"},
+		{`io.writeFile("a.txt", 1)`, "argument 2 for `io.writeFile` is suppose to be a string but got INTEGER"},
+	}
+
+	// the environment is kept for every test, the later ones read the files the earlier ones write.
+	env := NewEnvironment(Config{IO: IO{Files: root}})
+	for _, tt := range tests {
+		checkInspect(t, tt.input, testEvalIn(env, tt.input), tt.expected)
+	}
+}
+
+func TestIOPermissions(t *testing.T) {
+	none := Config{}
+	testInspectWith(t, none, `io.readFile("a.txt")`, "io.readFile: file access is not allowed")
+	testInspectWith(t, none, `io.listDir()`, "io.listDir: file access is not allowed")
+	testInspectWith(t, none, `io.writeFile("a.txt", "a")`, "io.writeFile: writing files is not allowed")
+
+	readOnly := Config{IO: IO{Files: fstest.MapFS{"a.txt": {Data: []byte("read only")}}}}
+	testInspectWith(t, readOnly, `io.readFile("a.txt")`, "read only")
+	testInspectWith(t, readOnly, `io.writeFile("a.txt", "a")`, "io.writeFile: writing files is not allowed")
+
+	// an environment made without a config has the standard streams but no files.
+	testInspect(t, `io.readFile("a.txt")`, "io.readFile: file access is not allowed")
+}
+
+func TestStandardStreams(t *testing.T) {
+	var out bytes.Buffer
+	cfg := Config{IO: IO{Stdout: &out, Stdin: strings.NewReader("ann\r\nbob\nlast")}}
+	testInspectWith(t, cfg, `io.print("name? ", 1, [2]); puts(io.readLine(), "x"); [io.readLine(), io.readLine(), io.readLine()]`, "[bob, last, null]")
+	if got, want := out.String(), "name? 1[2]ann\nx\n"; got != want {
+		t.Errorf("wrong output. got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,288 @@
+package evaluator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"go-interpreter-lexer/object"
+)
+
+// the json module converts between JSON text and values, objects keep the order of their keys in a hash.
+func init() {
+	modules["json"] = &object.Module{Name: "json",
+		Doc: "json converts values to and from JSON text, json.parse(s) and json.stringify(value).",
+		Members: map[string]*object.Builtin{
+			"parse": {Fn: parseJSON,
+				Doc: "json.parse(s) returns the value of the JSON text s. Objects become hashes with their keys in order, whole numbers integers and other numbers decimals.",
+			},
+			"stringify": {Fn: stringifyJSON,
+				Doc: "json.stringify(value, indent) returns value as JSON text, indent is optional and is a number of spaces or a string to indent nested values with.",
+			},
+		},
+	}
+}
+
+// maxJSONExponent bounds the exponent of a number json.parse accepts, 1e999999999 would not fit in memory.
+const maxJSONExponent = 1000
+
+func parseJSON(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return wrongArguments("json.parse", len(args), "1")
+	}
+	s, ok := args[0].(*object.String)
+	if !ok {
+		return newError("argument 1 for `json.parse` is suppose to be a string but got %s", args[0].Type())
+	}
+	dec := json.NewDecoder(strings.NewReader(s.Value))
+	dec.UseNumber()
+	p := &jsonParser{dec: dec, text: s.Value}
+	value, err := p.value()
+	if err == nil {
+		offset := dec.InputOffset()
+		if _, extra := dec.Token(); extra != io.EOF {
+			offset += int64(len(s.Value[offset:]) - len(strings.TrimLeft(s.Value[offset:], " \t\r\n")))
+			err = p.errorAt(offset, "unexpected data after the value")
+		}
+	}
+	if err != nil {
+		return newError("json.parse: %s", err)
+	}
+	return value
+}
+
+// jsonParser builds values from the tokens of a decoder, reading token by token keeps the order of keys.
+type jsonParser struct {
+	dec  *json.Decoder
+	text string
+}
+
+// errorAt is an error at the line and column of a byte offset of the text.
+func (p *jsonParser) errorAt(offset int64, msg string) error {
+	if offset > int64(len(p.text)) {
+		offset = int64(len(p.text))
+	}
+	before := p.text[:offset]
+	line := strings.Count(before, "\n") + 1
+	col := len(before) - strings.LastIndex(before, "\n")
+	return errors.New(msg + " at " + strconv.Itoa(line) + ":" + strconv.Itoa(col))
+}
+
+func (p *jsonParser) token() (json.Token, error) {
+	offset := p.dec.InputOffset()
+	tok, err := p.dec.Token()
+	if err == nil {
+		return tok, nil
+	}
+	var syntax *json.SyntaxError
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF || err.Error() == "unexpected end of JSON input":
+		return nil, p.errorAt(int64(len(p.text)), "unexpected end of JSON input")
+	case errors.As(err, &syntax):
+		// the offset of a syntax error is just past the character that is wrong.
+		return nil, p.errorAt(syntax.Offset-1, syntax.Error())
+	}
+	return nil, p.errorAt(offset, err.Error())
+}
+
+func (p *jsonParser) value() (object.Object, error) {
+	offset := p.dec.InputOffset()
+	tok, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok := tok.(type) {
+	case json.Delim:
+		if tok == '[' {
+			return p.array()
+		}
+		return p.object()
+	case string:
+		return &object.String{Value: tok}, nil
+	case json.Number:
+		n, ok := jsonNumber(string(tok))
+		if !ok {
+			return nil, p.errorAt(offset, "number "+string(tok)+" is out of range")
+		}
+		return n, nil
+	case bool:
+		return nativeBoolToBooleanObject(tok), nil
+	}
+	return NULL, nil
+}
+
+func (p *jsonParser) array() (object.Object, error) {
+	elements := []object.Object{}
+	for p.dec.More() {
+		el, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, el)
+	}
+	if _, err := p.token(); err != nil {
+		return nil, err
+	}
+	return &object.Array{Elements: elements}, nil
+}
+
+func (p *jsonParser) object() (object.Object, error) {
+	hash := object.NewHash()
+	for p.dec.More() {
+		tok, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		key := &object.String{Value: tok.(string)}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		hash.Set(key.HashKey(), object.HashPair{Key: key, Value: value})
+	}
+	if _, err := p.token(); err != nil {
+		return nil, err
+	}
+	return hash, nil
+}
+
+/*
+	jsonNumber converts the text of a JSON number to an integer, to a big integer when it is whole and too
+	large for one or to a decimal. An exponent is applied to the decimal, 1.5e2 is 150.
+*/
+func jsonNumber(text string) (object.Object, bool) {
+	if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return &object.Integer{Value: n}, true
+	}
+	mantissa, exponent := text, int64(0)
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(text[i+1:], 10, 64)
+		if err != nil || e > maxJSONExponent || e < -maxJSONExponent {
+			return nil, false
+		}
+		mantissa, exponent = text[:i], e
+	}
+	d, err := object.ParseDecimal(mantissa)
+	if err != nil {
+		return nil, false
+	}
+	scale := int64(d.Scale) - exponent
+	if scale < 0 {
+		d = &object.Decimal{Unscaled: new(big.Int).Mul(d.Unscaled, pow10(int32(-scale))), Scale: 0}
+	} else {
+		d = &object.Decimal{Unscaled: d.Unscaled, Scale: int32(scale)}
+	}
+	if !strings.ContainsAny(text, ".eE") {
+		return &object.BigInt{Value: d.Unscaled}, true
+	}
+	return d, true
+}
+
+func stringifyJSON(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return wrongArguments("json.stringify", len(args), "1 or 2")
+	}
+	w := &jsonWriter{}
+	if len(args) == 2 {
+		switch indent := args[1].(type) {
+		case *object.Integer:
+			if indent.Value < 0 || indent.Value > 10 {
+				return newError("`json.stringify` indent must be from 0 to 10 spaces, got %d", indent.Value)
+			}
+			w.indent = strings.Repeat(" ", int(indent.Value))
+		case *object.String:
+			w.indent = indent.Value
+		default:
+			return newError("argument 2 for `json.stringify` is suppose to be an integer or a string but got %s", args[1].Type())
+		}
+	}
+	if err := w.value(args[0], 0); err != nil {
+		return err
+	}
+	return &object.String{Value: w.out.String()}
+}
+
+// jsonWriter writes values as JSON, with indent set nested values go on their own lines like json.MarshalIndent.
+type jsonWriter struct {
+	out    bytes.Buffer
+	indent string
+}
+
+func (w *jsonWriter) newline(depth int) {
+	if w.indent != "" {
+		w.out.WriteByte('\n')
+		w.out.WriteString(strings.Repeat(w.indent, depth))
+	}
+}
+
+func (w *jsonWriter) value(obj object.Object, depth int) *object.Error {
+	switch obj := obj.(type) {
+	case *object.Integer, *object.BigInt, *object.Decimal, *object.Boolean, *object.Null:
+		w.out.WriteString(obj.Inspect())
+	case *object.String:
+		w.str(obj.Value)
+	case *object.Array:
+		if len(obj.Elements) == 0 {
+			w.out.WriteString("[]")
+			return nil
+		}
+		w.out.WriteByte('[')
+		for i, el := range obj.Elements {
+			if i > 0 {
+				w.out.WriteByte(',')
+			}
+			w.newline(depth + 1)
+			if err := w.value(el, depth+1); err != nil {
+				return err
+			}
+		}
+		w.newline(depth)
+		w.out.WriteByte(']')
+	case *object.Hash:
+		pairs := obj.Ordered()
+		if len(pairs) == 0 {
+			w.out.WriteString("{}")
+			return nil
+		}
+		w.out.WriteByte('{')
+		for i, pair := range pairs {
+			if i > 0 {
+				w.out.WriteByte(',')
+			}
+			w.newline(depth + 1)
+			// JSON keys are strings, numbers are written as the string of their digits like Go does.
+			switch key := pair.Key.(type) {
+			case *object.String:
+				w.str(key.Value)
+			case *object.Integer, *object.BigInt, *object.Decimal:
+				w.str(key.Inspect())
+			default:
+				return newError("`json.stringify` cannot use %s as an object key", pair.Key.Type())
+			}
+			w.out.WriteByte(':')
+			if w.indent != "" {
+				w.out.WriteByte(' ')
+			}
+			if err := w.value(pair.Value, depth+1); err != nil {
+				return err
+			}
+		}
+		w.newline(depth)
+		w.out.WriteByte('}')
+	default:
+		return newError("`json.stringify` cannot convert %s to JSON", obj.Type())
+	}
+	return nil
+}
+
+func (w *jsonWriter) str(s string) {
+	enc := json.NewEncoder(&w.out)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	// Encode ends the value with a newline.
+	w.out.Truncate(w.out.Len() - 1)
+}
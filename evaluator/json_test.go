@@ -0,0 +1,84 @@
+package evaluator
+
+import (
+	"testing"
+
+	"go-interpreter-lexer/object"
+)
+
+func TestJSONParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"b": 1, "a": [true, null, "x"]}`, "{b:1, a:[true, null, x]}"},
+		{`{"z": 1, "y": 2, "z": 3}`, "{z:3, y:2}"},
+		{`[1, -2, 1.50, 2e3, 1.5e-2, 12345678901234567890]`, "[1, -2, 1.50, 2000, 0.015, 12345678901234567890]"},
+		{` "caf\u00e9" `, "café"},
+		{`{}`, "{}"},
+		{`[]`, "[]"},
+
+		{`[1, 2`, "json.parse: unexpected end of JSON input at 1:6"},
+		{"{\"a\": 1,\n \"b\" 2}", "json.parse: invalid character '2' after object key at 2:6"},
+		{`[1] [2]`, "json.parse: unexpected data after the value at 1:5"},
+		{`1e99999`, "json.parse: number 1e99999 is out of range at 1:1"},
+	}
+
+	for _, tt := range tests {
+		got := parseJSON(object.NewEnvironment(), &object.String{Value: tt.input})
+		actual := got.Inspect()
+		if err, ok := got.(*object.Error); ok {
+			actual = err.Message
+		}
+		if actual != tt.expected {
+			t.Errorf("json.parse(%q): expected %q got %q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+// Monkey strings have no escapes, so these parse JSON without quotes from source.
+func TestJSONParseFromSource(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`json.parse("[1, 2.5, [true, null]]")`, "[1, 2.5, [true, null]]"},
+		{`let v = {"user": {"name": "monkey"}}; json.parse(json.stringify(v)).user.name`, "monkey"},
+		{`let v = {"n": [1, "two", false]}; json.stringify(json.parse(json.stringify(v))) == json.stringify(v)`, "true"},
+		{`json.parse(1)`, "argument 1 for `json.parse` is suppose to be a string but got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, tt.input, tt.expected)
+	}
+}
+
+func TestJSONStringify(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`json.stringify({"b": [1, 2.50, 3n], "a": {"ok": true, "none": if (false) { 1 }}})`, `{"b":[1,2.50,3],"a":{"ok":true,"none":null}}`},
+		{`json.stringify({1: "one", 2.5: "x"})`, `{"1":"one","2.5":"x"}`},
+		{`json.stringify([[], {}])`, `[[],{}]`},
+		{`json.stringify({"a": [1, 2], "b": {}}, 2)`, "{\n  \"a\": [\n    1,\n    2\n  ],\n  \"b\": {}\n}"},
+		{`json.stringify([1], "--")`, "[\n--1\n]"},
+
+		{`json.stringify([fn(x) { x }])`, "`json.stringify` cannot convert FUNCTION to JSON"},
+		{`json.stringify({true: 1})`, "`json.stringify` cannot use BOOLEAN as an object key"},
+		{`json.stringify(1, true)`, "argument 2 for `json.stringify` is suppose to be an integer or a string but got BOOLEAN"},
+		{`json.stringify(1, 20)`, "`json.stringify` indent must be from 0 to 10 spaces, got 20"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, tt.input, tt.expected)
+	}
+}
+
+func TestJSONStringifyEscapes(t *testing.T) {
+	got := stringifyJSON(object.NewEnvironment(), &object.String{Value: "say \"hi\" <b>\n"})
+	expected := `"say \"hi\" <b>\n"`
+	if got.Inspect() != expected {
+		t.Errorf("expected %q got %q", expected, got.Inspect())
+	}
+}
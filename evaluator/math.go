@@ -0,0 +1,342 @@
+package evaluator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"go-interpreter-lexer/object"
+)
+
+/*
+	the math module works on integers, big integers and decimals, a result that does not fit in an int64 is a big
+	integer like for the operators.
+*/
+func init() {
+	modules["math"] = &object.Module{Name: "math",
+		Doc: "math holds number functions, math.max(a, b) for example.",
+		Members: map[string]*object.Builtin{
+			"abs": {Fn: abs,
+				Doc: "math.abs(x) returns x without its sign.",
+			},
+			"min": {Fn: extreme("min", func(cmp int) bool { return cmp < 0 }),
+				Doc: "math.min(xs...) returns the smallest of its arguments, math.min(...arr) the smallest element of an array.",
+			},
+			"max": {Fn: extreme("max", func(cmp int) bool { return cmp > 0 }),
+				Doc: "math.max(xs...) returns the largest of its arguments, math.max(...arr) the largest element of an array.",
+			},
+			"pow": {Fn: pow,
+				Doc: "math.pow(x, n) returns x to the power of the integer n, n cannot be negative.",
+			},
+			"sqrt": {Fn: sqrt,
+				Doc: "math.sqrt(x) returns the square root of x rounded down.",
+			},
+			"floor": {Fn: rounding("floor", floorDecimal),
+				Doc: "math.floor(x) returns the decimal x rounded down to an integer, an integer is returned as it is.",
+			},
+			"ceil": {Fn: rounding("ceil", ceilDecimal),
+				Doc: "math.ceil(x) returns the decimal x rounded up to an integer, an integer is returned as it is.",
+			},
+			"round": {Fn: rounding("round", roundDecimal),
+				Doc: "math.round(x) returns the decimal x rounded to the nearest integer, halves away from zero, an integer is returned as it is.",
+			},
+			"div": {Fn: div,
+				Doc: "math.div(x, d) returns the integer x divided by d rounded down, math.div(-7, 2) is -4 where -7 / 2 is -3.",
+			},
+			"clamp": {Fn: clamp,
+				Doc: "math.clamp(x, lo, hi) returns x limited to the range from lo to hi.",
+			},
+			"random": {Fn: random,
+				Doc: "math.random(n) returns a random integer from 0 up to but not including n, math.random(lo, hi) one from lo up to hi.",
+			},
+		},
+	}
+}
+
+// checkedArithmetic applies +, - or * and reports false when the result does not fit in an int64.
+func checkedArithmetic(operator string, a, b int64) (int64, bool) {
+	switch operator {
+	case "+":
+		if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b) {
+			return 0, false
+		}
+		return a + b, true
+	case "-":
+		if (b < 0 && a > math.MaxInt64+b) || (b > 0 && a < math.MinInt64+b) {
+			return 0, false
+		}
+		return a - b, true
+	case "*":
+		if a == 0 || b == 0 {
+			return 0, true
+		}
+		r := a * b
+		return r, r/b == a && !(a == -1 && b == math.MinInt64) && !(b == -1 && a == math.MinInt64)
+	}
+	return 0, false
+}
+
+// numberArgs checks that there are want arguments and that all of them are integers, big integers or decimals.
+func numberArgs(name string, args []object.Object, want int) *object.Error {
+	if len(args) != want {
+		return wrongArguments(name, len(args), fmt.Sprint(want))
+	}
+	for i := range args {
+		if err := numberArg(name, args, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func numberArg(name string, args []object.Object, i int) *object.Error {
+	if !isNumber(args[i]) {
+		return newError("argument %d for `%s` is suppose to be a number but got %s", i+1, name, args[i].Type())
+	}
+	return nil
+}
+
+// cmpNumbers returns -1, 0 or 1 as a is less than, equal to or greater than b whatever their types.
+func cmpNumbers(a, b object.Object) int {
+	if x, ok := a.(*object.Integer); ok {
+		if y, ok := b.(*object.Integer); ok {
+			switch {
+			case x.Value < y.Value:
+				return -1
+			case x.Value > y.Value:
+				return 1
+			}
+			return 0
+		}
+	}
+	_, aDecimal := a.(*object.Decimal)
+	_, bDecimal := b.(*object.Decimal)
+	if aDecimal || bDecimal {
+		x, y := toDecimal(a), toDecimal(b)
+		scale := x.Scale
+		if y.Scale > scale {
+			scale = y.Scale
+		}
+		return rescale(x, scale).Cmp(rescale(y, scale))
+	}
+	return toBigInt(a).Cmp(toBigInt(b))
+}
+
+func abs(env *object.Environment, args ...object.Object) object.Object {
+	if err := numberArgs("math.abs", args, 1); err != nil {
+		return err
+	}
+	switch x := args[0].(type) {
+	case *object.BigInt:
+		return &object.BigInt{Value: new(big.Int).Abs(x.Value)}
+	case *object.Decimal:
+		return &object.Decimal{Unscaled: new(big.Int).Abs(x.Unscaled), Scale: x.Scale}
+	}
+	x := args[0].(*object.Integer).Value
+	if x == math.MinInt64 {
+		return &object.BigInt{Value: new(big.Int).Neg(big.NewInt(x))}
+	}
+	if x < 0 {
+		x = -x
+	}
+	return &object.Integer{Value: x}
+}
+
+// extreme builds min and max, better reports whether an argument comparing cmp to the best so far replaces it.
+func extreme(name string, better func(cmp int) bool) object.BuiltInFunction {
+	return func(env *object.Environment, args ...object.Object) object.Object {
+		if len(args) < 1 {
+			return wrongArguments("math."+name, len(args), "at least 1")
+		}
+		var result object.Object
+		for i := range args {
+			if err := numberArg("math."+name, args, i); err != nil {
+				return err
+			}
+			if i == 0 || better(cmpNumbers(args[i], result)) {
+				result = args[i]
+			}
+		}
+		return result
+	}
+}
+
+func pow(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return wrongArguments("math.pow", len(args), "2")
+	}
+	if err := numberArg("math.pow", args, 0); err != nil {
+		return err
+	}
+	n, err := integerArg("math.pow", args, 1)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return newError("`math.pow` exponent cannot be negative, got %d", n)
+	}
+	switch x := args[0].(type) {
+	case *object.BigInt:
+		return bigPow(x.Value, n)
+	case *object.Decimal:
+		// the scale grows with the exponent like it does for *, 1.5 to the power of 2 is 2.25.
+		if int64(x.Scale)*n > maxBigIntBits || int64(x.Unscaled.BitLen()-1)*n > maxBigIntBits {
+			return newError("`math.pow(%s, %d)` is too large", x.Inspect(), n)
+		}
+		unscaled := new(big.Int).Exp(x.Unscaled, big.NewInt(n), nil)
+		return &object.Decimal{Unscaled: unscaled, Scale: x.Scale * int32(n)}
+	}
+	return intPow(args[0].(*object.Integer).Value, n)
+}
+
+// intPow is math.pow of an integer, worked out on int64 until the result does not fit.
+func intPow(x, n int64) object.Object {
+	result := int64(1)
+	for base, e := x, n; e > 0; e >>= 1 {
+		var ok bool
+		if e&1 == 1 {
+			if result, ok = checkedArithmetic("*", result, base); !ok {
+				return bigPow(big.NewInt(x), n)
+			}
+		}
+		if e > 1 {
+			if base, ok = checkedArithmetic("*", base, base); !ok {
+				return bigPow(big.NewInt(x), n)
+			}
+		}
+	}
+	return &object.Integer{Value: result}
+}
+
+// bigPow is math.pow for a result that may not fit in an int64.
+func bigPow(x *big.Int, n int64) object.Object {
+	if int64(x.BitLen()-1)*n > maxBigIntBits {
+		return newError("`math.pow(%s, %d)` is too large", x, n)
+	}
+	return bigIntResult(new(big.Int).Exp(x, big.NewInt(n), nil))
+}
+
+func sqrt(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return wrongArguments("math.sqrt", len(args), "1")
+	}
+	x, err := integerArg("math.sqrt", args, 0)
+	if err != nil {
+		return err
+	}
+	if x < 0 {
+		return newError("`math.sqrt` of a negative number %d", x)
+	}
+	// the float estimate can be one off for large x, it is corrected with divisions that cannot overflow.
+	r := int64(math.Sqrt(float64(x)))
+	for r > 0 && r > x/r {
+		r--
+	}
+	for r+1 <= x/(r+1) {
+		r++
+	}
+	return &object.Integer{Value: r}
+}
+
+// rounding builds floor, ceil and round, round turns a decimal into the whole number it rounds to.
+func rounding(name string, round func(d *object.Decimal) *big.Int) object.BuiltInFunction {
+	return func(env *object.Environment, args ...object.Object) object.Object {
+		if len(args) != 1 {
+			return wrongArguments("math."+name, len(args), "1")
+		}
+		switch x := args[0].(type) {
+		case *object.Integer, *object.BigInt:
+			return x
+		case *object.Decimal:
+			if x.Scale == 0 {
+				return bigIntResult(x.Unscaled)
+			}
+			return bigIntResult(round(x))
+		}
+		return newError("argument 1 for `math.%s` is suppose to be a number but got %s", name, args[0].Type())
+	}
+}
+
+func floorDecimal(d *object.Decimal) *big.Int {
+	q, r := new(big.Int).QuoRem(d.Unscaled, pow10(d.Scale), new(big.Int))
+	if r.Sign() < 0 {
+		q.Sub(q, big.NewInt(1))
+	}
+	return q
+}
+
+func ceilDecimal(d *object.Decimal) *big.Int {
+	q, r := new(big.Int).QuoRem(d.Unscaled, pow10(d.Scale), new(big.Int))
+	if r.Sign() > 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+func roundDecimal(d *object.Decimal) *big.Int {
+	return roundQuo(d.Unscaled, pow10(d.Scale))
+}
+
+// div is division rounded down rather than towards zero, it is worked out on big integers so it cannot overflow.
+func div(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return wrongArguments("math.div", len(args), "2")
+	}
+	for i, arg := range args {
+		if _, ok := arg.(*object.Decimal); ok || !isNumber(arg) {
+			return newError("argument %d for `math.div` is suppose to be an integer but got %s", i+1, arg.Type())
+		}
+	}
+	x, d := toBigInt(args[0]), toBigInt(args[1])
+	if d.Sign() == 0 {
+		return newError("division by zero: math.div(%s, %s)", x, d)
+	}
+	q, r := new(big.Int).QuoRem(x, d, new(big.Int))
+	if r.Sign() != 0 && r.Sign() != d.Sign() {
+		q.Sub(q, big.NewInt(1))
+	}
+	return bigIntResult(q)
+}
+
+func clamp(env *object.Environment, args ...object.Object) object.Object {
+	if err := numberArgs("math.clamp", args, 3); err != nil {
+		return err
+	}
+	x, lo, hi := args[0], args[1], args[2]
+	if cmpNumbers(lo, hi) > 0 {
+		return newError("`math.clamp` lower bound %s is above the upper bound %s", lo.Inspect(), hi.Inspect())
+	}
+	if cmpNumbers(x, lo) < 0 {
+		return lo
+	}
+	if cmpNumbers(x, hi) > 0 {
+		return hi
+	}
+	return x
+}
+
+func random(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return wrongArguments("math.random", len(args), "1 or 2")
+	}
+	bounds := []int64{0, 0}
+	for i := range args {
+		n, err := integerArg("math.random", args, i)
+		if err != nil {
+			return err
+		}
+		bounds[i] = n
+	}
+	if len(args) == 1 {
+		bounds[0], bounds[1] = 0, bounds[0]
+	}
+	lo, hi := bounds[0], bounds[1]
+	if lo >= hi {
+		return newError("`math.random` needs a range that is not empty, got %d to %d", lo, hi)
+	}
+	span := uint64(hi) - uint64(lo)
+	if span > math.MaxInt64 {
+		return &object.Integer{Value: lo + int64(stateOf(env).random.Uint64()%span)}
+	}
+	return &object.Integer{Value: lo + stateOf(env).random.Int63n(int64(span))}
+}
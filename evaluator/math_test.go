@@ -0,0 +1,64 @@
+package evaluator
+
+import "testing"
+
+func TestMathModule(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[math.abs(-5), math.abs(5)]", "[5, 5]"},
+		{"[math.min(3, 1, 2), math.max(3, 1, 2), math.max(...[4, 9, 2])]", "[1, 3, 9]"},
+		{"[math.pow(2, 10), math.pow(-3, 3), math.pow(5, 0)]", "[1024, -27, 1]"},
+		{"[math.sqrt(0), math.sqrt(15), math.sqrt(16), math.sqrt(9223372036854775807)]", "[0, 3, 4, 3037000499]"},
+		{"[math.floor(3.5), math.floor(-3.5), math.floor(2.00), math.floor(7), math.floor(10n)]", "[3, -4, 2, 7, 10]"},
+		{"[math.ceil(3.5), math.ceil(-3.5), math.ceil(3.01), math.ceil(7)]", "[4, -3, 4, 7]"},
+		{"[math.round(2.5), math.round(-2.5), math.round(2.49), math.round(7 / 2.0)]", "[3, -3, 2, 4]"},
+		{"[math.div(7, 2), math.div(-7, 2), math.div(7, -2), math.div(-6, 3)]", "[3, -4, -4, -2]"},
+		{"[math.clamp(5, 0, 3), math.clamp(-1, 0, 3), math.clamp(2, 0, 3)]", "[3, 0, 2]"},
+		{"math.random(1)", "0"},
+		{"let r = math.random(-3, 3); math.clamp(r, -3, 2) == r", "true"},
+
+		{"math.abs(-9223372036854775807 - 1)", "9223372036854775808"},
+		{"[math.abs(-12.50), math.abs(-100000000000000000000n), math.abs(2.5)]", "[12.50, 100000000000000000000, 2.5]"},
+		{"[math.min(2, 1.5, 3n), math.max(2, 1.5, 3n), math.max(1.50, 1.5), math.min(-100000000000000000000n, 0)]", "[1.5, 3, 1.50, -100000000000000000000]"},
+		{"[math.clamp(1.25, 0, 1), math.clamp(0.5, 0, 1), math.clamp(100000000000000000000n, 0, 99.99)]", "[1, 0.5, 99.99]"},
+		{"[math.pow(1.5, 2), math.pow(-0.1, 3), math.pow(10n, 20), math.pow(2n, 3)]", "[2.25, -0.001, 100000000000000000000, 8]"},
+		{"[math.pow(10, 19), math.pow(-2, 63), math.pow(-2, 65)]", "[10000000000000000000, -9223372036854775808, -36893488147419103232]"},
+		{"math.div(-9223372036854775807 - 1, -1)", "9223372036854775808"},
+		{"math.floor(100000000000000000000.5)", "100000000000000000000"},
+		{"math.pow(3, 10000000)", "`math.pow(3, 10000000)` is too large"},
+		{"math.pow(2, -1)", "`math.pow` exponent cannot be negative, got -1"},
+		{"math.sqrt(-4)", "`math.sqrt` of a negative number -4"},
+		{"math.div(1, 0)", "division by zero: math.div(1, 0)"},
+		{"math.floor(7, 2)", "wrong number of arguments to `math.floor` got 2, wanted 1"},
+		{`math.round("2.5")`, "argument 1 for `math.round` is suppose to be a number but got STRING"},
+		{"math.div(7.5, 2)", "argument 1 for `math.div` is suppose to be an integer but got DECIMAL"},
+		{"math.clamp(1, 3, 2)", "`math.clamp` lower bound 3 is above the upper bound 2"},
+		{"math.random(0)", "`math.random` needs a range that is not empty, got 0 to 0"},
+		{"math.min()", "wrong number of arguments to `math.min` got 0, wanted at least 1"},
+		{`math.max(1, "a")`, "argument 2 for `math.max` is suppose to be a number but got STRING"},
+		{"math.pow(2, 1.5)", "argument 2 for `math.pow` is suppose to be an integer but got DECIMAL"},
+		{"math.pow(1.5, 10000000)", "`math.pow(1.5, 10000000)` is too large"},
+		{"math.clamp(1, 3.5, 2n)", "`math.clamp` lower bound 3.5 is above the upper bound 2"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, tt.input, tt.expected)
+	}
+}
+
+func TestSeedRandom(t *testing.T) {
+	draw := func(seed int64) string {
+		return testEvalIn(NewEnvironment(Config{Seed: &seed}), "map(range(5), fn(i) { math.random(1000) })").Inspect()
+	}
+	if first, second := draw(42), draw(42); first != second {
+		t.Errorf("the same seed gave %s and %s", first, second)
+	}
+	if first, second := draw(0), draw(0); first != second {
+		t.Errorf("the seed 0 gave %s and %s", first, second)
+	}
+	if first, other := draw(42), draw(43); first == other {
+		t.Errorf("the seeds 42 and 43 both gave %s", first)
+	}
+}
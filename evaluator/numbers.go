@@ -0,0 +1,215 @@
+package evaluator
+
+import (
+	"math/big"
+
+	"go-interpreter-lexer/object"
+)
+
+// divisionScale is the number of decimal places a division is worked out to before trailing zeros are dropped.
+const divisionScale = 16
+
+// maxBigIntBits bounds the big integers math.pow builds, a mistaken exponent should fail rather than exhaust memory.
+const maxBigIntBits = 1 << 20
+
+func init() {
+	builtins["decimal"] = &object.Builtin{Fn: decimal,
+		Doc: "decimal(x, places) converts an integer, big integer or string such as \"12.50\" to a decimal, places is optional and rounds the result to that many places, halves away from zero.",
+	}
+}
+
+func isNumber(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Integer, *object.BigInt, *object.Decimal:
+		return true
+	}
+	return false
+}
+
+// toBigInt returns the value of an integer or big integer as a big.Int the caller must not change.
+func toBigInt(obj object.Object) *big.Int {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return big.NewInt(obj.Value)
+	case *object.BigInt:
+		return obj.Value
+	}
+	return nil
+}
+
+func toDecimal(obj object.Object) *object.Decimal {
+	if d, ok := obj.(*object.Decimal); ok {
+		return d
+	}
+	return &object.Decimal{Unscaled: toBigInt(obj), Scale: 0}
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// rescale returns the unscaled value of d with scale places, scale must not be below the scale of d.
+func rescale(d *object.Decimal, scale int32) *big.Int {
+	if scale == d.Scale {
+		return d.Unscaled
+	}
+	return new(big.Int).Mul(d.Unscaled, pow10(scale-d.Scale))
+}
+
+// roundQuo divides a by b rounding halves away from zero.
+func roundQuo(a, b *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(r), big.NewInt(2)).Cmp(new(big.Int).Abs(b)) >= 0 {
+		if a.Sign() == b.Sign() {
+			q.Add(q, big.NewInt(1))
+		} else {
+			q.Sub(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+/*
+	evalNumberInfixExpression applies an operator to two numbers of which at least one is a big integer or a
+	decimal. A decimal operand makes the result a decimal, otherwise it is a big integer.
+*/
+func evalNumberInfixExpression(operator string, left, right object.Object) object.Object {
+	_, leftDecimal := left.(*object.Decimal)
+	_, rightDecimal := right.(*object.Decimal)
+	if leftDecimal || rightDecimal {
+		return evalDecimalInfixExpression(operator, toDecimal(left), toDecimal(right))
+	}
+	return evalBigIntInfixExpression(operator, toBigInt(left), toBigInt(right))
+}
+
+func evalBigIntInfixExpression(operator string, a, b *big.Int) object.Object {
+	switch operator {
+	case "+":
+		return bigIntResult(new(big.Int).Add(a, b))
+	case "-":
+		return bigIntResult(new(big.Int).Sub(a, b))
+	case "*":
+		return bigIntResult(new(big.Int).Mul(a, b))
+	case "/", "%":
+		if b.Sign() == 0 {
+			return newError("division by zero: %s %s %s", a.String(), operator, b.String())
+		}
+		if operator == "%" {
+			return bigIntResult(new(big.Int).Rem(a, b))
+		}
+		return bigIntResult(new(big.Int).Quo(a, b))
+	}
+	return compareNumbers(operator, a.Cmp(b), object.BIGINT_OBJ)
+}
+
+// bigIntResult returns n as an integer when it fits in an int64, a value is only a big integer while it has to be.
+func bigIntResult(n *big.Int) object.Object {
+	if n.IsInt64() {
+		return &object.Integer{Value: n.Int64()}
+	}
+	return &object.BigInt{Value: n}
+}
+
+func evalDecimalInfixExpression(operator string, a, b *object.Decimal) object.Object {
+	scale := a.Scale
+	if b.Scale > scale {
+		scale = b.Scale
+	}
+	x, y := rescale(a, scale), rescale(b, scale)
+	switch operator {
+	case "+":
+		return &object.Decimal{Unscaled: new(big.Int).Add(x, y), Scale: scale}
+	case "-":
+		return &object.Decimal{Unscaled: new(big.Int).Sub(x, y), Scale: scale}
+	case "*":
+		return &object.Decimal{Unscaled: new(big.Int).Mul(a.Unscaled, b.Unscaled), Scale: a.Scale + b.Scale}
+	case "/", "%":
+		if b.Unscaled.Sign() == 0 {
+			return newError("division by zero: %s %s %s", a.Inspect(), operator, b.Inspect())
+		}
+		if operator == "%" {
+			return &object.Decimal{Unscaled: new(big.Int).Rem(x, y), Scale: scale}
+		}
+		// the quotient is worked out to at least divisionScale places and its trailing zeros are dropped down
+		// to the scale of the operands, 10.00 / 4 is 2.50 and 1 / 3.0 is 0.3333333333333333.
+		places := scale
+		if places < divisionScale {
+			places = divisionScale
+		}
+		q := roundQuo(new(big.Int).Mul(x, pow10(places)), y)
+		result := (&object.Decimal{Unscaled: q, Scale: places}).Normalize()
+		if result.Scale < scale {
+			result = &object.Decimal{Unscaled: rescale(result, scale), Scale: scale}
+		}
+		return result
+	}
+	return compareNumbers(operator, x.Cmp(y), object.DECIMAL_OBJ)
+}
+
+// compareNumbers turns the result of comparing two numbers into the boolean the operator asks for.
+func compareNumbers(operator string, cmp int, typ object.ObjectType) object.Object {
+	switch operator {
+	case "<":
+		return nativeBoolToBooleanObject(cmp < 0)
+	case ">":
+		return nativeBoolToBooleanObject(cmp > 0)
+	case "==":
+		return nativeBoolToBooleanObject(cmp == 0)
+	case "!=":
+		return nativeBoolToBooleanObject(cmp != 0)
+	}
+	return newError("unknown operator: %s %s %s", typ, operator, typ)
+}
+
+// numbersEqual compares two numbers by value whatever their types, 1, 1n and 1.00 are equal.
+func numbersEqual(a, b object.Object) bool {
+	return evalInfixExpression("==", a, b) == TRUE
+}
+
+func negateNumber(obj object.Object) object.Object {
+	switch obj := obj.(type) {
+	case *object.BigInt:
+		return bigIntResult(new(big.Int).Neg(obj.Value))
+	case *object.Decimal:
+		return &object.Decimal{Unscaled: new(big.Int).Neg(obj.Unscaled), Scale: obj.Scale}
+	}
+	return newError("unknown operator: -%s", obj.Type())
+}
+
+func decimal(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return wrongArguments("decimal", len(args), "1 or 2")
+	}
+	var d *object.Decimal
+	switch arg := args[0].(type) {
+	case *object.Integer, *object.BigInt, *object.Decimal:
+		d = toDecimal(arg)
+	case *object.String:
+		parsed, err := object.ParseDecimal(arg.Value)
+		if err != nil {
+			return newError("`decimal` cannot convert %q, it is not a number", arg.Value)
+		}
+		d = parsed
+	default:
+		return newError("argument 1 for `decimal` is suppose to be a number or a string but got %s", args[0].Type())
+	}
+	if len(args) == 1 {
+		return d
+	}
+	places, err := integerArg("decimal", args, 1)
+	if err != nil {
+		return err
+	}
+	if places < 0 || places > 1000 {
+		return newError("`decimal` places must be from 0 to 1000, got %d", places)
+	}
+	return roundDecimalTo(d, int32(places))
+}
+
+// roundDecimalTo returns d with scale places, rounding halves away from zero when places are dropped.
+func roundDecimalTo(d *object.Decimal, scale int32) *object.Decimal {
+	if scale >= d.Scale {
+		return &object.Decimal{Unscaled: rescale(d, scale), Scale: scale}
+	}
+	return &object.Decimal{Unscaled: roundQuo(d.Unscaled, pow10(d.Scale-scale)), Scale: scale}
+}
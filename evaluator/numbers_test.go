@@ -0,0 +1,77 @@
+package evaluator
+
+import "testing"
+
+func TestBigIntegers(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"9223372036854775807 + 1", "9223372036854775808"},
+		{"-9223372036854775807 - 2", "-9223372036854775809"},
+		{"4611686018427387904 * 4", "18446744073709551616"},
+		{"let min = -9223372036854775807 - 1; [min / -1, min % -1, -min]", "[9223372036854775808, 0, 9223372036854775808]"},
+		{"123n", "123"},
+		{"100000000000000000000n / 3", "33333333333333333333"},
+		{"-7n % 3", "-1"},
+		{"[2n > 1, 1 < 2n, 5n == 5, 5 != 5n]", "[true, true, true, false]"},
+		{"-(3n)", "-3"},
+		{"let f = fn(n) { if (n < 2) { 1 } else { n * f(n - 1) } }; f(25)", "15511210043330985984000000"},
+		{"let x = 9223372036854775807 + 1 - 1; [10, 20][x - 9223372036854775806]", "20"},
+		{"[range(-(2n)), strings.repeat(\"ab\", 4n / 2)]", "[[], abab]"},
+		{"1n / 0", "division by zero: 1 / 0"},
+		{"1n + true", "type mismatch: BIGINT + BOOLEAN"},
+		{"1n + 1 + true", "type mismatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, tt.input, tt.expected)
+	}
+}
+
+func TestDecimals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"12.50", "12.50"},
+		{"0.1 + 0.2", "0.3"},
+		{"0.1 + 0.2 == 0.3", "true"},
+		{"19.99 * 3", "59.97"},
+		{"1.5 * 1.5", "2.25"},
+		{"10.00 - 0.25", "9.75"},
+		{"10.00 / 4", "2.50"},
+		{"1 / 3.0", "0.3333333333333333"},
+		{"2.0 / 3", "0.6666666666666667"},
+		{"-2.0 / 3", "-0.6666666666666667"},
+		{"7.5 % 2", "1.5"},
+		{"-0.05", "-0.05"},
+		{"[1.0 == 1, 1.5 > 1, 2n < 2.5, 0.10 == 0.1]", "[true, true, true, true]"},
+		{"1.5 / 0", "division by zero: 1.5 / 0"},
+		{`[decimal(5), decimal("-3.250"), decimal(2n)]`, "[5, -3.250, 2]"},
+		{"[decimal(2.345, 2), decimal(-2.345, 2), decimal(2.5, 0), decimal(1, 2)]", "[2.35, -2.35, 3, 1.00]"},
+		{`decimal("1.2.3")`, "`decimal` cannot convert \"1.2.3\", it is not a number"},
+		{"decimal(true)", "argument 1 for `decimal` is suppose to be a number or a string but got BOOLEAN"},
+		{"decimal(1.5, -1)", "`decimal` places must be from 0 to 1000, got -1"},
+		{`1.5 + "a"`, "type mismatch: DECIMAL + STRING"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, tt.input, tt.expected)
+	}
+}
+
+func TestNumberHashKeys(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let h = {1: "one", 2.50: "two and a half"}; [h[1n], h[1.00], h[2.5], h[2]]`, "[one, one, two and a half, null]"},
+		{`let h = {9223372036854775808n: "big"}; h[9223372036854775807 + 1]`, "big"},
+		{`{1: "a", 1.0: "b"}`, "{1.0:b}"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, tt.input, tt.expected)
+	}
+}
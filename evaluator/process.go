@@ -0,0 +1,163 @@
+package evaluator
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+
+	"go-interpreter-lexer/object"
+)
+
+func init() {
+	builtins["args"] = &object.Builtin{Fn: programArgs,
+		Doc: "args() returns the arguments the program was run with as an array of strings, if the host allows it.",
+	}
+	builtins["env"] = &object.Builtin{Fn: envVar,
+		Doc: "env(name) returns the value of an environment variable, null when it is not set, if the host allows it.",
+	}
+	builtins["exit"] = &object.Builtin{Fn: exit,
+		Doc: "exit(code) ends the program with an exit status, code is optional and 0 by default, if the host allows it.",
+	}
+	builtins["exec"] = &object.Builtin{Fn: execCommand,
+		Doc: "exec(cmd, args) runs a command with an optional array of arguments and returns a hash of its stdout, stderr and exit status, if the host allows it.",
+	}
+}
+
+// Capability is a set of the things about the process running it a program may use, see Process.
+type Capability uint
+
+const (
+	AllowArgs Capability = 1 << iota
+	AllowEnv
+	AllowExit
+	AllowExec
+
+	AllowAll = AllowArgs | AllowEnv | AllowExit | AllowExec
+)
+
+/*
+	Process is what programs know about the process running them. Args are what args() returns and Allow
+	the builtins that may be used, each of args, env, exit and exec is an error unless the host allows it.
+*/
+type Process struct {
+	Args  []string
+	Allow Capability
+}
+
+// allowed reports an error unless the host allows c to the program env belongs to.
+func allowed(env *object.Environment, name string, c Capability) *object.Error {
+	if stateOf(env).process.Allow&c == 0 {
+		return newError("`%s` is not allowed by the host", name)
+	}
+	return nil
+}
+
+func programArgs(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return wrongArguments("args", len(args), "0")
+	}
+	if err := allowed(env, "args", AllowArgs); err != nil {
+		return err
+	}
+	return stringArray(stateOf(env).process.Args)
+}
+
+func envVar(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return wrongArguments("env", len(args), "1")
+	}
+	name, ok := args[0].(*object.String)
+	if !ok {
+		return newError("argument 1 for `env` is suppose to be a string but got %s", args[0].Type())
+	}
+	if err := allowed(env, "env", AllowEnv); err != nil {
+		return err
+	}
+	value, set := os.LookupEnv(name.Value)
+	if !set {
+		return NULL
+	}
+	return &object.String{Value: value}
+}
+
+/*
+	exit returns an error marked as an exit, it unwinds the program like any other error so the host can
+	still write coverage and profiles before it ends with the code.
+*/
+func exit(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) > 1 {
+		return wrongArguments("exit", len(args), "0 or 1")
+	}
+	if err := allowed(env, "exit", AllowExit); err != nil {
+		return err
+	}
+	code := int64(0)
+	if len(args) == 1 {
+		n, err := integerArg("exit", args, 0)
+		if err != nil {
+			return err
+		}
+		code = n
+	}
+	if code < 0 || code > 255 {
+		return newError("`exit` code must be from 0 to 255, got %d", code)
+	}
+	err := newError("exit status %d", code)
+	err.Exit, err.Code = true, int(code)
+	return err
+}
+
+func execCommand(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return wrongArguments("exec", len(args), "1 or 2")
+	}
+	name, ok := args[0].(*object.String)
+	if !ok {
+		return newError("argument 1 for `exec` is suppose to be a string but got %s", args[0].Type())
+	}
+	var cmdArgs []string
+	if len(args) == 2 {
+		arr, err := arrayArg("exec", args, 1)
+		if err != nil {
+			return err
+		}
+		for _, el := range arr.Elements {
+			s, ok := el.(*object.String)
+			if !ok {
+				return newError("the arguments of `exec` must be strings but got %s", el.Type())
+			}
+			cmdArgs = append(cmdArgs, s.Value)
+		}
+	}
+	if err := allowed(env, "exec", AllowExec); err != nil {
+		return err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(name.Value, cmdArgs...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	status := 0
+	if err := cmd.Run(); err != nil {
+		// a command that ran and failed is a result with its status, one that could not start is an error.
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return newError("exec: %s", err)
+		}
+		status = exitErr.ExitCode()
+	}
+
+	hash := object.NewHash()
+	for _, pair := range []struct {
+		key   string
+		value object.Object
+	}{
+		{"stdout", &object.String{Value: stdout.String()}},
+		{"stderr", &object.String{Value: stderr.String()}},
+		{"status", &object.Integer{Value: int64(status)}},
+	} {
+		key := &object.String{Value: pair.key}
+		hash.Set(key.HashKey(), object.HashPair{Key: key, Value: pair.value})
+	}
+	return hash
+}
@@ -0,0 +1,68 @@
+package evaluator
+
+import (
+	"os"
+	"testing"
+
+	"go-interpreter-lexer/object"
+)
+
+func TestProcessBuiltins(t *testing.T) {
+	os.Setenv("MONKEY_TEST_VAR", "banana")
+	defer os.Unsetenv("MONKEY_TEST_VAR")
+	cfg := Config{Process: Process{Args: []string{"-v", "input.txt"}, Allow: AllowAll}}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`args()`, "[-v, input.txt]"},
+		{`[env("MONKEY_TEST_VAR"), env("MONKEY_TEST_UNSET")]`, "[banana, null]"},
+		{`exec("echo", ["hello", "monkey"])`, "{stdout:hello monkey\n, stderr:, status:0}"},
+		{`exec("sh", ["-c", "echo oops >&2; exit 3"])`, "{stdout:, stderr:oops\n, status:3}"},
+		{`exec("true").status`, "0"},
+		{`exec("monkey-no-such-command")`, `exec: exec: "monkey-no-such-command": executable file not found in $PATH`},
+		{`exec("echo", [1])`, "the arguments of `exec` must be strings but got INTEGER"},
+		{`exit(300)`, "`exit` code must be from 0 to 255, got 300"},
+		{`env(1)`, "argument 1 for `env` is suppose to be a string but got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testInspectWith(t, cfg, tt.input, tt.expected)
+	}
+}
+
+func TestExit(t *testing.T) {
+	cfg := Config{Process: Process{Allow: AllowExit}}
+	tests := []struct {
+		input string
+		code  int
+	}{
+		{`let a = 1; exit(3); a + 1`, 3},
+		{`let f = fn() { map([1], fn(x) { exit() }) }; f(); 1`, 0},
+		{`assertError(fn() { exit(4) })`, 4},
+		{`if (exit(5)) { 1 } else { 2 }`, 5},
+	}
+
+	for _, tt := range tests {
+		env := NewEnvironment(cfg)
+		DeclareAssertions(env)
+		err, ok := testEvalIn(env, tt.input).(*object.Error)
+		if !ok || !err.Exit || err.Code != tt.code {
+			t.Errorf("%q: expected exit %d, got %v", tt.input, tt.code, err)
+		}
+	}
+}
+
+func TestProcessDenied(t *testing.T) {
+	cfg := Config{Process: Process{Allow: AllowArgs}}
+	testInspectWith(t, cfg, `args()`, "[]")
+	testInspectWith(t, cfg, `env("HOME")`, "`env` is not allowed by the host")
+	testInspectWith(t, cfg, `exit(1)`, "`exit` is not allowed by the host")
+	testInspectWith(t, cfg, `exit(300)`, "`exit` is not allowed by the host")
+	testInspectWith(t, cfg, `exec("echo")`, "`exec` is not allowed by the host")
+
+	// an environment made without a config allows nothing.
+	testInspect(t, `args()`, "`args` is not allowed by the host")
+	testInspect(t, `exec("echo")`, "`exec` is not allowed by the host")
+}
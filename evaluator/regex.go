@@ -0,0 +1,219 @@
+package evaluator
+
+import (
+	"regexp"
+
+	"go-interpreter-lexer/object"
+)
+
+// the regex module wraps Go's regexp, a pattern is a regex made by regex.compile or a string compiled on each call.
+func init() {
+	modules["regex"] = &object.Module{Name: "regex",
+		Doc: "regex matches strings against regular expressions with the syntax of Go's regexp, regex.compile(pattern) makes one.",
+		Members: map[string]*object.Builtin{
+			"compile": {Fn: compileRegex,
+				Doc: "regex.compile(pattern) returns a regex for pattern, an invalid pattern is an error. Monkey strings have no escapes, \"\\d+\" is the pattern \\d+.",
+			},
+			"match": {Fn: matchRegex,
+				Doc: "regex.match(re, s) reports whether re matches anywhere in s, anchor the pattern with ^ and $ to match all of s.",
+			},
+			"find": {Fn: findRegex,
+				Doc: "regex.find(re, s) returns the first match of re in s, null if there is none.",
+			},
+			"findAll": {Fn: findAllRegex,
+				Doc: "regex.findAll(re, s, n) returns an array of the matches of re in s, n is optional and limits the number of matches.",
+			},
+			"captures": {Fn: captures,
+				Doc: "regex.captures(re, s) returns the groups of the first match, an array with the whole match first or a hash of the named groups when re has names. A group that did not take part is null, no match is null.",
+			},
+			"replace": {Fn: replaceRegex,
+				Doc: "regex.replace(re, s, repl) replaces every match of re in s. repl is a string in which $1 or ${name} is a group, or fn(match) returning the replacement.",
+			},
+			"split": {Fn: splitRegex,
+				Doc: "regex.split(re, s, n) splits s around the matches of re, n is optional and limits the number of parts.",
+			},
+		},
+	}
+}
+
+// regexArg returns argument i as a regular expression, compiling it when it is a string.
+func regexArg(name string, args []object.Object, i int) (*regexp.Regexp, *object.Error) {
+	switch arg := args[i].(type) {
+	case *object.Regex:
+		return arg.Regexp, nil
+	case *object.String:
+		re, err := regexp.Compile(arg.Value)
+		if err != nil {
+			return nil, newError("regex.%s: %s", name, err)
+		}
+		return re, nil
+	}
+	return nil, newError("argument %d for `regex.%s` is suppose to be a regex or a string but got %s", i+1, name, args[i].Type())
+}
+
+/*
+	regexArgs checks the arguments shared by the module, a pattern, a string and up to optional more arguments
+	which the caller checks.
+*/
+func regexArgs(name string, args []object.Object, optional int) (*regexp.Regexp, string, *object.Error) {
+	if len(args) < 2 || len(args) > 2+optional {
+		want := "2"
+		if optional > 0 {
+			want = "2 or 3"
+		}
+		return nil, "", wrongArguments("regex."+name, len(args), want)
+	}
+	re, err := regexArg(name, args, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	s, ok := args[1].(*object.String)
+	if !ok {
+		return nil, "", newError("argument 2 for `regex.%s` is suppose to be a string but got %s", name, args[1].Type())
+	}
+	return re, s.Value, nil
+}
+
+// limitArg returns the optional limit at index 2, -1 for no limit like the n of Go's regexp.
+func limitArg(name string, args []object.Object) (int, *object.Error) {
+	if len(args) < 3 {
+		return -1, nil
+	}
+	n, err := integerArg("regex."+name, args, 2)
+	if err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
+
+func compileRegex(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return wrongArguments("regex.compile", len(args), "1")
+	}
+	if _, ok := args[0].(*object.String); !ok {
+		return newError("argument 1 for `regex.compile` is suppose to be a string but got %s", args[0].Type())
+	}
+	re, err := regexArg("compile", args, 0)
+	if err != nil {
+		return err
+	}
+	return &object.Regex{Regexp: re}
+}
+
+func matchRegex(env *object.Environment, args ...object.Object) object.Object {
+	re, s, err := regexArgs("match", args, 0)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(re.MatchString(s))
+}
+
+func findRegex(env *object.Environment, args ...object.Object) object.Object {
+	re, s, err := regexArgs("find", args, 0)
+	if err != nil {
+		return err
+	}
+	loc := re.FindStringIndex(s)
+	if loc == nil {
+		return NULL
+	}
+	return &object.String{Value: s[loc[0]:loc[1]]}
+}
+
+func findAllRegex(env *object.Environment, args ...object.Object) object.Object {
+	re, s, err := regexArgs("findAll", args, 1)
+	if err != nil {
+		return err
+	}
+	n, err := limitArg("findAll", args)
+	if err != nil {
+		return err
+	}
+	return stringArray(re.FindAllString(s, n))
+}
+
+func captures(env *object.Environment, args ...object.Object) object.Object {
+	re, s, err := regexArgs("captures", args, 0)
+	if err != nil {
+		return err
+	}
+	loc := re.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return NULL
+	}
+	group := func(i int) object.Object {
+		if loc[2*i] < 0 {
+			return NULL
+		}
+		return &object.String{Value: s[loc[2*i]:loc[2*i+1]]}
+	}
+	names := re.SubexpNames()
+	named := false
+	for _, name := range names {
+		named = named || name != ""
+	}
+	if !named {
+		elements := make([]object.Object, len(names))
+		for i := range names {
+			elements[i] = group(i)
+		}
+		return &object.Array{Elements: elements}
+	}
+	hash := object.NewHash()
+	for i, name := range names {
+		if name != "" {
+			key := &object.String{Value: name}
+			hash.Set(key.HashKey(), object.HashPair{Key: key, Value: group(i)})
+		}
+	}
+	return hash
+}
+
+func replaceRegex(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 3 {
+		return wrongArguments("regex.replace", len(args), "3")
+	}
+	re, s, err := regexArgs("replace", args[:2], 0)
+	if err != nil {
+		return err
+	}
+	switch repl := args[2].(type) {
+	case *object.String:
+		return &object.String{Value: re.ReplaceAllString(s, repl.Value)}
+	case *object.Function, *object.Builtin:
+		// the first error a callback returns stops the replacing, the matches after it are left as they are.
+		var failed object.Object
+		result := re.ReplaceAllStringFunc(s, func(match string) string {
+			if failed != nil {
+				return match
+			}
+			value := callback(env, repl, &object.String{Value: match})
+			str, ok := value.(*object.String)
+			if !ok {
+				failed = value
+				if !isError(value) {
+					failed = newError("the function given to `regex.replace` must return a string but returned %s", value.Type())
+				}
+				return match
+			}
+			return str.Value
+		})
+		if failed != nil {
+			return failed
+		}
+		return &object.String{Value: result}
+	}
+	return newError("argument 3 for `regex.replace` is suppose to be a string or a function but got %s", args[2].Type())
+}
+
+func splitRegex(env *object.Environment, args ...object.Object) object.Object {
+	re, s, err := regexArgs("split", args, 1)
+	if err != nil {
+		return err
+	}
+	n, err := limitArg("split", args)
+	if err != nil {
+		return err
+	}
+	return stringArray(re.Split(s, n))
+}
@@ -0,0 +1,44 @@
+package evaluator
+
+import "testing"
+
+func TestRegexModule(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`regex.compile("\d+")`, `/\d+/`},
+		{`let re = regex.compile("^[a-z]+@[a-z]+\.com$"); [regex.match(re, "ann@mail.com"), regex.match(re, "ann@mail.org")]`, "[true, false]"},
+		{`regex.match("b", "abc")`, "true"},
+		{`[regex.find("\d+", "abc 123 45"), regex.find("\d+", "abc")]`, "[123, null]"},
+		{`regex.findAll("\d+", "1 22 333")`, "[1, 22, 333]"},
+		{`regex.findAll("\d+", "1 22 333", 2)`, "[1, 22]"},
+		{`regex.findAll("x", "abc")`, "[]"},
+		{`regex.captures("(\d+)-(\d+)", "call 555-1234")`, "[555-1234, 555, 1234]"},
+		{`regex.captures("(a)|(b)", "b")`, "[b, null, b]"},
+		{`regex.captures("(?P<year>\d{4})-(?P<month>\d{2})", "on 2024-05-01")`, "{year:2024, month:05}"},
+		{`regex.captures("(?P<year>\d{4})-(?P<month>\d{2})", "today").year`, "member access not supported: NULL"},
+		{`regex.captures("\d", "abc")`, "null"},
+		{`regex.replace("(\w+)@(\w+)", "ann@mail bob@web", "$2:$1")`, "mail:ann web:bob"},
+		{`regex.replace("(?P<n>\d+)", "a1b22", "<${n}>")`, "a<1>b<22>"},
+		{`regex.replace("\d+", "a1b22", fn(m) { strings.repeat("#", len(m)) })`, "a#b##"},
+		{`regex.replace("\d+", "a1b22", strings.upper)`, "a1b22"},
+		{`regex.split(",\s*", "a, b,c")`, "[a, b, c]"},
+		{`regex.split(",", "a,b,c", 2)`, "[a, b,c]"},
+
+		{`regex.compile("(a")`, "regex.compile: error parsing regexp: missing closing ): `(a`"},
+		{`regex.match("[", "a")`, "regex.match: error parsing regexp: missing closing ]: `[`"},
+		{`regex.compile(1)`, "argument 1 for `regex.compile` is suppose to be a string but got INTEGER"},
+		{`regex.find(1, "a")`, "argument 1 for `regex.find` is suppose to be a regex or a string but got INTEGER"},
+		{`regex.find("a", 1)`, "argument 2 for `regex.find` is suppose to be a string but got INTEGER"},
+		{`regex.findAll("a", "a", "b")`, "argument 3 for `regex.findAll` is suppose to be an integer but got STRING"},
+		{`regex.match("a")`, "wrong number of arguments to `regex.match` got 1, wanted 2"},
+		{`regex.replace("\d", "a1", fn(m) { 1 })`, "the function given to `regex.replace` must return a string but returned INTEGER"},
+		{`regex.replace("\d", "a1", fn(m) { 1 + true })`, "type mismatch: INTEGER + BOOLEAN"},
+		{`regex.replace("\d", "a1", 1)`, "argument 3 for `regex.replace` is suppose to be a string or a function but got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, tt.input, tt.expected)
+	}
+}
@@ -0,0 +1,85 @@
+package evaluator
+
+import (
+	"bufio"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"go-interpreter-lexer/object"
+)
+
+/*
+Config is what a host sets up for the programs it evaluates in an environment, see NewEnvironment. Every
+environment has its own, so programs with different configs can run at the same time.
+*/
+type Config struct {
+	// IO is where puts and the io module read and write.
+	IO IO
+	// Process is what programs may do with the process running them, nothing unless the host allows it.
+	Process Process
+	// Clock is where time.now and time.since read the time, a test can freeze the time a program sees
+	// with a function returning a fixed time. nil is the system clock.
+	Clock func() time.Time
+	// Hook is notified as programs run, nil evaluates them without one. See SetHook to change it later.
+	Hook Hook
+	// Seed makes math.random return the same numbers for the same seed so a run can be repeated, nil
+	// seeds it from the time.
+	Seed *int64
+}
+
+// NewEnvironment returns a global environment whose programs are evaluated with cfg.
+func NewEnvironment(cfg Config) *object.Environment {
+	env := object.NewEnvironment()
+	env.SetHost(newState(cfg))
+	return env
+}
+
+// state is what one evaluation keeps apart from every other, it is the host of its outermost environment.
+type state struct {
+	// depth is the number of function calls in progress, see MaxCallDepth.
+	depth int
+	// random is where math.random draws from.
+	random  *rand.Rand
+	clock   func() time.Time
+	io      IO
+	process Process
+	hook    Hook
+	// lines buffers io.Stdin for io.readLine, it is kept between calls so no input is lost.
+	lines *bufio.Reader
+}
+
+func newState(cfg Config) *state {
+	seed := time.Now().UnixNano()
+	if cfg.Seed != nil {
+		seed = *cfg.Seed
+	}
+	s := &state{random: rand.New(rand.NewSource(seed)), clock: cfg.Clock, io: cfg.IO, process: cfg.Process, hook: cfg.Hook}
+	if s.clock == nil {
+		s.clock = time.Now
+	}
+	if s.io.Stdout == nil {
+		s.io.Stdout = io.Discard
+	}
+	if s.io.Stdin == nil {
+		s.io.Stdin = strings.NewReader("")
+	}
+	s.lines = bufio.NewReader(s.io.Stdin)
+	return s
+}
+
+/*
+stateOf returns the state of the evaluation env belongs to. An environment that was not made by
+NewEnvironment gets the default config the first time it is asked for, programs use the standard
+streams of the process and nothing else.
+*/
+func stateOf(env *object.Environment) *state {
+	if s, ok := env.Host().(*state); ok {
+		return s
+	}
+	s := newState(Config{IO: IO{Stdout: os.Stdout, Stdin: os.Stdin}})
+	env.SetHost(s)
+	return s
+}
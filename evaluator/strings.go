@@ -0,0 +1,378 @@
+package evaluator
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"go-interpreter-lexer/object"
+)
+
+// the strings module works on bytes like len and indexing, chars is the way to get at the characters.
+func init() {
+	modules["strings"] = &object.Module{Name: "strings",
+		Doc: "strings holds functions for working with strings, strings.upper(s) for example.",
+		Members: map[string]*object.Builtin{
+			"split": {Fn: split,
+				Doc: "strings.split(s, sep) returns the parts of s between each sep, an empty sep splits s into its characters.",
+			},
+			"join": {Fn: join,
+				Doc: "strings.join(arr, sep) returns the strings of arr joined with sep between them.",
+			},
+			"trim": {Fn: trim("trim", strings.Trim, strings.TrimSpace),
+				Doc: "strings.trim(s, cutset) returns s without the leading and trailing characters in cutset, white space when cutset is left out.",
+			},
+			"trimLeft": {Fn: trim("trimLeft", strings.TrimLeft, func(s string) string { return strings.TrimLeft(s, " \t\r\n") }),
+				Doc: "strings.trimLeft(s, cutset) returns s without the leading characters in cutset, white space when cutset is left out.",
+			},
+			"trimRight": {Fn: trim("trimRight", strings.TrimRight, func(s string) string { return strings.TrimRight(s, " \t\r\n") }),
+				Doc: "strings.trimRight(s, cutset) returns s without the trailing characters in cutset, white space when cutset is left out.",
+			},
+			"upper": {Fn: mapString("upper", strings.ToUpper),
+				Doc: "strings.upper(s) returns s with every letter in upper case.",
+			},
+			"lower": {Fn: mapString("lower", strings.ToLower),
+				Doc: "strings.lower(s) returns s with every letter in lower case.",
+			},
+			"contains": {Fn: testString("contains", strings.Contains),
+				Doc: "strings.contains(s, sub) reports whether sub is within s.",
+			},
+			"startsWith": {Fn: testString("startsWith", strings.HasPrefix),
+				Doc: "strings.startsWith(s, prefix) reports whether s begins with prefix.",
+			},
+			"endsWith": {Fn: testString("endsWith", strings.HasSuffix),
+				Doc: "strings.endsWith(s, suffix) reports whether s ends with suffix.",
+			},
+			"indexOf": {Fn: indexOf,
+				Doc: "strings.indexOf(s, sub) returns the byte index of the first sub in s, -1 if there is none.",
+			},
+			"replace": {Fn: replace,
+				Doc: "strings.replace(s, old, new) returns s with every old replaced by new.",
+			},
+			"repeat": {Fn: repeat,
+				Doc: "strings.repeat(s, n) returns s repeated n times.",
+			},
+			"substring": {Fn: substring,
+				Doc: "strings.substring(s, start, end) returns the bytes from start up to but not including end, end is optional and negative indexes count from the end.",
+			},
+			"padLeft": {Fn: pad("padLeft", true),
+				Doc: "strings.padLeft(s, width, pad) returns s with pad added to the front until it is width long, pad is a space when left out.",
+			},
+			"padRight": {Fn: pad("padRight", false),
+				Doc: "strings.padRight(s, width, pad) returns s with pad added to the end until it is width long, pad is a space when left out.",
+			},
+			"chars": {Fn: chars,
+				Doc: "strings.chars(s) returns an array of the characters of s.",
+			},
+			"format": {Fn: format("format"),
+				Doc: "strings.format(f, args...) returns the args formatted by the verbs of f as Go's fmt.Sprintf does, %d, %s, %v, %q and %5.2x for example.",
+			},
+			"sprintf": {Fn: format("sprintf"),
+				Doc: "strings.sprintf(f, args...) is the same as strings.format.",
+			},
+		},
+	}
+}
+
+// stringArg returns the argument at i if it is a string, name is the member of the strings module.
+func stringArg(name string, args []object.Object, i int) (string, *object.Error) {
+	s, ok := args[i].(*object.String)
+	if !ok {
+		return "", newError("argument %d for `strings.%s` is suppose to be a string but got %s", i+1, name, args[i].Type())
+	}
+	return s.Value, nil
+}
+
+// stringArgs checks that there are want arguments and that all of them are strings.
+func stringArgs(name string, args []object.Object, want int) ([]string, *object.Error) {
+	if len(args) != want {
+		return nil, wrongArguments("strings."+name, len(args), fmt.Sprint(want))
+	}
+	values := make([]string, want)
+	for i := range args {
+		s, err := stringArg(name, args, i)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = s
+	}
+	return values, nil
+}
+
+func stringArray(values []string) *object.Array {
+	elements := make([]object.Object, len(values))
+	for i, v := range values {
+		elements[i] = &object.String{Value: v}
+	}
+	return &object.Array{Elements: elements}
+}
+
+func split(env *object.Environment, args ...object.Object) object.Object {
+	values, err := stringArgs("split", args, 2)
+	if err != nil {
+		return err
+	}
+	return stringArray(strings.Split(values[0], values[1]))
+}
+
+func join(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return wrongArguments("strings.join", len(args), "2")
+	}
+	arr, err := arrayArg("strings.join", args, 0)
+	if err != nil {
+		return err
+	}
+	sep, err := stringArg("join", args, 1)
+	if err != nil {
+		return err
+	}
+	parts := make([]string, len(arr.Elements))
+	for i, el := range arr.Elements {
+		s, ok := el.(*object.String)
+		if !ok {
+			return newError("`strings.join` needs an array of strings, element %d is %s", i, el.Type())
+		}
+		parts[i] = s.Value
+	}
+	return &object.String{Value: strings.Join(parts, sep)}
+}
+
+// trim builds trim, trimLeft and trimRight, cut takes the cutset argument and space is used without it.
+func trim(name string, cut func(s, cutset string) string, space func(s string) string) object.BuiltInFunction {
+	return func(env *object.Environment, args ...object.Object) object.Object {
+		if len(args) < 1 || len(args) > 2 {
+			return wrongArguments("strings."+name, len(args), "1 or 2")
+		}
+		s, err := stringArg(name, args, 0)
+		if err != nil {
+			return err
+		}
+		if len(args) == 1 {
+			return &object.String{Value: space(s)}
+		}
+		cutset, err := stringArg(name, args, 1)
+		if err != nil {
+			return err
+		}
+		return &object.String{Value: cut(s, cutset)}
+	}
+}
+
+func mapString(name string, f func(string) string) object.BuiltInFunction {
+	return func(env *object.Environment, args ...object.Object) object.Object {
+		values, err := stringArgs(name, args, 1)
+		if err != nil {
+			return err
+		}
+		return &object.String{Value: f(values[0])}
+	}
+}
+
+func testString(name string, f func(s, sub string) bool) object.BuiltInFunction {
+	return func(env *object.Environment, args ...object.Object) object.Object {
+		values, err := stringArgs(name, args, 2)
+		if err != nil {
+			return err
+		}
+		return nativeBoolToBooleanObject(f(values[0], values[1]))
+	}
+}
+
+func indexOf(env *object.Environment, args ...object.Object) object.Object {
+	values, err := stringArgs("indexOf", args, 2)
+	if err != nil {
+		return err
+	}
+	return &object.Integer{Value: int64(strings.Index(values[0], values[1]))}
+}
+
+func replace(env *object.Environment, args ...object.Object) object.Object {
+	values, err := stringArgs("replace", args, 3)
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: strings.ReplaceAll(values[0], values[1], values[2])}
+}
+
+// maxString is the longest string repeat and the pad functions build.
+const maxString = 1 << 24
+
+func repeat(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return wrongArguments("strings.repeat", len(args), "2")
+	}
+	s, err := stringArg("repeat", args, 0)
+	if err != nil {
+		return err
+	}
+	n, err := integerArg("strings.repeat", args, 1)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return newError("`strings.repeat` count cannot be negative, got %d", n)
+	}
+	if len(s) > 0 && n > maxString/int64(len(s)) {
+		return newError("`strings.repeat` of %d bytes is too large", n*int64(len(s)))
+	}
+	return &object.String{Value: strings.Repeat(s, int(n))}
+}
+
+func substring(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) < 2 || len(args) > 3 {
+		return wrongArguments("strings.substring", len(args), "2 or 3")
+	}
+	s, err := stringArg("substring", args, 0)
+	if err != nil {
+		return err
+	}
+	length := int64(len(s))
+	start, err := integerArg("strings.substring", args, 1)
+	if err != nil {
+		return err
+	}
+	end := length
+	if len(args) == 3 {
+		if end, err = integerArg("strings.substring", args, 2); err != nil {
+			return err
+		}
+	}
+	start, end = clampIndex(start, length), clampIndex(end, length)
+	if start >= end {
+		return &object.String{Value: ""}
+	}
+	return &object.String{Value: s[start:end]}
+}
+
+// pad builds padLeft and padRight, the padding is cut short when it does not fit the width exactly.
+func pad(name string, left bool) object.BuiltInFunction {
+	return func(env *object.Environment, args ...object.Object) object.Object {
+		if len(args) < 2 || len(args) > 3 {
+			return wrongArguments("strings."+name, len(args), "2 or 3")
+		}
+		s, err := stringArg(name, args, 0)
+		if err != nil {
+			return err
+		}
+		width, err := integerArg("strings."+name, args, 1)
+		if err != nil {
+			return err
+		}
+		padding := " "
+		if len(args) == 3 {
+			if padding, err = stringArg(name, args, 2); err != nil {
+				return err
+			}
+		}
+		if padding == "" {
+			return newError("`strings.%s` padding cannot be empty", name)
+		}
+		if width > maxString {
+			return newError("`strings.%s` to %d bytes is too large", name, width)
+		}
+		missing := int(width) - len(s)
+		if missing <= 0 {
+			return &object.String{Value: s}
+		}
+		fill := strings.Repeat(padding, missing/len(padding)+1)[:missing]
+		if left {
+			return &object.String{Value: fill + s}
+		}
+		return &object.String{Value: s + fill}
+	}
+}
+
+func chars(env *object.Environment, args ...object.Object) object.Object {
+	values, err := stringArgs("chars", args, 1)
+	if err != nil {
+		return err
+	}
+	s := values[0]
+	parts := make([]string, 0, utf8.RuneCountInString(s))
+	for len(s) > 0 {
+		_, size := utf8.DecodeRuneInString(s)
+		parts = append(parts, s[:size])
+		s = s[size:]
+	}
+	return stringArray(parts)
+}
+
+/*
+	format passes integers, big integers, strings and booleans to fmt.Sprintf as Go values, decimals as a
+	decimalArg and anything else as its printed form.
+*/
+func format(name string) object.BuiltInFunction {
+	return func(env *object.Environment, args ...object.Object) object.Object {
+		if len(args) < 1 {
+			return wrongArguments("strings."+name, len(args), "at least 1")
+		}
+		f, err := stringArg(name, args, 0)
+		if err != nil {
+			return err
+		}
+		values := make([]interface{}, len(args)-1)
+		for i, arg := range args[1:] {
+			switch arg := arg.(type) {
+			case *object.Integer:
+				values[i] = arg.Value
+			case *object.BigInt:
+				values[i] = arg.Value
+			case *object.Decimal:
+				values[i] = decimalArg{arg}
+			case *object.String:
+				values[i] = arg.Value
+			case *object.Boolean:
+				values[i] = arg.Bool
+			default:
+				values[i] = arg.Inspect()
+			}
+		}
+		return &object.String{Value: fmt.Sprintf(f, values...)}
+	}
+}
+
+/*
+	decimalArg formats a decimal for strings.format. %f rounds it to the precision, 6 places without one, halves
+	away from zero like decimal(x, places), %e and %g go through a big.Float and any other verb formats its
+	printed form, so %s and %v show 1.50 as it was written.
+*/
+type decimalArg struct {
+	d *object.Decimal
+}
+
+func (a decimalArg) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'f', 'F':
+		places, ok := s.Precision()
+		if !ok {
+			places = 6
+		}
+		digits, sign := roundDecimalTo(a.d, int32(places)).Inspect(), ""
+		if strings.HasPrefix(digits, "-") {
+			digits, sign = digits[1:], "-"
+		} else if s.Flag('+') {
+			sign = "+"
+		} else if s.Flag(' ') {
+			sign = " "
+		}
+		width, _ := s.Width()
+		pad := width - len(sign) - len(digits)
+		switch {
+		case pad <= 0:
+			fmt.Fprint(s, sign+digits)
+		case s.Flag('-'):
+			fmt.Fprint(s, sign+digits+strings.Repeat(" ", pad))
+		case s.Flag('0'):
+			fmt.Fprint(s, sign+strings.Repeat("0", pad)+digits)
+		default:
+			fmt.Fprint(s, strings.Repeat(" ", pad)+sign+digits)
+		}
+	case 'e', 'E', 'g', 'G':
+		f, _ := new(big.Float).SetPrec(uint(a.d.Unscaled.BitLen()) + 64).SetString(a.d.Inspect())
+		f.Format(s, verb)
+	default:
+		fmt.Fprintf(s, fmt.FormatString(s, verb), a.d.Inspect())
+	}
+}
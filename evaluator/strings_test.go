@@ -0,0 +1,90 @@
+package evaluator
+
+import "testing"
+
+func TestStringsModule(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`strings.split("a,b,,c", ",")`, "[a, b, , c]"},
+		{`strings.split("héllo", "")`, "[h, é, l, l, o]"},
+		{`strings.join(["a", "b", "c"], "-")`, "a-b-c"},
+		{`strings.join([], "-")`, ""},
+		{"strings.trim(\"  a b \n\")", "a b"},
+		{`strings.trim("xxaxx", "x")`, "a"},
+		{`strings.trimLeft("  a  ") + "|"`, "a  |"},
+		{`"|" + strings.trimRight("  a  ")`, "|  a"},
+		{`[strings.upper("abC"), strings.lower("ABc")]`, "[ABC, abc]"},
+		{`[strings.contains("monkey", "key"), strings.contains("monkey", "dog")]`, "[true, false]"},
+		{`[strings.startsWith("monkey", "mon"), strings.endsWith("monkey", "mon")]`, "[true, false]"},
+		{`[strings.indexOf("banana", "na"), strings.indexOf("banana", "x")]`, "[2, -1]"},
+		{`strings.replace("a-b-c", "-", "+")`, "a+b+c"},
+		{`strings.repeat("ab", 3)`, "ababab"},
+		{`strings.substring("monkey", 1, 3)`, "on"},
+		{`strings.substring("monkey", -3)`, "key"},
+		{`strings.substring("monkey", 4, 2)`, ""},
+		{`strings.padLeft("7", 3, "0")`, "007"},
+		{`strings.padRight("ab", 5, "xy") + "|"`, "abxyx|"},
+		{`strings.padLeft("abc", 2)`, "abc"},
+		{`strings.chars("añb")`, "[a, ñ, b]"},
+		{`strings.format("%s is %d, %v %q", "x", 5, true, "y")`, `x is 5, true "y"`},
+		{`strings.format("%05d|%-3s|%x", 42, "a", 255)`, "00042|a  |ff"},
+		{`strings.sprintf("%v", [1, "a"])`, "[1, a]"},
+		{`strings.format("%d %s %x", 100000000000000000000n, 1.50, 255n)`, "100000000000000000000 1.50 ff"},
+		{`strings.format("%.2f|%f|%8.1f|%-6.1f|%+.0f|%07.2f", 2.345, 1.5, -2.25, 0.05, 2.5, -1.5)`, "2.35|1.500000|    -2.3|0.1   |+3|-001.50"},
+		{`strings.format("%.3e %g", 12345.6, 0.25)`, "1.235e+04 0.25"},
+		{`let upper = strings.upper; upper("a")`, "A"},
+		{"strings", "module strings"},
+
+		{`strings.nope`, "module strings has no member nope"},
+		{`strings.upper(1)`, "argument 1 for `strings.upper` is suppose to be a string but got INTEGER"},
+		{`strings.split("a")`, "wrong number of arguments to `strings.split` got 1, wanted 2"},
+		{`strings.join([1], ",")`, "`strings.join` needs an array of strings, element 0 is INTEGER"},
+		{`strings.repeat("a", -1)`, "`strings.repeat` count cannot be negative, got -1"},
+		{`strings.repeat("ab", 100000000)`, "`strings.repeat` of 200000000 bytes is too large"},
+		{`strings.padLeft("a", 3, "")`, "`strings.padLeft` padding cannot be empty"},
+		{`strings.format()`, "wrong number of arguments to `strings.format` got 0, wanted at least 1"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, tt.input, tt.expected)
+	}
+}
+
+func TestMemberExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let h = {"name": "monkey"}; h.name`, "monkey"},
+		{`{"a": {"b": 2}}.a.b`, "2"},
+		{`{"a": 1}.b`, "null"},
+		{`let strings = {"upper": 1}; strings.upper`, "1"},
+		{`5.a`, "member access not supported: INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, tt.input, tt.expected)
+	}
+}
+
+func TestStringOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"a" + "b" == "ab"`, "true"},
+		{`let s = "x"; [s == "x", s != "x", "x" != "y"]`, "[true, false, true]"},
+		{`["apple" < "banana", "b" > "abc", "a" < "a"]`, "[true, true, false]"},
+		{`"abc"[0] + "abc"[2]`, "ac"},
+		{`["abc"[3], "abc"[-1]]`, "[null, null]"},
+		{`"a" == 1`, "false"},
+		{`"a" < 1`, "type mismatch: STRING < INTEGER"},
+		{`"a" - "b"`, "unknown operator: STRING - STRING"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, tt.input, tt.expected)
+	}
+}
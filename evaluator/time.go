@@ -0,0 +1,336 @@
+package evaluator
+
+import (
+	"time"
+	// the time zone database is built in so time.in works on machines without one.
+	_ "time/tzdata"
+
+	"go-interpreter-lexer/object"
+)
+
+// the time module works with instants and durations, see object.Time and object.Duration.
+func init() {
+	modules["time"] = &object.Module{Name: "time",
+		Doc: "time reads the clock and works with times and durations, time.now() - start is a duration and a time plus a duration a time.",
+		Members: map[string]*object.Builtin{
+			"now": {Fn: now,
+				Doc: "time.now() returns the current time.",
+			},
+			"since": {Fn: since,
+				Doc: "time.since(t) returns the duration from t to now.",
+			},
+			"parse": {Fn: parseTime,
+				Doc: "time.parse(layout, s, zone) parses s with a Go layout such as \"2006-01-02 15:04\" or one of RFC3339, RFC1123, DateTime, DateOnly, TimeOnly and Kitchen. zone is optional and is the zone of a time without an offset, UTC by default.",
+			},
+			"format": {Fn: formatTime,
+				Doc: "time.format(t, layout) returns t written with a layout, see time.parse.",
+			},
+			"in": {Fn: inZone,
+				Doc: "time.in(t, zone) returns the same instant as t shown in a zone such as \"Europe/Paris\", \"UTC\" or \"Local\".",
+			},
+			"parts": {Fn: timeParts,
+				Doc: "time.parts(t) returns a hash of the year, month, day, hour, minute, second, nanosecond, weekday and zone of t.",
+			},
+			"unix": {Fn: unix("unix", time.Time.Unix),
+				Doc: "time.unix(t) returns t as the number of seconds since January 1, 1970 UTC.",
+			},
+			"unixMilli": {Fn: unix("unixMilli", time.Time.UnixMilli),
+				Doc: "time.unixMilli(t) returns t as the number of milliseconds since January 1, 1970 UTC.",
+			},
+			"fromUnix": {Fn: fromUnix("fromUnix", func(n int64) time.Time { return time.Unix(n, 0) }),
+				Doc: "time.fromUnix(n) returns the time n seconds after January 1, 1970 UTC, in UTC.",
+			},
+			"fromUnixMilli": {Fn: fromUnix("fromUnixMilli", time.UnixMilli),
+				Doc: "time.fromUnixMilli(n) returns the time n milliseconds after January 1, 1970 UTC, in UTC.",
+			},
+			"duration": {Fn: duration,
+				Doc: "time.duration(s) parses a duration such as \"1h30m\", \"250ms\" or \"-2s\". d / time.duration(\"1s\") is the whole seconds of d.",
+			},
+		},
+	}
+}
+
+// layouts are the names time.parse and time.format accept in place of a layout.
+var layouts = map[string]string{
+	"RFC3339":  time.RFC3339,
+	"RFC1123":  time.RFC1123,
+	"DateTime": time.DateTime,
+	"DateOnly": time.DateOnly,
+	"TimeOnly": time.TimeOnly,
+	"Kitchen":  time.Kitchen,
+}
+
+func layoutArg(name string, args []object.Object, i int) (string, *object.Error) {
+	s, ok := args[i].(*object.String)
+	if !ok {
+		return "", newError("argument %d for `time.%s` is suppose to be a string but got %s", i+1, name, args[i].Type())
+	}
+	if layout, ok := layouts[s.Value]; ok {
+		return layout, nil
+	}
+	return s.Value, nil
+}
+
+func timeArg(name string, args []object.Object, i int) (time.Time, *object.Error) {
+	t, ok := args[i].(*object.Time)
+	if !ok {
+		return time.Time{}, newError("argument %d for `time.%s` is suppose to be a time but got %s", i+1, name, args[i].Type())
+	}
+	return t.Value, nil
+}
+
+func zoneArg(name string, args []object.Object, i int) (*time.Location, *object.Error) {
+	s, ok := args[i].(*object.String)
+	if !ok {
+		return nil, newError("argument %d for `time.%s` is suppose to be a string but got %s", i+1, name, args[i].Type())
+	}
+	loc, err := time.LoadLocation(s.Value)
+	if err != nil {
+		return nil, newError("time.%s: unknown time zone %q", name, s.Value)
+	}
+	return loc, nil
+}
+
+func now(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return wrongArguments("time.now", len(args), "0")
+	}
+	return &object.Time{Value: stateOf(env).clock()}
+}
+
+func since(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return wrongArguments("time.since", len(args), "1")
+	}
+	t, err := timeArg("since", args, 0)
+	if err != nil {
+		return err
+	}
+	return &object.Duration{Value: stateOf(env).clock().Sub(t)}
+}
+
+func parseTime(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) < 2 || len(args) > 3 {
+		return wrongArguments("time.parse", len(args), "2 or 3")
+	}
+	layout, err := layoutArg("parse", args, 0)
+	if err != nil {
+		return err
+	}
+	s, ok := args[1].(*object.String)
+	if !ok {
+		return newError("argument 2 for `time.parse` is suppose to be a string but got %s", args[1].Type())
+	}
+	loc := time.UTC
+	if len(args) == 3 {
+		if loc, err = zoneArg("parse", args, 2); err != nil {
+			return err
+		}
+	}
+	t, parseErr := time.ParseInLocation(layout, s.Value, loc)
+	if parseErr != nil {
+		return newError("time.parse: %s", parseErr)
+	}
+	return &object.Time{Value: t}
+}
+
+func formatTime(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return wrongArguments("time.format", len(args), "2")
+	}
+	t, err := timeArg("format", args, 0)
+	if err != nil {
+		return err
+	}
+	layout, err := layoutArg("format", args, 1)
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: t.Format(layout)}
+}
+
+func inZone(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return wrongArguments("time.in", len(args), "2")
+	}
+	t, err := timeArg("in", args, 0)
+	if err != nil {
+		return err
+	}
+	loc, err := zoneArg("in", args, 1)
+	if err != nil {
+		return err
+	}
+	return &object.Time{Value: t.In(loc)}
+}
+
+func timeParts(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return wrongArguments("time.parts", len(args), "1")
+	}
+	t, err := timeArg("parts", args, 0)
+	if err != nil {
+		return err
+	}
+	zone, _ := t.Zone()
+	hash := object.NewHash()
+	for _, part := range []struct {
+		name  string
+		value object.Object
+	}{
+		{"year", &object.Integer{Value: int64(t.Year())}},
+		{"month", &object.Integer{Value: int64(t.Month())}},
+		{"day", &object.Integer{Value: int64(t.Day())}},
+		{"hour", &object.Integer{Value: int64(t.Hour())}},
+		{"minute", &object.Integer{Value: int64(t.Minute())}},
+		{"second", &object.Integer{Value: int64(t.Second())}},
+		{"nanosecond", &object.Integer{Value: int64(t.Nanosecond())}},
+		{"weekday", &object.String{Value: t.Weekday().String()}},
+		{"zone", &object.String{Value: zone}},
+	} {
+		key := &object.String{Value: part.name}
+		hash.Set(key.HashKey(), object.HashPair{Key: key, Value: part.value})
+	}
+	return hash
+}
+
+func unix(name string, f func(time.Time) int64) object.BuiltInFunction {
+	return func(env *object.Environment, args ...object.Object) object.Object {
+		if len(args) != 1 {
+			return wrongArguments("time."+name, len(args), "1")
+		}
+		t, err := timeArg(name, args, 0)
+		if err != nil {
+			return err
+		}
+		return &object.Integer{Value: f(t)}
+	}
+}
+
+func fromUnix(name string, f func(int64) time.Time) object.BuiltInFunction {
+	return func(env *object.Environment, args ...object.Object) object.Object {
+		if len(args) != 1 {
+			return wrongArguments("time."+name, len(args), "1")
+		}
+		n, err := integerArg("time."+name, args, 0)
+		if err != nil {
+			return err
+		}
+		return &object.Time{Value: f(n).UTC()}
+	}
+}
+
+func duration(env *object.Environment, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return wrongArguments("time.duration", len(args), "1")
+	}
+	s, ok := args[0].(*object.String)
+	if !ok {
+		return newError("argument 1 for `time.duration` is suppose to be a string but got %s", args[0].Type())
+	}
+	d, err := time.ParseDuration(s.Value)
+	if err != nil {
+		return newError("time.duration: %s", err)
+	}
+	return &object.Duration{Value: d}
+}
+
+func isTemporal(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Time, *object.Duration:
+		return true
+	}
+	return false
+}
+
+/*
+	evalTimeInfixExpression applies an operator when at least one operand is a time or a duration. Times
+	subtract to a duration and move by one, durations add, subtract, scale by integers and divide to an
+	integer. Times compare as instants, the same instant in two zones is equal.
+*/
+func evalTimeInfixExpression(operator string, left, right object.Object) object.Object {
+	switch l := left.(type) {
+	case *object.Time:
+		switch r := right.(type) {
+		case *object.Time:
+			if operator == "-" {
+				return &object.Duration{Value: l.Value.Sub(r.Value)}
+			}
+			return compareNumbers(operator, l.Value.Compare(r.Value), object.TIME_OBJ)
+		case *object.Duration:
+			switch operator {
+			case "+":
+				return &object.Time{Value: l.Value.Add(r.Value)}
+			case "-":
+				return &object.Time{Value: l.Value.Add(-r.Value)}
+			}
+		}
+	case *object.Duration:
+		switch r := right.(type) {
+		case *object.Duration:
+			return evalDurationInfixExpression(operator, l.Value, r.Value)
+		case *object.Time:
+			if operator == "+" {
+				return &object.Time{Value: r.Value.Add(l.Value)}
+			}
+		case *object.Integer:
+			if operator == "*" || operator == "/" {
+				return scaleDuration(operator, l.Value, r.Value)
+			}
+		}
+	case *object.Integer:
+		if r, ok := right.(*object.Duration); ok && operator == "*" {
+			return scaleDuration(operator, r.Value, l.Value)
+		}
+	}
+	switch {
+	case operator == "==":
+		return FALSE
+	case operator == "!=":
+		return TRUE
+	case left.Type() != right.Type():
+		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+	}
+	return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+}
+
+func evalDurationInfixExpression(operator string, a, b time.Duration) object.Object {
+	switch operator {
+	case "+", "-":
+		d, ok := checkedArithmetic(operator, int64(a), int64(b))
+		if !ok {
+			return newError("duration overflow: %s %s %s", a, operator, b)
+		}
+		return &object.Duration{Value: time.Duration(d)}
+	case "/", "%":
+		if b == 0 {
+			return newError("division by zero: %s %s %s", a, operator, b)
+		}
+		if operator == "%" {
+			return &object.Duration{Value: a % b}
+		}
+		return &object.Integer{Value: int64(a / b)}
+	}
+	cmp := 0
+	switch {
+	case a < b:
+		cmp = -1
+	case a > b:
+		cmp = 1
+	}
+	return compareNumbers(operator, cmp, object.DURATION_OBJ)
+}
+
+func scaleDuration(operator string, d time.Duration, n int64) object.Object {
+	if operator == "/" {
+		if n == 0 {
+			return newError("division by zero: %s / %d", d, n)
+		}
+		return &object.Duration{Value: d / time.Duration(n)}
+	}
+	scaled, ok := checkedArithmetic("*", int64(d), n)
+	if !ok {
+		return newError("duration overflow: %s * %d", d, n)
+	}
+	return &object.Duration{Value: time.Duration(scaled)}
+}
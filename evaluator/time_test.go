@@ -0,0 +1,64 @@
+package evaluator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeModule(t *testing.T) {
+	clock := func() time.Time { return time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC) }
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`time.now()`, "2024-05-01T09:30:00Z"},
+		{`time.since(time.parse("DateOnly", "2024-04-30"))`, "33h30m0s"},
+		{`time.parse("2006-01-02 15:04", "2024-02-29 18:45")`, "2024-02-29T18:45:00Z"},
+		{`time.parse("DateTime", "2024-07-01 12:00:00", "Europe/Paris")`, "2024-07-01T12:00:00+02:00"},
+		{`time.parse("RFC3339", "2024-01-15T08:00:00.5-05:00")`, "2024-01-15T08:00:00.5-05:00"},
+		{`time.format(time.now(), "Mon Jan 2 15:04")`, "Wed May 1 09:30"},
+		{`time.format(time.now(), "Kitchen")`, "9:30AM"},
+		{`time.in(time.now(), "Asia/Tokyo")`, "2024-05-01T18:30:00+09:00"},
+		{`time.in(time.now(), "Asia/Tokyo") == time.now()`, "true"},
+		{`time.parts(time.in(time.now(), "America/New_York"))`, "{year:2024, month:5, day:1, hour:5, minute:30, second:0, nanosecond:0, weekday:Wednesday, zone:EDT}"},
+		{`[time.unix(time.now()), time.unixMilli(time.fromUnix(1))]`, "[1714555800, 1000]"},
+		{`[time.fromUnix(0), time.fromUnixMilli(1500)]`, "[1970-01-01T00:00:00Z, 1970-01-01T00:00:01.5Z]"},
+
+		{`time.duration("1h30m")`, "1h30m0s"},
+		{`time.now() + time.duration("36h")`, "2024-05-02T21:30:00Z"},
+		{`time.duration("1h") + time.now()`, "2024-05-01T10:30:00Z"},
+		{`time.now() - time.duration("30m")`, "2024-05-01T09:00:00Z"},
+		{`time.now() - time.parse("DateOnly", "2024-05-01")`, "9h30m0s"},
+		{`[time.duration("1h") + time.duration("15m"), time.duration("1h") - time.duration("2h")]`, "[1h15m0s, -1h0m0s]"},
+		{`[time.duration("90s") * 2, 3 * time.duration("1m"), time.duration("1h") / 4]`, "[3m0s, 3m0s, 15m0s]"},
+		{`[time.duration("90m") / time.duration("1h"), time.duration("90m") % time.duration("1h")]`, "[1, 30m0s]"},
+		{`let d = time.now(); [d < d + time.duration("1s"), d > d, d == d, d != d - time.duration("1ns")]`, "[true, false, true, true]"},
+		{`[time.duration("1m") > time.duration("59s"), time.duration("1m") == time.duration("60s")]`, "[true, true]"},
+		{`[time.now() == 1, time.duration("1s") != "1s"]`, "[false, true]"},
+
+		{`time.now() + time.now()`, "unknown operator: TIME + TIME"},
+		{`time.now() + 1`, "type mismatch: TIME + INTEGER"},
+		{`time.duration("1s") / 0`, "division by zero: 1s / 0"},
+		{`time.duration("2000000h") * 1000000`, "duration overflow: 2000000h0m0s * 1000000"},
+		{`time.parse("DateOnly", "May 1")`, `time.parse: parsing time "May 1" as "2006-01-02": cannot parse "May 1" as "2006"`},
+		{`time.in(time.now(), "Mars/Olympus")`, `time.in: unknown time zone "Mars/Olympus"`},
+		{`time.duration("soon")`, `time.duration: time: invalid duration "soon"`},
+		{`time.format(1, "Kitchen")`, "argument 1 for `time.format` is suppose to be a time but got INTEGER"},
+		{`time.now(1)`, "wrong number of arguments to `time.now` got 1, wanted 0"},
+	}
+
+	for _, tt := range tests {
+		testInspectWith(t, Config{Clock: clock}, tt.input, tt.expected)
+	}
+}
+
+func TestClock(t *testing.T) {
+	frozen := NewEnvironment(Config{Clock: func() time.Time { return time.Unix(0, 0) }})
+	if got := testEvalIn(frozen, "time.unix(time.now())").Inspect(); got != "0" {
+		t.Errorf("the frozen clock gave %s", got)
+	}
+	if got := testEval("time.now() > time.fromUnix(0)").Inspect(); got != "true" {
+		t.Errorf("the system clock gave %s", got)
+	}
+}
@@ -0,0 +1,270 @@
+/*
+	Package format prints a program back as source in a canonical layout: one statement per line ending in
+	a semicolon, blocks indented one level, a single space around infix operators and after commas, and only
+	the parentheses needed to keep the meaning. Blank lines between statements are kept, runs of blank lines
+	are collapsed to one.
+*/
+package format
+
+import (
+	"errors"
+	"strings"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/parser"
+)
+
+// Source parses src and returns it formatted using indent for each level of nesting.
+func Source(src string, indent string) (string, error) {
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+	if diags := p.Diagnostics(); len(diags) != 0 {
+		return "", errors.New(diags[0].String())
+	}
+	return Program(program, src, indent), nil
+}
+
+/*
+	Program formats a parsed program. src is the text it was parsed from and is only used to find the blank
+	lines to keep, it can be empty.
+*/
+func Program(program *ast.Program, src string, indent string) string {
+	pr := &printer{lines: strings.Split(src, "\n"), indent: indent}
+	pr.statements(program.Statements)
+	if pr.out.Len() > 0 {
+		pr.out.WriteString("\n")
+	}
+	return pr.out.String()
+}
+
+// Expression formats a single expression, blocks in it are indented with two spaces.
+func Expression(e ast.Expression) string {
+	pr := &printer{indent: "  "}
+	pr.expr(e)
+	return pr.out.String()
+}
+
+type printer struct {
+	out    strings.Builder
+	lines  []string
+	indent string
+	depth  int
+}
+
+func (p *printer) write(s string) {
+	p.out.WriteString(s)
+}
+
+func (p *printer) newline() {
+	p.write("\n" + strings.Repeat(p.indent, p.depth))
+}
+
+func (p *printer) statements(stmts []ast.Statement) {
+	for i, s := range stmts {
+		if i > 0 {
+			if p.blankBefore(s) {
+				p.write("\n")
+			}
+			p.newline()
+		}
+		p.statement(s)
+	}
+}
+
+// blankBefore reports whether the source line before the statement is blank.
+func (p *printer) blankBefore(s ast.Statement) bool {
+	line := ast.Pos(s).Line
+	return line >= 2 && line-2 < len(p.lines) && strings.TrimSpace(p.lines[line-2]) == ""
+}
+
+func (p *printer) statement(s ast.Statement) {
+	switch s := s.(type) {
+	case *ast.LetStatement:
+		p.write("let " + s.Name.Value)
+		if s.Type != nil {
+			p.write(": " + s.Type.String())
+		}
+		p.write(" = ")
+		p.expr(s.Value)
+		p.write(";")
+	case *ast.FunctionStatement:
+		// a declaration reads like an if and is not followed by a semicolon either.
+		p.write("fn " + s.Name.Value)
+		p.function(s.Function)
+	case *ast.ReturnStatement:
+		p.write("return")
+		if s.ReturnValue != nil {
+			p.write(" ")
+			p.expr(s.ReturnValue)
+		}
+		p.write(";")
+	case *ast.ExpressionStatement:
+		p.expr(s.Expression)
+		// an if reads like a statement so it is not followed by a semicolon.
+		if _, ok := s.Expression.(*ast.IfExpression); !ok {
+			p.write(";")
+		}
+	case *ast.BlockStatement:
+		p.block(s)
+	}
+}
+
+func (p *printer) block(b *ast.BlockStatement) {
+	if len(b.Statements) == 0 {
+		p.write("{}")
+		return
+	}
+	p.write("{")
+	p.depth++
+	p.newline()
+	p.statements(b.Statements)
+	p.depth--
+	p.newline()
+	p.write("}")
+}
+
+var operators = map[string]int{
+	"==": parser.EQUALS,
+	"!=": parser.EQUALS,
+	"<":  parser.LESSGREATER,
+	">":  parser.LESSGREATER,
+	"+":  parser.SUM,
+	"-":  parser.SUM,
+	"*":  parser.PRODUCT,
+	"/":  parser.PRODUCT,
+	"%":  parser.PRODUCT,
+}
+
+// precedence is how tightly an expression binds, anything that is not an operator cannot be split.
+func precedence(e ast.Expression) int {
+	switch e := e.(type) {
+	case *ast.InfixExpression:
+		if prec, ok := operators[e.Operator]; ok {
+			return prec
+		}
+		return parser.LOWEST
+	case *ast.PrefixExpression:
+		return parser.PREFIX
+	case *ast.CallExpression, *ast.IndexExpression, *ast.MemberExpression:
+		return parser.CALL
+	}
+	return parser.INDEX + 1
+}
+
+// operand prints e wrapped in parentheses if it binds less tightly than min.
+func (p *printer) operand(e ast.Expression, min int) {
+	if precedence(e) < min {
+		p.write("(")
+		p.expr(e)
+		p.write(")")
+		return
+	}
+	p.expr(e)
+}
+
+func (p *printer) expr(e ast.Expression) {
+	switch e := e.(type) {
+	case *ast.Identifier:
+		p.write(e.Value)
+	case *ast.IntegerLiteral, *ast.BigIntLiteral, *ast.DecimalLiteral:
+		p.write(e.TokenLiteral())
+	case *ast.StringLiteral:
+		p.write(`"` + e.Value + `"`)
+	case *ast.Boolean:
+		p.write(e.Token.Literal)
+	case *ast.PrefixExpression:
+		p.write(e.Operator)
+		p.operand(e.Right, parser.PREFIX)
+	case *ast.InfixExpression:
+		// operators are left associative so the right operand needs parentheses at the same precedence.
+		prec := precedence(e)
+		p.operand(e.Left, prec)
+		p.write(" " + e.Operator + " ")
+		p.operand(e.Right, prec+1)
+	case *ast.IfExpression:
+		p.write("if (")
+		p.expr(e.Condition)
+		p.write(") ")
+		p.block(e.Consequence)
+		if e.Alternative != nil {
+			p.write(" else ")
+			p.block(e.Alternative)
+		}
+	case *ast.FunctionLiteral:
+		p.write("fn")
+		p.function(e)
+	case *ast.SpreadExpression:
+		p.write("...")
+		p.expr(e.Value)
+	case *ast.CallExpression:
+		p.operand(e.Function, parser.CALL)
+		p.write("(")
+		p.list(e.Arguments)
+		p.write(")")
+	case *ast.ArrayLiteral:
+		p.write("[")
+		p.list(e.Elements)
+		p.write("]")
+	case *ast.MemberExpression:
+		p.operand(e.Object, parser.CALL)
+		p.write("." + e.Property.Value)
+	case *ast.IndexExpression:
+		p.operand(e.Left, parser.CALL)
+		p.write("[")
+		p.expr(e.Index)
+		p.write("]")
+	case *ast.HashLiteral:
+		p.hash(e)
+	}
+}
+
+// function prints the parameters, result type and body of a function.
+func (p *printer) function(e *ast.FunctionLiteral) {
+	p.write("(")
+	for i, param := range e.Parameters {
+		if i > 0 {
+			p.write(", ")
+		}
+		if e.Rest && i == len(e.Parameters)-1 {
+			p.write("...")
+		}
+		p.write(param.Value)
+		if i < len(e.ParameterTypes) && e.ParameterTypes[i] != nil {
+			p.write(": " + e.ParameterTypes[i].String())
+		}
+		if i < len(e.Defaults) && e.Defaults[i] != nil {
+			p.write(" = ")
+			p.expr(e.Defaults[i])
+		}
+	}
+	p.write(")")
+	if e.ResultType != nil {
+		p.write(": " + e.ResultType.String())
+	}
+	p.write(" ")
+	p.block(e.Body)
+}
+
+func (p *printer) list(exprs []ast.Expression) {
+	for i, e := range exprs {
+		if i > 0 {
+			p.write(", ")
+		}
+		p.expr(e)
+	}
+}
+
+// hash prints the pairs of a hash literal in the order they were written.
+func (p *printer) hash(h *ast.HashLiteral) {
+	p.write("{")
+	for i, k := range h.Keys() {
+		if i > 0 {
+			p.write(", ")
+		}
+		p.expr(k)
+		p.write(": ")
+		p.expr(h.Pairs[k])
+	}
+	p.write("}")
+}
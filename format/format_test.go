@@ -0,0 +1,60 @@
+package format
+
+import "testing"
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let   x=5", "let x = 5;\n"},
+		{"1+2*3", "1 + 2 * 3;\n"},
+		{"(1+2)*3", "(1 + 2) * 3;\n"},
+		{"1-(2-3)", "1 - (2 - 3);\n"},
+		{"(1-2)-3", "1 - 2 - 3;\n"},
+		{"-(a+b)", "-(a + b);\n"},
+		{"!-a", "!-a;\n"},
+		{"add(1,2*3)[0]", "add(1, 2 * 3)[0];\n"},
+		{"(a+b)[0]", "(a + b)[0];\n"},
+		{`[1,"two",true]`, "[1, \"two\", true];\n"},
+		{`{"b":2,"a":1}`, "{\"b\": 2, \"a\": 1};\n"},
+		{"{}", "{};\n"},
+		{"return x;", "return x;\n"},
+		{"let f = fn(a: int,b): int { a + b }", "let f = fn(a: int, b): int {\n    a + b;\n};\n"},
+		{"let f = fn() {}", "let f = fn() {};\n"},
+		{"if (x>1) { y } else { if (z) { 1 } }", "if (x > 1) {\n    y;\n} else {\n    if (z) {\n        1;\n    }\n}\n"},
+		{"let a = 1;\n\n\n\nlet b = 2;\nlet c = 3;", "let a = 1;\n\nlet b = 2;\nlet c = 3;\n"},
+		{"fn(x) { x }(5)", "fn(x) {\n    x;\n}(5);\n"},
+		{"let total=19.99*3+10n", "let total = 19.99 * 3 + 10n;\n"},
+		{`strings.upper( h.name )[0]+(-a).b`, "strings.upper(h.name)[0] + (-a).b;\n"},
+		{"fn add(a,b) { a+b }\nfn(x) { x }", "fn add(a, b) {\n    a + b;\n}\nfn(x) {\n    x;\n};\n"},
+		{"fn(a,b=1+2,...rest) { a }(...xs)", "fn(a, b = 1 + 2, ...rest) {\n    a;\n}(...xs);\n"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		got, err := Source(tt.input, "    ")
+		if err != nil {
+			t.Errorf("Source(%q) returned error %v", tt.input, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("Source(%q) wrong.\nexpected=%q\ngot=%q", tt.input, tt.expected, got)
+			continue
+		}
+		again, err := Source(got, "    ")
+		if err != nil || again != got {
+			t.Errorf("formatting %q is not stable, got %q (%v)", got, again, err)
+		}
+	}
+}
+
+func TestSourceError(t *testing.T) {
+	_, err := Source("let = 5;", "\t")
+	if err == nil {
+		t.Fatalf("expected an error for a program that does not parse")
+	}
+	if err.Error() != "1:5: error: expected next token to be IDENT got: = (syntax)" {
+		t.Errorf("wrong error %q", err.Error())
+	}
+}
@@ -7,7 +7,32 @@ import (
 	"go-interpreter-lexer/repl"
 )
 
+/*
+	Sub commands of the monkey binary, running it without a sub command starts the REPL.
+	Each command takes the arguments that follow its name and returns the exit code of the process.
+*/
+var commands = map[string]func(args []string) int{
+	"ast": astCommand,
+	"run": runCommand,
+	"lint": lintCommand,
+	"check": checkCommand,
+	"lsp": lspCommand,
+	"debug": debugCommand,
+	"dap": dapCommand,
+	"cover": coverCommand,
+	"test": testCommand,
+}
+
 func main(){
+	if len(os.Args) > 1 {
+		cmd, ok := commands[os.Args[1]]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "monkey: unknown command %q\n", os.Args[1])
+			os.Exit(2)
+		}
+		os.Exit(cmd(os.Args[2:]))
+	}
+
 	user, err := user.Current()
 	if err != nil{
 		panic (err)
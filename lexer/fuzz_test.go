@@ -0,0 +1,39 @@
+package lexer
+
+import (
+	"testing"
+
+	"go-interpreter-lexer/token"
+)
+
+var fuzzSeeds = []string{
+	"",
+	`let add = fn(a, b) { a + b; }; add(1, 2 * 3);`,
+	`if (x != 10) { return "ten"; } else { [1, 2][0] }`,
+	`{"a": 1, true: [x]}["a"] == !y`,
+	`"unterminated`,
+	"let x: [int] = 5;\n\t@#$ 123abc",
+}
+
+// FuzzNextToken checks the lexer always reaches EOF, with positions that move forward, whatever the input.
+func FuzzNextToken(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		l := New(input)
+		var last token.Position
+		// every token consumes at least one byte so there can be no more tokens than bytes.
+		for i := 0; i <= len(input)+1; i++ {
+			tok := l.NextToken()
+			if tok.Pos.Line < last.Line || tok.Pos.Line == last.Line && tok.Pos.Column < last.Column {
+				t.Fatalf("token %q at %s is before the previous one at %s", tok.Literal, tok.Pos, last)
+			}
+			last = tok.Pos
+			if tok.Type == token.EOF {
+				return
+			}
+		}
+		t.Fatalf("no EOF after %d tokens", len(input)+2)
+	})
+}
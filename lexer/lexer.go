@@ -9,10 +9,13 @@ type Lexer struct{
 	position int
 	readPosition int
 	ch byte
+	// line and column of ch
+	line int
+	column int
 }
 
 func New(input string) *Lexer{
-	l := &Lexer{ input: input }
+	l := &Lexer{ input: input, line: 1 }
 	l.readChar()
 	return l
 }
@@ -20,6 +23,7 @@ func New(input string) *Lexer{
 func (l *Lexer) NextToken() token.Token{
 	var t  token.Token
 	l.skipWhitespace()
+	pos := token.Position{Line: l.line, Column: l.column}
 
 	switch l.ch {
 			case '=' :
@@ -27,7 +31,7 @@ func (l *Lexer) NextToken() token.Token{
 					ch := l.ch
 					l.readChar()
 					literal := string(ch) + string(l.ch)
-					t = token.Token{token.EQ, literal}
+					t = token.Token{Type: token.EQ, Literal: literal}
 				}else{
 					t = newToken(token.ASSIGN, l.ch)
 				}
@@ -50,7 +54,7 @@ func (l *Lexer) NextToken() token.Token{
 					ch := l.ch
 					l.readChar()
 					literal := string(ch) + string(l.ch)
-					t = token.Token{token.NOT_EQ, literal}
+					t = token.Token{Type: token.NOT_EQ, Literal: literal}
 				}else {
 					t = newToken(token.BANG, l.ch)
 				}
@@ -58,6 +62,8 @@ func (l *Lexer) NextToken() token.Token{
 				t = newToken(token.SLASH, l.ch)
 			case '*' :
 				t = newToken(token.ASTERISK, l.ch)
+			case '%' :
+				t = newToken(token.PERCENT, l.ch)
 			case '>' :
 				t = newToken(token.GT, l.ch)
 			case '<' :
@@ -65,7 +71,7 @@ func (l *Lexer) NextToken() token.Token{
 			case '-' :
 				t = newToken(token.MINUS, l.ch)
 			case 0 :
-				t = token.Token{token.EOF, ""}
+				t = token.Token{Type: token.EOF, Literal: ""}
 			case '[':
 				t = newToken(token.LBRACKET, l.ch)
 			case ']':
@@ -75,14 +81,23 @@ func (l *Lexer) NextToken() token.Token{
 				t.Literal = l.readString()
 			case ':':
 				t = newToken(token.COLON, l.ch)
+			case '.':
+				if l.peekChar() == '.' && l.readPosition+1 < len(l.input) && l.input[l.readPosition+1] == '.' {
+					l.readChar()
+					l.readChar()
+					t = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+				}else{
+					t = newToken(token.DOT, l.ch)
+				}
 			default:
 				if isLetter(l.ch){
 					t.Literal = l.readIdentifier()
 					t.Type = token.LookupIdent(t.Literal)
+					t.Pos = pos
 					return t
 				}else if isDigit(l.ch) {
-					t.Literal = l.readNumber()
-					t.Type = token.INT
+					t.Type, t.Literal = l.readNumber()
+					t.Pos = pos
 					return t
 				} else{
 					t = newToken(token.ILLEGAL, l.ch)
@@ -90,6 +105,7 @@ func (l *Lexer) NextToken() token.Token{
 				}
 
 		l.readChar()
+	t.Pos = pos
 	return t
 }
 
@@ -117,12 +133,27 @@ func isLetter(ch byte) bool{
 }
 
 
-func (l *Lexer) readNumber() string{
+/*
+	readNumber reads an integer, a big integer when it ends in n or a decimal when a point and digits follow.
+	5.a stays the integer 5 followed by a member access.
+*/
+func (l *Lexer) readNumber() (token.TokenType, string){
 	position := l.position
 	for isDigit(l.ch){
 		l.readChar()
 	}
-	return l.input[position:l.position]
+	if l.ch == 'n' {
+		l.readChar()
+		return token.BIGINT, l.input[position:l.position]
+	}
+	if l.ch == '.' && isDigit(l.peekChar()) {
+		l.readChar()
+		for isDigit(l.ch){
+			l.readChar()
+		}
+		return token.DECIMAL, l.input[position:l.position]
+	}
+	return token.INT, l.input[position:l.position]
 }
 
 /*
@@ -148,12 +179,19 @@ func newToken(tokenType token.TokenType, ch byte) token.Token{
 }
 
 func (l *Lexer) readChar(){
+	if l.ch == '\n'{
+		l.line += 1
+		l.column = 0
+	}
 	if l.readPosition >= len(l.input){
 		l.ch = 0
 	}else{
 		l.ch = l.input[l.readPosition]
 	}
 
+	if l.readPosition <= len(l.input){
+		l.column +=1
+	}
 	l.position = l.readPosition
 	l.readPosition +=1
 }
@@ -145,6 +145,30 @@ if (5 < 10) {
 			{token.EOF, ""},
 		},
 	},
+	{
+		`x % m.n`,
+		[]expectedTokens{
+			{token.IDENT, "x"},
+			{token.PERCENT, "%"},
+			{token.IDENT, "m"},
+			{token.DOT, "."},
+			{token.IDENT, "n"},
+			{token.EOF, ""},
+		},
+	},
+	{
+		`12n 12.50 5.a 7.`,
+		[]expectedTokens{
+			{token.BIGINT, "12n"},
+			{token.DECIMAL, "12.50"},
+			{token.INT, "5"},
+			{token.DOT, "."},
+			{token.IDENT, "a"},
+			{token.INT, "7"},
+			{token.DOT, "."},
+			{token.EOF, ""},
+		},
+	},
 }
 
 func TestNextToken(t *testing.T){
@@ -164,4 +188,27 @@ func TestNextToken(t *testing.T){
 		}
 	}
 
-}
\ No newline at end of file
+}
+func TestTokenPositions(t *testing.T){
+	input := "let x = 5;\n  x == \"a b\";\n"
+	expected := []token.Position{
+		{Line: 1, Column: 1},
+		{Line: 1, Column: 5},
+		{Line: 1, Column: 7},
+		{Line: 1, Column: 9},
+		{Line: 1, Column: 10},
+		{Line: 2, Column: 3},
+		{Line: 2, Column: 5},
+		{Line: 2, Column: 8},
+		{Line: 2, Column: 13},
+		{Line: 3, Column: 1},
+	}
+
+	l := New(input)
+	for i, pos := range expected{
+		tok := l.NextToken()
+		if tok.Pos != pos {
+			t.Errorf("Test [%d] - position of %q wrong. expected = %s, got = %s", i, tok.Literal, pos, tok.Pos)
+		}
+	}
+}
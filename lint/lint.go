@@ -0,0 +1,108 @@
+/*
+	Package lint checks a parsed program for code that is legal but almost certainly a mistake. Each check is
+	a Rule that can be enabled or disabled by name, problems are reported as warnings in the same
+	diagnostic format the parser and resolver use with the rule name as the code.
+*/
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/diagnostic"
+	"go-interpreter-lexer/resolver"
+	"go-interpreter-lexer/token"
+)
+
+type Rule struct {
+	Name string
+	Doc  string
+	Run  func(p *Pass)
+}
+
+/*
+	Pass is handed to a rule when it runs, it holds the program being checked and the resolver that has
+	already been run over it.
+*/
+type Pass struct {
+	Program  *ast.Program
+	Resolver *resolver.Resolver
+
+	rule        *Rule
+	diagnostics []diagnostic.Diagnostic
+}
+
+// Report adds a warning for the rule that is running.
+func (p *Pass) Report(pos token.Position, format string, a ...interface{}) {
+	p.diagnostics = append(p.diagnostics, diagnostic.Diagnostic{
+		Pos:      pos,
+		Severity: diagnostic.Warning,
+		Code:     p.rule.Name,
+		Message:  fmt.Sprintf(format, a...),
+	})
+}
+
+// Rules returns every rule the linter knows about sorted by name.
+func Rules() []*Rule {
+	out := append([]*Rule{}, rules...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func lookup(name string) *Rule {
+	for _, r := range rules {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+type Linter struct {
+	builtins []string
+	enabled  map[string]bool
+}
+
+// New returns a linter with every rule enabled that knows about the given builtin functions.
+func New(builtins []string) *Linter {
+	l := &Linter{builtins: builtins, enabled: make(map[string]bool)}
+	for _, r := range rules {
+		l.enabled[r.Name] = true
+	}
+	return l
+}
+
+func (l *Linter) Enable(name string) error {
+	return l.set(name, true)
+}
+
+func (l *Linter) Disable(name string) error {
+	return l.set(name, false)
+}
+
+func (l *Linter) set(name string, enabled bool) error {
+	if lookup(name) == nil {
+		return fmt.Errorf("unknown lint rule %q", name)
+	}
+	l.enabled[name] = enabled
+	return nil
+}
+
+// Lint runs the enabled rules over program and returns what they found ordered by position.
+func (l *Linter) Lint(program *ast.Program) []diagnostic.Diagnostic {
+	r := resolver.New(l.builtins)
+	r.Resolve(program)
+
+	p := &Pass{Program: program, Resolver: r}
+	for _, rule := range rules {
+		if !l.enabled[rule.Name] {
+			continue
+		}
+		p.rule = rule
+		rule.Run(p)
+	}
+
+	diagnostic.Sort(p.diagnostics)
+	return p.diagnostics
+}
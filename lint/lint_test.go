@@ -0,0 +1,62 @@
+package lint
+
+import (
+	"testing"
+
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/parser"
+)
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"let a = 1; puts(a);", nil},
+		{"let a = 1;", []string{"1:5: warning: let a is declared but never used (unused-let)"}},
+		{"let _a = 1;", nil},
+		{"let f = fn(x, y) { x }; f(1, 2);", []string{"1:15: warning: parameter y is declared but never used (unused-param)"}},
+		{"let f = fn() { return 1; 2; }; f();", []string{"1:26: warning: unreachable code after return (unreachable)"}},
+		{"let a = 1; a == a;", []string{"1:14: warning: comparison of a with itself is always true (self-compare)"}},
+		{"let a = [1]; a[0] < a[0];", []string{"1:19: warning: comparison of (a[0]) with itself is always false (self-compare)"}},
+		{"let f = fn() { 1 }; f() == f();", nil},
+		{"if (true) { 1 }", []string{"1:1: warning: if condition true is always true (constant-condition)"}},
+		{"if (!1) { 1 }", []string{"1:1: warning: if condition (!1) is always false (constant-condition)"}},
+		{"len([1], [2]);", []string{"1:4: warning: len expects 1 argument(s) but is called with 2 (builtin-arity)"}},
+		{"let f = fn(first) { first([1]) }; f(1);", []string{"1:12: warning: first shadows the builtin function first (shadow-builtin)"}},
+		{"x;", []string{"1:1: error: identifier not found: x (undefined)"}},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%q: parser errors %v", tt.input, p.Errors())
+		}
+		diags := New([]string{"len", "first", "last", "puts"}).Lint(program)
+		if len(diags) != len(tt.expected) {
+			t.Errorf("%q: expected %d diagnostics got %v", tt.input, len(tt.expected), diags)
+			continue
+		}
+		for i, d := range diags {
+			if d.String() != tt.expected[i] {
+				t.Errorf("%q: wrong diagnostic. expected=%q, got=%q", tt.input, tt.expected[i], d.String())
+			}
+		}
+	}
+}
+
+func TestDisable(t *testing.T) {
+	program := parser.New(lexer.New("let a = fn(x) { 1 };")).ParseProgram()
+	l := New(nil)
+	if err := l.Disable("unused-let"); err != nil {
+		t.Fatalf("Disable returned %v", err)
+	}
+	diags := l.Lint(program)
+	if len(diags) != 1 || diags[0].Code != "unused-param" {
+		t.Errorf("expected only unused-param got %v", diags)
+	}
+	if err := l.Disable("no-such-rule"); err == nil {
+		t.Errorf("expected an error disabling an unknown rule")
+	}
+}
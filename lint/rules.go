@@ -0,0 +1,216 @@
+package lint
+
+import (
+	"strings"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/resolver"
+	"go-interpreter-lexer/token"
+)
+
+var rules = []*Rule{
+	{Name: "undefined", Doc: "identifiers that are not declared anywhere", Run: resolverCode("undefined")},
+	{Name: "shadowed", Doc: "declarations that shadow a binding of an enclosing scope", Run: resolverCode("shadowed")},
+	{Name: "unused-let", Doc: "let bindings that are never used", Run: unusedBindings(resolver.Let)},
+	{Name: "unused-param", Doc: "function parameters that are never used", Run: unusedBindings(resolver.Param)},
+	{Name: "unreachable", Doc: "statements after a return in the same block", Run: unreachable},
+	{Name: "self-compare", Doc: "comparison of a value with itself", Run: selfCompare},
+	{Name: "constant-condition", Doc: "if expressions whose condition is a literal", Run: constantCondition},
+	{Name: "builtin-arity", Doc: "builtin functions called with the wrong number of arguments", Run: builtinArity},
+	{Name: "shadow-builtin", Doc: "let bindings, functions and parameters named after a builtin function", Run: shadowBuiltin},
+}
+
+// builtinArities is the number of arguments the builtins with a fixed arity take.
+var builtinArities = map[string]int{
+	"len":         1,
+	"first":       1,
+	"last":        1,
+	"assertEq":    2,
+	"assertError": 1,
+	"rest":        1,
+	"map":         2,
+	"filter":      2,
+	"find":        2,
+	"any":         2,
+	"all":         2,
+	"reverse":     1,
+	"flatten":     1,
+	"uniq":        1,
+	"keys":        1,
+	"values":      1,
+	"entries":     1,
+	"has":         2,
+	"args":        0,
+	"env":         1,
+}
+
+// resolverCode passes on the diagnostics of the resolver with the given code.
+func resolverCode(code string) func(p *Pass) {
+	return func(p *Pass) {
+		for _, d := range p.Resolver.Diagnostics() {
+			if d.Code == code {
+				p.diagnostics = append(p.diagnostics, d)
+			}
+		}
+	}
+}
+
+// unusedBindings reports bindings of kind that are never referred to, names starting with _ are ignored.
+func unusedBindings(kind resolver.Kind) func(p *Pass) {
+	return func(p *Pass) {
+		var visit func(s *resolver.Scope)
+		visit = func(s *resolver.Scope) {
+			for _, b := range s.Bindings {
+				if b.Kind != kind || len(b.Uses) != 0 || strings.HasPrefix(b.Name, "_") || b.Decl() == nil {
+					continue
+				}
+				p.Report(b.Decl().Token.Pos, "%s %s is declared but never used", kind, b.Name)
+			}
+			for _, c := range s.Children {
+				visit(c)
+			}
+		}
+		visit(p.Resolver.Global())
+	}
+}
+
+func unreachable(p *Pass) {
+	check := func(stmts []ast.Statement) {
+		for i, s := range stmts {
+			if _, ok := s.(*ast.ReturnStatement); ok && i+1 < len(stmts) {
+				p.Report(tokenOf(stmts[i+1]).Pos, "unreachable code after return")
+				return
+			}
+		}
+	}
+	ast.Inspect(p.Program, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Program:
+			check(n.Statements)
+		case *ast.BlockStatement:
+			check(n.Statements)
+		}
+		return true
+	})
+}
+
+var comparisons = map[string]bool{"==": true, "!=": true, "<": true, ">": true}
+
+func selfCompare(p *Pass) {
+	ast.Inspect(p.Program, func(n ast.Node) bool {
+		ie, ok := n.(*ast.InfixExpression)
+		if !ok || !comparisons[ie.Operator] || ie.Left == nil || ie.Right == nil {
+			return true
+		}
+		if !pure(ie.Left) || ie.Left.String() != ie.Right.String() {
+			return true
+		}
+		always := ie.Operator == "=="
+		p.Report(ie.Token.Pos, "comparison of %s with itself is always %t", ie.Left.String(), always)
+		return true
+	})
+}
+
+// pure reports whether evaluating node twice gives the same value, i.e. it does not call any function.
+func pure(node ast.Node) bool {
+	result := true
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.CallExpression, *ast.FunctionLiteral:
+			result = false
+		}
+		return result
+	})
+	return result
+}
+
+func constantCondition(p *Pass) {
+	ast.Inspect(p.Program, func(n ast.Node) bool {
+		ie, ok := n.(*ast.IfExpression)
+		if !ok {
+			return true
+		}
+		if truthy, ok := literalTruth(ie.Condition); ok {
+			p.Report(ie.Token.Pos, "if condition %s is always %t", ie.Condition.String(), truthy)
+		}
+		return true
+	})
+}
+
+// literalTruth returns how the evaluator would treat a literal condition, ok is false for anything else.
+func literalTruth(exp ast.Expression) (truthy bool, ok bool) {
+	switch e := exp.(type) {
+	case *ast.Boolean:
+		return e.Value, true
+	case *ast.IntegerLiteral, *ast.BigIntLiteral, *ast.DecimalLiteral, *ast.StringLiteral, *ast.ArrayLiteral, *ast.HashLiteral, *ast.FunctionLiteral:
+		return true, true
+	case *ast.PrefixExpression:
+		if e.Operator == "!" {
+			truthy, ok := literalTruth(e.Right)
+			return !truthy, ok
+		}
+	}
+	return false, false
+}
+
+func builtinArity(p *Pass) {
+	ast.Inspect(p.Program, func(n ast.Node) bool {
+		ce, ok := n.(*ast.CallExpression)
+		if !ok {
+			return true
+		}
+		ident, ok := ce.Function.(*ast.Identifier)
+		if !ok {
+			return true
+		}
+		b := p.Resolver.Binding(ident)
+		want, known := builtinArities[ident.Value]
+		if b == nil || b.Kind != resolver.Builtin || !known || len(ce.Arguments) == want {
+			return true
+		}
+		for _, a := range ce.Arguments {
+			if _, ok := a.(*ast.SpreadExpression); ok {
+				return true
+			}
+		}
+		p.Report(ce.Token.Pos, "%s expects %d argument(s) but is called with %d", ident.Value, want, len(ce.Arguments))
+		return true
+	})
+}
+
+func shadowBuiltin(p *Pass) {
+	ast.Inspect(p.Program, func(n ast.Node) bool {
+		var decls []*ast.Identifier
+		switch n := n.(type) {
+		case *ast.LetStatement:
+			decls = append(decls, n.Name)
+		case *ast.FunctionStatement:
+			decls = append(decls, n.Name)
+		case *ast.FunctionLiteral:
+			decls = append(decls, n.Parameters...)
+		}
+		for _, d := range decls {
+			if d != nil && p.Resolver.Builtin(d.Value) != nil {
+				p.Report(d.Token.Pos, "%s shadows the builtin function %s", d.Value, d.Value)
+			}
+		}
+		return true
+	})
+}
+
+// tokenOf returns the token a statement starts with.
+func tokenOf(s ast.Statement) token.Token {
+	switch s := s.(type) {
+	case *ast.LetStatement:
+		return s.Token
+	case *ast.FunctionStatement:
+		return s.Token
+	case *ast.ReturnStatement:
+		return s.Token
+	case *ast.ExpressionStatement:
+		return s.Token
+	case *ast.BlockStatement:
+		return s.Token
+	}
+	return token.Token{}
+}
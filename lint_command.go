@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"go-interpreter-lexer/diagnostic"
+	"go-interpreter-lexer/evaluator"
+	"go-interpreter-lexer/lint"
+)
+
+/*
+	lintCommand checks source files with the lint rules,
+	monkey lint [--enable=rule,...] [--disable=rule,...] [--rules] file.mk...
+	The exit code is 1 if any file has an error and 3 if there were only warnings.
+*/
+func lintCommand(args []string) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	enable := fs.String("enable", "", "comma separated rules to enable, all rules are enabled by default")
+	disable := fs.String("disable", "", "comma separated rules to disable")
+	list := fs.Bool("rules", false, "list the available rules and exit")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *list {
+		for _, r := range lint.Rules() {
+			fmt.Printf("%-20s %s\n", r.Name, r.Doc)
+		}
+		return 0
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: monkey lint [--enable=rule,...] [--disable=rule,...] file.mk...")
+		return 2
+	}
+
+	l := lint.New(evaluator.BuiltinNames())
+	for _, name := range splitList(*disable) {
+		if err := l.Disable(name); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+	for _, name := range splitList(*enable) {
+		if err := l.Enable(name); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+
+	code := 0
+	for _, path := range fs.Args() {
+		program, diags, err := parseFile(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = 1
+			continue
+		}
+		if len(diags) == 0 {
+			diags = l.Lint(program)
+		}
+		printDiagnostics(os.Stdout, path, diags)
+		if diagnostic.HasErrors(diags) {
+			code = 1
+		} else if len(diags) != 0 && code == 0 {
+			code = 3
+		}
+	}
+	return code
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
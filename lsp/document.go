@@ -0,0 +1,173 @@
+package lsp
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/diagnostic"
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/parser"
+	"go-interpreter-lexer/resolver"
+	"go-interpreter-lexer/testrunner"
+	"go-interpreter-lexer/token"
+	"go-interpreter-lexer/typecheck"
+)
+
+// analysis is what the server knows about one version of a document.
+type analysis struct {
+	program     *ast.Program
+	diagnostics []diagnostic.Diagnostic
+	// resolver and checker are nil when the document does not parse.
+	resolver *resolver.Resolver
+	checker  *typecheck.Checker
+}
+
+// analyze parses and checks the text of the document at uri, a test file has the assertions as builtins.
+func analyze(uri, text string) *analysis {
+	p := parser.New(lexer.New(text))
+	a := &analysis{program: p.ParseProgram(), diagnostics: p.Diagnostics()}
+	if len(a.diagnostics) != 0 {
+		return a
+	}
+	a.resolver = resolver.New(testrunner.BuiltinNames(uri))
+	a.resolver.Resolve(a.program)
+	a.checker = typecheck.New()
+	a.checker.Check(a.program)
+	a.diagnostics = append(a.resolver.Diagnostics(), a.checker.Diagnostics()...)
+	diagnostic.Sort(a.diagnostics)
+	return a
+}
+
+type document struct {
+	uri     string
+	version int
+	text    string
+	lines   []string
+	current *analysis
+	// resolved is the latest analysis of a version that parsed, used while the current text has syntax errors.
+	resolved *analysis
+}
+
+func (d *document) update(text string, version int) {
+	d.text = text
+	d.version = version
+	d.lines = strings.Split(text, "\n")
+	d.current = analyze(d.uri, text)
+	if d.current.resolver != nil {
+		d.resolved = d.current
+	}
+}
+
+// toPosition converts a position in the source to a protocol position.
+func (d *document) toPosition(pos token.Position) Position {
+	line := pos.Line - 1
+	if line < 0 {
+		return Position{}
+	}
+	if line >= len(d.lines) {
+		return Position{Line: line}
+	}
+	text := d.lines[line]
+	col := pos.Column - 1
+	if col > len(text) {
+		col = len(text)
+	}
+	return Position{Line: line, Character: utf16Len(text[:col])}
+}
+
+// fromPosition converts a protocol position to a position in the source.
+func (d *document) fromPosition(p Position) token.Position {
+	if p.Line < 0 || p.Line >= len(d.lines) {
+		return token.Position{Line: p.Line + 1, Column: 1}
+	}
+	text := d.lines[p.Line]
+	units, col := 0, 0
+	for col < len(text) && units < p.Character {
+		r, size := utf8.DecodeRuneInString(text[col:])
+		units += utf16Units(r)
+		col += size
+	}
+	return token.Position{Line: p.Line + 1, Column: col + 1}
+}
+
+// identRange is the range covered by an identifier.
+func (d *document) identRange(ident *ast.Identifier) Range {
+	start := ident.Token.Pos
+	end := token.Position{Line: start.Line, Column: start.Column + len(ident.Value)}
+	return Range{Start: d.toPosition(start), End: d.toPosition(end)}
+}
+
+// wordRange is the range of the word starting at pos, or of the single character there.
+func (d *document) wordRange(pos token.Position) Range {
+	end := pos
+	if pos.Line >= 1 && pos.Line <= len(d.lines) {
+		text := d.lines[pos.Line-1]
+		col := pos.Column - 1
+		for col < len(text) && isWordByte(text[col]) {
+			col++
+		}
+		if col == pos.Column-1 && col < len(text) {
+			_, size := utf8.DecodeRuneInString(text[col:])
+			col += size
+		}
+		end.Column = col + 1
+	}
+	return Range{Start: d.toPosition(pos), End: d.toPosition(end)}
+}
+
+// endPosition is the protocol position just past the last character of the text.
+func (d *document) endPosition() Position {
+	last := len(d.lines) - 1
+	return Position{Line: last, Character: utf16Len(d.lines[last])}
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16Units(r)
+	}
+	return n
+}
+
+func utf16Units(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
+
+// identAt returns the identifier under or just before pos.
+func identAt(program *ast.Program, pos token.Position) *ast.Identifier {
+	var found *ast.Identifier
+	ast.Inspect(program, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Identifier)
+		if !ok || found != nil {
+			return found == nil
+		}
+		start := ident.Token.Pos
+		if start.Line == pos.Line && start.Column <= pos.Column && pos.Column <= start.Column+len(ident.Value) {
+			found = ident
+		}
+		return true
+	})
+	return found
+}
+
+// scopeAt returns the innermost scope whose function literal contains pos.
+func scopeAt(s *resolver.Scope, pos token.Position) *resolver.Scope {
+	for _, c := range s.Children {
+		fl, ok := c.Node.(*ast.FunctionLiteral)
+		if !ok || fl.Body == nil {
+			continue
+		}
+		if fl.Token.Pos.Before(pos) && !fl.Body.End.Before(pos) {
+			return scopeAt(c, pos)
+		}
+	}
+	return s
+}
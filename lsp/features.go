@@ -0,0 +1,257 @@
+package lsp
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/evaluator"
+	"go-interpreter-lexer/format"
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/optimizer"
+	"go-interpreter-lexer/parser"
+	"go-interpreter-lexer/resolver"
+	"go-interpreter-lexer/token"
+	"go-interpreter-lexer/typecheck"
+)
+
+var keywords = []string{"else", "false", "fn", "if", "let", "return", "true"}
+
+// bindingAt finds the identifier at a request position and the binding it refers to.
+func (s *Server) bindingAt(p TextDocumentPositionParams) (*document, *analysis, *ast.Identifier, *resolver.Binding, error) {
+	d, err := s.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	a := d.current
+	if a.resolver == nil {
+		return d, a, nil, nil, nil
+	}
+	ident := identAt(a.program, d.fromPosition(p.Position))
+	if ident == nil {
+		return d, a, nil, nil, nil
+	}
+	return d, a, ident, a.resolver.Binding(ident), nil
+}
+
+func (s *Server) definition(params json.RawMessage) (interface{}, error) {
+	var p TextDocumentPositionParams
+	if err := unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	d, _, _, b, err := s.bindingAt(p)
+	if err != nil || b == nil || b.Decl() == nil {
+		return nil, err
+	}
+	return Location{URI: d.uri, Range: d.identRange(b.Decl())}, nil
+}
+
+func (s *Server) references(params json.RawMessage) (interface{}, error) {
+	var p ReferenceParams
+	if err := unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	d, _, _, b, err := s.bindingAt(p.TextDocumentPositionParams)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	idents := append([]*ast.Identifier{}, b.Uses...)
+	if p.Context.IncludeDeclaration {
+		idents = append(idents, b.Decls...)
+	}
+	sort.Slice(idents, func(i, j int) bool {
+		return idents[i].Token.Pos.Before(idents[j].Token.Pos)
+	})
+	locations := []Location{}
+	for _, ident := range idents {
+		locations = append(locations, Location{URI: d.uri, Range: d.identRange(ident)})
+	}
+	return locations, nil
+}
+
+func (s *Server) hover(params json.RawMessage) (interface{}, error) {
+	var p TextDocumentPositionParams
+	if err := unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	d, a, ident, b, err := s.bindingAt(p)
+	if err != nil || b == nil {
+		return nil, err
+	}
+
+	var text string
+	if b.Kind == resolver.Builtin {
+		text = "```monkey\nbuiltin " + b.Name + "\n```\n" + evaluator.BuiltinDoc(b.Name)
+	} else {
+		sig := b.Kind.String() + " " + b.Name
+		if t := typeOf(a, b); t != nil {
+			sig += ": " + t.String()
+		}
+		if v := constValue(d.text, b); v != "" {
+			sig += " = " + v
+		}
+		text = "```monkey\n" + sig + "\n```"
+	}
+	r := d.identRange(ident)
+	return Hover{Contents: MarkupContent{Kind: "markdown", Value: text}, Range: &r}, nil
+}
+
+// typeOf is the type the checker inferred for the declaration of a binding.
+func typeOf(a *analysis, b *resolver.Binding) typecheck.Type {
+	if b.Decl() == nil || a.checker == nil {
+		return nil
+	}
+	return a.checker.TypeOf(b.Decl())
+}
+
+/*
+	constValue returns the value of a let that the optimizer reduces to a literal, e.g. 86400 for
+	let day = 60 * 60 * 24, and an empty string for anything else.
+*/
+func constValue(text string, b *resolver.Binding) string {
+	if b.Kind != resolver.Let || len(b.Decls) != 1 {
+		return ""
+	}
+	pos := b.Decl().Token.Pos
+	program := parser.New(lexer.New(text)).ParseProgram()
+	optimizer.Optimize(program)
+
+	value := ""
+	ast.Inspect(program, func(n ast.Node) bool {
+		ls, ok := n.(*ast.LetStatement)
+		if !ok || ls.Name == nil || ls.Name.Token.Pos != pos {
+			return value == ""
+		}
+		switch v := ls.Value.(type) {
+		case *ast.IntegerLiteral, *ast.BigIntLiteral, *ast.DecimalLiteral, *ast.Boolean:
+			value = v.String()
+		case *ast.StringLiteral:
+			value = `"` + v.Value + `"`
+		}
+		return false
+	})
+	return value
+}
+
+func (s *Server) completion(params json.RawMessage) (interface{}, error) {
+	var p TextDocumentPositionParams
+	if err := unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	d, err := s.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []CompletionItem{}
+	seen := make(map[string]bool)
+	if a := d.resolved; a != nil {
+		pos := d.fromPosition(p.Position)
+		inner := scopeAt(a.resolver.Global(), pos)
+		for sc := inner; sc != nil; sc = sc.Parent {
+			for _, b := range sc.Bindings {
+				// lets of the scope the cursor is in can only be used after they are declared.
+				if seen[b.Name] || (sc == inner && b.Kind == resolver.Let && !b.Decl().Token.Pos.Before(pos)) {
+					continue
+				}
+				seen[b.Name] = true
+				item := CompletionItem{Label: b.Name, Kind: CompletionVariable}
+				if t := typeOf(a, b); t != nil {
+					item.Detail = t.String()
+					if _, ok := t.(*typecheck.Func); ok {
+						item.Kind = CompletionFunction
+					}
+				}
+				items = append(items, item)
+			}
+		}
+	}
+	for _, name := range evaluator.BuiltinNames() {
+		if !seen[name] {
+			items = append(items, CompletionItem{
+				Label:         name,
+				Kind:          CompletionFunction,
+				Detail:        "builtin",
+				Documentation: evaluator.BuiltinDoc(name),
+			})
+		}
+	}
+	for _, kw := range keywords {
+		items = append(items, CompletionItem{Label: kw, Kind: CompletionKeyword})
+	}
+	return items, nil
+}
+
+func (s *Server) documentSymbol(params json.RawMessage) (interface{}, error) {
+	var p DocumentSymbolParams
+	if err := unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	d, err := s.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := []DocumentSymbol{}
+	a := d.current
+	for _, stmt := range a.program.Statements {
+		var name *ast.Identifier
+		var value ast.Expression
+		switch stmt := stmt.(type) {
+		case *ast.LetStatement:
+			name, value = stmt.Name, stmt.Value
+		case *ast.FunctionStatement:
+			name, value = stmt.Name, stmt.Function
+		}
+		if name == nil {
+			continue
+		}
+		sym := DocumentSymbol{
+			Name:           name.Value,
+			Kind:           SymbolVariable,
+			SelectionRange: d.identRange(name),
+		}
+		start := ast.Pos(stmt)
+		end := token.Position{Line: start.Line, Column: len(d.lines[start.Line-1]) + 1}
+		if fl, ok := value.(*ast.FunctionLiteral); ok && fl != nil {
+			sym.Kind = SymbolFunction
+			if fl.Body != nil {
+				end = token.Position{Line: fl.Body.End.Line, Column: fl.Body.End.Column + 1}
+			}
+		}
+		sym.Range = Range{Start: d.toPosition(start), End: d.toPosition(end)}
+		if a.checker != nil {
+			if t := a.checker.TypeOf(name); t != nil {
+				sym.Detail = t.String()
+			}
+		}
+		symbols = append(symbols, sym)
+	}
+	return symbols, nil
+}
+
+func (s *Server) formatting(params json.RawMessage) (interface{}, error) {
+	var p DocumentFormattingParams
+	if err := unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	d, err := s.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	indent := "\t"
+	if p.Options.InsertSpaces && p.Options.TabSize > 0 {
+		indent = strings.Repeat(" ", p.Options.TabSize)
+	}
+	formatted, err := format.Source(d.text, indent)
+	if err != nil || formatted == d.text {
+		// a document that does not parse is left alone, its diagnostics already say why.
+		return []TextEdit{}, nil
+	}
+	return []TextEdit{{
+		Range:   Range{End: d.endPosition()},
+		NewText: formatted,
+	}}, nil
+}
@@ -0,0 +1,200 @@
+/*
+	Package lsp is a language server for monkey speaking the language server protocol over a pair of streams,
+	usually stdin and stdout of the monkey lsp command. Documents are analysed with the parser, the resolver
+	and the type checker every time they change and the results back diagnostics, go to definition, find
+	references, hover, completion, document symbols and formatting.
+*/
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"go-interpreter-lexer/diagnostic"
+)
+
+type Server struct {
+	in       *bufio.Reader
+	out      io.Writer
+	docs     map[string]*document
+	shutdown bool
+}
+
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		in:   bufio.NewReader(in),
+		out:  out,
+		docs: make(map[string]*document),
+	}
+}
+
+type handler func(s *Server, params json.RawMessage) (interface{}, error)
+
+var handlers = map[string]handler{
+	"initialize":                  (*Server).initialize,
+	"shutdown":                    (*Server).shutdownRequest,
+	"textDocument/definition":     (*Server).definition,
+	"textDocument/references":     (*Server).references,
+	"textDocument/hover":          (*Server).hover,
+	"textDocument/completion":     (*Server).completion,
+	"textDocument/documentSymbol": (*Server).documentSymbol,
+	"textDocument/formatting":     (*Server).formatting,
+}
+
+var notifications = map[string]func(s *Server, params json.RawMessage) error{
+	"textDocument/didOpen":   (*Server).didOpen,
+	"textDocument/didChange": (*Server).didChange,
+	"textDocument/didClose":  (*Server).didClose,
+}
+
+// ErrNoShutdown is returned by Run when the client sends exit without asking the server to shut down first.
+var ErrNoShutdown = errors.New("exit without shutdown")
+
+// Run serves requests until the client sends exit or the input ends.
+func (s *Server) Run() error {
+	for {
+		body, err := readMessage(s.in)
+		if err != nil {
+			if err == io.EOF && s.shutdown {
+				return nil
+			}
+			return err
+		}
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			if err := writeResponse(s.out, json.RawMessage("null"), nil, &responseError{Code: codeParseError, Message: err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return ErrNoShutdown
+			}
+			return nil
+		}
+		if len(msg.ID) == 0 {
+			// unknown notifications such as $/cancelRequest are ignored.
+			if n, ok := notifications[msg.Method]; ok {
+				if err := n(s, msg.Params); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		var result interface{}
+		h, ok := handlers[msg.Method]
+		if ok {
+			result, err = h(s, msg.Params)
+		} else {
+			err = &responseError{Code: codeMethodNotFound, Message: "method not supported: " + msg.Method}
+		}
+		if err := writeResponse(s.out, msg.ID, result, err); err != nil {
+			return err
+		}
+	}
+}
+
+func unmarshal(params json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(params, v); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func (s *Server) initialize(params json.RawMessage) (interface{}, error) {
+	return map[string]interface{}{
+		"capabilities": map[string]interface{}{
+			// full synchronization, every change sends the whole text.
+			"textDocumentSync":           1,
+			"definitionProvider":         true,
+			"referencesProvider":         true,
+			"hoverProvider":              true,
+			"completionProvider":         map[string]interface{}{},
+			"documentSymbolProvider":     true,
+			"documentFormattingProvider": true,
+		},
+		"serverInfo": map[string]string{"name": "monkey"},
+	}, nil
+}
+
+func (s *Server) shutdownRequest(params json.RawMessage) (interface{}, error) {
+	s.shutdown = true
+	return nil, nil
+}
+
+func (s *Server) didOpen(params json.RawMessage) error {
+	var p DidOpenTextDocumentParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil
+	}
+	d := &document{uri: p.TextDocument.URI}
+	d.update(p.TextDocument.Text, p.TextDocument.Version)
+	s.docs[d.uri] = d
+	return s.publishDiagnostics(d)
+}
+
+func (s *Server) didChange(params json.RawMessage) error {
+	var p DidChangeTextDocumentParams
+	if err := json.Unmarshal(params, &p); err != nil || len(p.ContentChanges) == 0 {
+		return nil
+	}
+	d, ok := s.docs[p.TextDocument.URI]
+	if !ok {
+		d = &document{uri: p.TextDocument.URI}
+		s.docs[d.uri] = d
+	}
+	d.update(p.ContentChanges[len(p.ContentChanges)-1].Text, p.TextDocument.Version)
+	return s.publishDiagnostics(d)
+}
+
+func (s *Server) didClose(params json.RawMessage) error {
+	var p DidCloseTextDocumentParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil
+	}
+	delete(s.docs, p.TextDocument.URI)
+	// clear the diagnostics of the closed document.
+	return writeNotification(s.out, "textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         p.TextDocument.URI,
+		Diagnostics: []Diagnostic{},
+	})
+}
+
+func (s *Server) publishDiagnostics(d *document) error {
+	diags := []Diagnostic{}
+	for _, diag := range d.current.diagnostics {
+		severity := SeverityError
+		switch diag.Severity {
+		case diagnostic.Warning:
+			severity = SeverityWarning
+		case diagnostic.Info:
+			severity = SeverityInformation
+		}
+		diags = append(diags, Diagnostic{
+			Range:    d.wordRange(diag.Pos),
+			Severity: severity,
+			Code:     diag.Code,
+			Source:   "monkey",
+			Message:  diag.Message,
+		})
+	}
+	return writeNotification(s.out, "textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         d.uri,
+		Version:     d.version,
+		Diagnostics: diags,
+	})
+}
+
+// document returns the open document a request refers to.
+func (s *Server) document(uri string) (*document, error) {
+	d, ok := s.docs[uri]
+	if !ok {
+		return nil, &responseError{Code: codeInvalidParams, Message: "document not open: " + uri}
+	}
+	return d, nil
+}
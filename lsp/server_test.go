@@ -0,0 +1,252 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go-interpreter-lexer/evaluator"
+)
+
+const uri = "file:///test.mk"
+
+// session runs the server over a list of messages and returns what it wrote, keyed by request id, and the
+// parameters of the diagnostics it published.
+func session(t *testing.T, text string, requests ...map[string]interface{}) (map[string]json.RawMessage, []PublishDiagnosticsParams) {
+	var in bytes.Buffer
+	msgs := []map[string]interface{}{
+		{"id": 0, "method": "initialize", "params": map[string]interface{}{}},
+		{"method": "initialized", "params": map[string]interface{}{}},
+		{"method": "textDocument/didOpen", "params": map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri, "languageId": "monkey", "version": 1, "text": text},
+		}},
+	}
+	msgs = append(msgs, requests...)
+	msgs = append(msgs, map[string]interface{}{"id": 99, "method": "shutdown"}, map[string]interface{}{"method": "exit"})
+	for _, m := range msgs {
+		m["jsonrpc"] = "2.0"
+		if err := writeMessage(&in, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := NewServer(&in, &out).Run(); err != nil {
+		t.Fatalf("Run returned %v", err)
+	}
+
+	results := make(map[string]json.RawMessage)
+	var published []PublishDiagnosticsParams
+	r := bufio.NewReader(&out)
+	for {
+		body, err := readMessage(r)
+		if err != nil {
+			break
+		}
+		var msg struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+			Result json.RawMessage `json:"result"`
+			Error  *responseError  `json:"error"`
+		}
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatal(err)
+		}
+		if msg.Error != nil {
+			t.Fatalf("request %s failed: %s", msg.ID, msg.Error.Message)
+		}
+		if msg.Method == "textDocument/publishDiagnostics" {
+			var p PublishDiagnosticsParams
+			json.Unmarshal(msg.Params, &p)
+			published = append(published, p)
+			continue
+		}
+		results[string(msg.ID)] = msg.Result
+	}
+	return results, published
+}
+
+func at(id int, method string, line, char int) map[string]interface{} {
+	return map[string]interface{}{"id": id, "method": method, "params": map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+		"position":     map[string]interface{}{"line": line, "character": char},
+		"context":      map[string]interface{}{"includeDeclaration": true},
+	}}
+}
+
+func TestInitialize(t *testing.T) {
+	results, _ := session(t, "")
+	var init struct {
+		Capabilities map[string]interface{} `json:"capabilities"`
+	}
+	json.Unmarshal(results["0"], &init)
+	for _, c := range []string{"definitionProvider", "referencesProvider", "hoverProvider", "completionProvider",
+		"documentSymbolProvider", "documentFormattingProvider"} {
+		if _, ok := init.Capabilities[c]; !ok {
+			t.Errorf("capability %s missing", c)
+		}
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	change := map[string]interface{}{"method": "textDocument/didChange", "params": map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": uri, "version": 2},
+		"contentChanges": []map[string]interface{}{{"text": "let x = 5;\nx + y"}},
+	}}
+	_, published := session(t, "let = 5;", change)
+	if len(published) != 2 {
+		t.Fatalf("expected diagnostics for open and change, got %d", len(published))
+	}
+
+	syntax := published[0].Diagnostics
+	if len(syntax) == 0 || syntax[0].Code != "syntax" || syntax[0].Range.Start != (Position{0, 4}) {
+		t.Errorf("wrong syntax diagnostics %+v", syntax)
+	}
+	undefined := published[1].Diagnostics
+	if published[1].Version != 2 || len(undefined) != 1 {
+		t.Fatalf("wrong diagnostics after change %+v", published[1])
+	}
+	want := Diagnostic{
+		Range:    Range{Start: Position{1, 4}, End: Position{1, 5}},
+		Severity: SeverityError,
+		Code:     "undefined",
+		Source:   "monkey",
+		Message:  "identifier not found: y",
+	}
+	if undefined[0] != want {
+		t.Errorf("wrong diagnostic.\nexpected=%+v\ngot=%+v", want, undefined[0])
+	}
+}
+
+const program = `let day = 60 * 60 * 24;
+let add = fn(a, b) { a + b };
+add(day, len("ab"));
+let f = fn(x) {
+  let y = 1;
+
+};`
+
+func TestDefinitionAndReferences(t *testing.T) {
+	results, _ := session(t, program,
+		at(1, "textDocument/definition", 2, 5),
+		at(2, "textDocument/definition", 1, 22),
+		at(3, "textDocument/references", 0, 6),
+		at(4, "textDocument/definition", 2, 10),
+	)
+
+	var loc Location
+	json.Unmarshal(results["1"], &loc)
+	if loc.URI != uri || loc.Range != (Range{Position{0, 4}, Position{0, 7}}) {
+		t.Errorf("wrong definition of day %+v", loc)
+	}
+	json.Unmarshal(results["2"], &loc)
+	if loc.Range != (Range{Position{1, 13}, Position{1, 14}}) {
+		t.Errorf("wrong definition of parameter a %+v", loc)
+	}
+
+	var refs []Location
+	json.Unmarshal(results["3"], &refs)
+	if len(refs) != 2 || refs[0].Range.Start != (Position{0, 4}) || refs[1].Range.Start != (Position{2, 4}) {
+		t.Errorf("wrong references of day %+v", refs)
+	}
+	if string(results["4"]) != "null" {
+		t.Errorf("builtins have no definition, got %s", results["4"])
+	}
+}
+
+func TestHover(t *testing.T) {
+	results, _ := session(t, program,
+		at(1, "textDocument/hover", 2, 5),
+		at(2, "textDocument/hover", 2, 10),
+		at(3, "textDocument/hover", 1, 5),
+	)
+	tests := []struct {
+		id       string
+		expected string
+	}{
+		{"1", "let day: int = 86400"},
+		{"2", "builtin len\n```\nlen(x) returns"},
+		{"3", "let add: fn(any, any): any"},
+	}
+	for _, tt := range tests {
+		var h Hover
+		json.Unmarshal(results[tt.id], &h)
+		if !strings.Contains(h.Contents.Value, tt.expected) {
+			t.Errorf("hover %s wrong. expected to contain %q, got %q", tt.id, tt.expected, h.Contents.Value)
+		}
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	results, _ := session(t, program, at(1, "textDocument/completion", 5, 2), at(2, "textDocument/completion", 0, 0))
+
+	labels := func(id string) string {
+		var items []CompletionItem
+		json.Unmarshal(results[id], &items)
+		var names []string
+		for _, item := range items {
+			names = append(names, item.Label)
+		}
+		return strings.Join(names, " ")
+	}
+	builtins := strings.Join(evaluator.BuiltinNames(), " ")
+	if got := labels("1"); !strings.HasPrefix(got, "x y day add f "+builtins) || !strings.Contains(got, "let") {
+		t.Errorf("wrong completion inside f: %s", got)
+	}
+	if got := labels("2"); !strings.HasPrefix(got, builtins+" else") {
+		t.Errorf("lets are not in scope before they are declared: %s", got)
+	}
+}
+
+func TestDocumentSymbol(t *testing.T) {
+	results, _ := session(t, program, map[string]interface{}{"id": 1, "method": "textDocument/documentSymbol",
+		"params": map[string]interface{}{"textDocument": map[string]interface{}{"uri": uri}}})
+
+	var symbols []DocumentSymbol
+	json.Unmarshal(results["1"], &symbols)
+	var got []string
+	for _, s := range symbols {
+		got = append(got, fmt.Sprintf("%s %d %d-%d", s.Name, s.Kind, s.Range.Start.Line, s.Range.End.Line))
+	}
+	expected := "day 13 0-0, add 12 1-1, f 12 3-6"
+	if strings.Join(got, ", ") != expected {
+		t.Errorf("wrong symbols.\nexpected=%s\ngot=%s", expected, strings.Join(got, ", "))
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	results, _ := session(t, "let x=1;\nif(x>0){puts(x)}", map[string]interface{}{"id": 1, "method": "textDocument/formatting",
+		"params": map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri},
+			"options":      map[string]interface{}{"tabSize": 2, "insertSpaces": true},
+		}})
+
+	var edits []TextEdit
+	json.Unmarshal(results["1"], &edits)
+	if len(edits) != 1 {
+		t.Fatalf("expected one edit, got %+v", edits)
+	}
+	if edits[0].Range != (Range{End: Position{1, 16}}) {
+		t.Errorf("edit should replace the whole document, got %+v", edits[0].Range)
+	}
+	expected := "let x = 1;\nif (x > 0) {\n  puts(x);\n}\n"
+	if edits[0].NewText != expected {
+		t.Errorf("wrong formatting.\nexpected=%q\ngot=%q", expected, edits[0].NewText)
+	}
+}
+
+func TestPositions(t *testing.T) {
+	d := &document{}
+	d.update("let s = \"héllo😀\"; s", 1)
+	p := d.toPosition(d.fromPosition(Position{0, 18}))
+	if p != (Position{0, 18}) {
+		t.Errorf("round trip of a position after multi-byte characters gave %+v", p)
+	}
+	if ident := identAt(d.current.program, d.fromPosition(Position{0, 19})); ident == nil || ident.Value != "s" {
+		t.Errorf("identifier after multi-byte characters not found, got %v", ident)
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"go-interpreter-lexer/lsp"
+)
+
+// lspCommand runs the language server on stdin and stdout, monkey lsp
+func lspCommand(args []string) int {
+	fs := flag.NewFlagSet("lsp", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if err := lsp.NewServer(os.Stdin, os.Stdout).Run(); err != nil {
+		fmt.Fprintln(os.Stderr, "monkey lsp:", err)
+		return 1
+	}
+	return 0
+}
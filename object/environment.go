@@ -1,5 +1,7 @@
 package object
 
+import "sort"
+
 
 func NewEnclosedEnvironment(outer *Environment) *Environment{
 	env := NewEnvironment()
@@ -15,6 +17,8 @@ func NewEnvironment() *Environment{
 type Environment struct{
 	store map[string]Object
 	outer *Environment
+	// host is kept on the outermost environment only, see Host.
+	host interface{}
 }
 
 func (e *Environment) Get(name string) (Object, bool){
@@ -30,3 +34,55 @@ func (e *Environment) Set(name string, val Object) Object{
 	return val
 }
 
+/*
+	Host returns what the evaluator keeps for the programs evaluated in the outermost environment enclosing
+	e, such as the depth of their calls. Every environment of an evaluation shares it and environments made
+	with NewEnvironment never do, so separate evaluations can run at the same time.
+*/
+func (e *Environment) Host() interface{}{
+	return e.root().host
+}
+
+// SetHost sets what Host returns for e and every environment enclosed by the same outermost environment.
+func (e *Environment) SetHost(host interface{}){
+	e.root().host = host
+}
+
+func (e *Environment) root() *Environment{
+	for e.outer != nil {
+		e = e.outer
+	}
+	return e
+}
+
+// Outer returns the enclosing environment, nil for the global one.
+func (e *Environment) Outer() *Environment{
+	return e.outer
+}
+
+// Names returns the sorted names defined in e itself, not in the environments enclosing it.
+func (e *Environment) Names() []string{
+	names := make([]string, 0, len(e.store))
+	for name := range e.store{
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+
+/*
+	GetAt looks name up in the environment depth levels out from e, without searching the environments
+	in between. Used with the depth the resolver computed for an identifier.
+*/
+func (e *Environment) GetAt(depth int, name string) (Object, bool){
+	env := e
+	for i := 0; i < depth && env != nil; i++{
+		env = env.outer
+	}
+	if env == nil {
+		return nil, false
+	}
+	obj, ok := env.store[name]
+	return obj, ok
+}
@@ -0,0 +1,100 @@
+package object
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/big"
+	"strings"
+)
+
+/*
+	BigInt is an integer without a size limit. Integer arithmetic promotes to it when a result does not fit in
+	an int64 and 123n writes one. Value is never changed once the object is made, operations build a new one.
+*/
+type BigInt struct {
+	Value *big.Int
+}
+
+func (b *BigInt) Type() ObjectType { return BIGINT_OBJ }
+func (b *BigInt) Inspect() string  { return b.Value.String() }
+
+// HashKey of a big integer that fits in an int64 is the key of the Integer with the same value.
+func (b *BigInt) HashKey() HashKey {
+	if b.Value.IsInt64() {
+		return (&Integer{Value: b.Value.Int64()}).HashKey()
+	}
+	h := fnv.New64a()
+	h.Write([]byte(b.Value.String()))
+	return HashKey{Type: b.Type(), Value: h.Sum64()}
+}
+
+/*
+	Decimal is an exact fixed-point number, Unscaled / 10^Scale, 1.50 is 150 with a scale of 2. The scale is
+	never negative and is kept through arithmetic so money values print with the places they were written
+	with. Like BigInt a decimal is never changed once it is made.
+*/
+type Decimal struct {
+	Unscaled *big.Int
+	Scale    int32
+}
+
+// ParseDecimal parses digits with an optional sign and fraction, such as -12.50.
+func ParseDecimal(s string) (*Decimal, error) {
+	digits := s
+	var scale int32
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		digits = s[:i] + s[i+1:]
+		scale = int32(len(s) - i - 1)
+	}
+	unscaled, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal %q", s)
+	}
+	return &Decimal{Unscaled: unscaled, Scale: scale}, nil
+}
+
+func (d *Decimal) Type() ObjectType { return DECIMAL_OBJ }
+
+func (d *Decimal) Inspect() string {
+	digits := new(big.Int).Abs(d.Unscaled).String()
+	sign := ""
+	if d.Unscaled.Sign() < 0 {
+		sign = "-"
+	}
+	if d.Scale == 0 {
+		return sign + digits
+	}
+	if pad := int(d.Scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(d.Scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// Normalize returns the decimal without trailing zeros in its fraction, 1.50 becomes 1.5 and 2.00 becomes 2.
+func (d *Decimal) Normalize() *Decimal {
+	unscaled, scale := new(big.Int).Set(d.Unscaled), d.Scale
+	ten, r := big.NewInt(10), new(big.Int)
+	for scale > 0 && unscaled.Sign() != 0 {
+		q, m := new(big.Int).QuoRem(unscaled, ten, r)
+		if m.Sign() != 0 {
+			break
+		}
+		unscaled, scale = q, scale-1
+	}
+	if unscaled.Sign() == 0 {
+		scale = 0
+	}
+	return &Decimal{Unscaled: unscaled, Scale: scale}
+}
+
+// HashKey of a decimal is the same for every scale, a whole decimal has the key of the integer with its value.
+func (d *Decimal) HashKey() HashKey {
+	n := d.Normalize()
+	if n.Scale == 0 {
+		return (&BigInt{Value: n.Unscaled}).HashKey()
+	}
+	h := fnv.New64a()
+	h.Write([]byte(n.Inspect()))
+	return HashKey{Type: d.Type(), Value: h.Sum64()}
+}
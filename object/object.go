@@ -21,6 +21,12 @@ const(
 	BUILTIN_OBJ = "BUILTIN"
 	ARRAY_OBJ = "ARRAY"
 	HASH_OBJ = "HASH"
+	MODULE_OBJ = "MODULE"
+	BIGINT_OBJ = "BIGINT"
+	DECIMAL_OBJ = "DECIMAL"
+	REGEX_OBJ = "REGEX"
+	TIME_OBJ = "TIME"
+	DURATION_OBJ = "DURATION"
 )
 
 type Object interface{
@@ -73,6 +79,10 @@ func (rv *ReturnValue) Inspect() string{
 
 type Error struct{
 	Message string
+	// Exit is set on the error exit(code) returns, it unwinds the program like any error and the host then
+	// ends with Code.
+	Exit bool
+	Code int
 }
 
 func (e *Error) Type() ObjectType{
@@ -83,7 +93,12 @@ func (e *Error) Inspect() string{
 }
 
 type Function struct{
+	// Name is the name the function was declared or bound with, empty for anonymous functions.
+	Name string
 	Parameters []*ast.Identifier
+	// Defaults and Rest are those of the function literal, see ast.FunctionLiteral.
+	Defaults []ast.Expression
+	Rest bool
 	Body *ast.BlockStatement
 	Env *Environment
 }
@@ -96,11 +111,21 @@ func (f *Function) Inspect() string{
 
 	params := []string{}
 
-	for _, param := range f.Parameters{
-		params = append(params, param.String())
+	for i, param := range f.Parameters{
+		p := param.String()
+		if f.Rest && i == len(f.Parameters)-1 {
+			p = "..." + p
+		}
+		if i < len(f.Defaults) && f.Defaults[i] != nil {
+			p += " = " + f.Defaults[i].String()
+		}
+		params = append(params, p)
 	}
 
 	out.WriteString("fn")
+	if f.Name != "" {
+		out.WriteString(" " + f.Name)
+	}
 	out.WriteString("(")
 	out.WriteString(strings.Join(params,", "))
 	out.WriteString(")")
@@ -121,10 +146,12 @@ func (s *String) Inspect() string{
 	return s.Value
 }
 
-type BuiltInFunction func(args ...Object) Object
+type BuiltInFunction func(env *Environment, args ...Object) Object
 
 type Builtin struct{
 	Fn BuiltInFunction
+	// Doc is a short description of the builtin shown by editors, starting with its signature.
+	Doc string
 }
 
 func (b *Builtin) Type() ObjectType{
@@ -134,6 +161,21 @@ func (b *Builtin) Inspect() string{
 	return "builtin function"
 }
 
+// Module is a named group of builtins reached with member access, strings.upper.
+type Module struct{
+	Name string
+	// Doc is a short description of the module shown by editors.
+	Doc string
+	Members map[string]*Builtin
+}
+
+func (m *Module) Type() ObjectType{
+	return MODULE_OBJ
+}
+func (m *Module) Inspect() string{
+	return "module " + m.Name
+}
+
 type Array struct{
 	Elements []Object
 }
@@ -188,8 +230,50 @@ type HashPair struct{
 	Value Object
 }
 
+/*
+	Hash keeps its pairs in the order their keys were first set, Inspect and every builtin that lists the
+	pairs use that order. Pairs is only changed through Set and Delete so the order stays in step with it.
+*/
 type Hash struct{
 	Pairs map[HashKey]HashPair
+	keys []HashKey
+}
+
+func NewHash() *Hash{
+	return &Hash{Pairs: make(map[HashKey]HashPair)}
+}
+
+// Set adds or replaces a pair, a replaced pair keeps its place.
+func (h *Hash) Set(key HashKey, pair HashPair){
+	if h.Pairs == nil {
+		h.Pairs = make(map[HashKey]HashPair)
+	}
+	if _, ok := h.Pairs[key]; !ok {
+		h.keys = append(h.keys, key)
+	}
+	h.Pairs[key] = pair
+}
+
+func (h *Hash) Delete(key HashKey){
+	if _, ok := h.Pairs[key]; !ok {
+		return
+	}
+	delete(h.Pairs, key)
+	for i, k := range h.keys{
+		if k == key {
+			h.keys = append(h.keys[:i:i], h.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Ordered returns the pairs in the order their keys were first set.
+func (h *Hash) Ordered() []HashPair{
+	pairs := make([]HashPair, 0, len(h.keys))
+	for _, k := range h.keys{
+		pairs = append(pairs, h.Pairs[k])
+	}
+	return pairs
 }
 
 func (h *Hash) Type() ObjectType {
@@ -198,7 +282,7 @@ func (h *Hash) Type() ObjectType {
 func (h *Hash) Inspect() string{
 	var out bytes.Buffer
 	pairs := []string{}
-	for _, pair := range h.Pairs{
+	for _, pair := range h.Ordered(){
 		pairs = append(pairs, fmt.Sprintf("%s:%s",pair.Key.Inspect(), pair.Value.Inspect()))
 	}
 	out.WriteString("{")
@@ -1,6 +1,9 @@
 package object
 
-import "testing"
+import (
+	"math/big"
+	"testing"
+)
 
 func TestStringHashKey(t *testing.T){
 	hello1 := &String{Value: "Hello World"}
@@ -20,4 +23,37 @@ func TestStringHashKey(t *testing.T){
 		t.Errorf("strings with different content have same hash keys")
 	}
 
-}
\ No newline at end of file
+}
+func TestNumberHashKey(t *testing.T){
+	whole, _ := ParseDecimal("42.000")
+	half, _ := ParseDecimal("0.50")
+	otherHalf, _ := ParseDecimal("0.5")
+	large := &BigInt{Value: new(big.Int).Lsh(big.NewInt(1), 64)}
+	larger := &BigInt{Value: new(big.Int).Lsh(big.NewInt(1), 65)}
+
+	if whole.HashKey() != (&Integer{Value: 42}).HashKey() || (&BigInt{Value: big.NewInt(42)}).HashKey() != (&Integer{Value: 42}).HashKey(){
+		t.Errorf("numbers with the same whole value have different hash keys")
+	}
+	if half.HashKey() != otherHalf.HashKey(){
+		t.Errorf("decimals with the same value and different scales have different hash keys")
+	}
+	if large.HashKey() == larger.HashKey(){
+		t.Errorf("different big integers have the same hash key")
+	}
+}
+
+func TestDecimalInspect(t *testing.T){
+	tests := map[string]string{"12.50": "12.50", "-0.05": "-0.05", ".5": "0.5", "7": "7", "-3.": "-3"}
+	for input, expected := range tests{
+		d, err := ParseDecimal(input)
+		if err != nil {
+			t.Fatalf("%q: %v", input, err)
+		}
+		if d.Inspect() != expected {
+			t.Errorf("%q: expected %q got %q", input, expected, d.Inspect())
+		}
+	}
+	if _, err := ParseDecimal("1.2.3"); err == nil {
+		t.Errorf("expected an error for 1.2.3")
+	}
+}
@@ -0,0 +1,11 @@
+package object
+
+import "regexp"
+
+// Regex is a compiled regular expression with the syntax of Go's regexp package, made by regex.compile.
+type Regex struct {
+	Regexp *regexp.Regexp
+}
+
+func (r *Regex) Type() ObjectType { return REGEX_OBJ }
+func (r *Regex) Inspect() string  { return "/" + r.Regexp.String() + "/" }
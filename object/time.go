@@ -0,0 +1,19 @@
+package object
+
+import "time"
+
+// Time is an instant with the time zone it is shown in, made by the time module.
+type Time struct {
+	Value time.Time
+}
+
+func (t *Time) Type() ObjectType { return TIME_OBJ }
+func (t *Time) Inspect() string  { return t.Value.Format(time.RFC3339Nano) }
+
+// Duration is the time between two instants, 1h30m0s, the difference of two times.
+type Duration struct {
+	Value time.Duration
+}
+
+func (d *Duration) Type() ObjectType { return DURATION_OBJ }
+func (d *Duration) Inspect() string  { return d.Value.String() }
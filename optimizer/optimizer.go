@@ -0,0 +1,326 @@
+/*
+	Package optimizer rewrites a parsed program so that less work is done each time it is evaluated.
+	Arithmetic, string and boolean expressions whose operands are literals are folded into a single literal,
+	if expressions with a literal condition lose the branch that can never run and lets bound to a literal
+	are inlined where they are used.
+
+	Only expressions that the evaluator would compute without an error are folded, so a type mismatch or
+	an unknown operator is still reported when the program runs, as is a division by zero.
+*/
+package optimizer
+
+import (
+	"strconv"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/resolver"
+	"go-interpreter-lexer/token"
+)
+
+type optimizer struct {
+	r *resolver.Resolver
+	// consts holds the lets found so far that are bound once to a literal.
+	consts map[*resolver.Binding]constLet
+}
+
+type constLet struct {
+	pos   token.Position
+	value ast.Expression
+}
+
+// Optimize rewrites program in place and returns it.
+func Optimize(program *ast.Program) *ast.Program {
+	o := &optimizer{
+		r:      resolver.New(nil),
+		consts: make(map[*resolver.Binding]constLet),
+	}
+	o.r.Resolve(program)
+	program.Statements = o.statements(program.Statements, true)
+	return program
+}
+
+/*
+	statements optimizes a list of statements, top is true for the body of the program or of a function
+	where a let always runs before the statements that follow it.
+*/
+func (o *optimizer) statements(stmts []ast.Statement, top bool) []ast.Statement {
+	for i, s := range stmts {
+		stmts[i] = o.statement(s, top)
+	}
+	return stmts
+}
+
+func (o *optimizer) statement(s ast.Statement, top bool) ast.Statement {
+	switch s := s.(type) {
+	case *ast.LetStatement:
+		if s == nil {
+			return s
+		}
+		s.Value = o.expr(s.Value)
+		if top && s.Name != nil && isLiteral(s.Value) {
+			b := o.r.Binding(s.Name)
+			if b != nil && b.Kind == resolver.Let && len(b.Decls) == 1 {
+				o.consts[b] = constLet{pos: s.Token.Pos, value: s.Value}
+			}
+		}
+	case *ast.FunctionStatement:
+		if s != nil && s.Function != nil {
+			o.expr(s.Function)
+		}
+	case *ast.ReturnStatement:
+		if s != nil {
+			s.ReturnValue = o.expr(s.ReturnValue)
+		}
+	case *ast.ExpressionStatement:
+		if s != nil {
+			s.Expression = o.expr(s.Expression)
+		}
+	case *ast.BlockStatement:
+		o.block(s, false)
+	}
+	return s
+}
+
+func (o *optimizer) block(b *ast.BlockStatement, top bool) {
+	if b != nil {
+		b.Statements = o.statements(b.Statements, top)
+	}
+}
+
+func (o *optimizer) expr(e ast.Expression) ast.Expression {
+	switch e := e.(type) {
+	case *ast.Identifier:
+		return o.identifier(e)
+	case *ast.PrefixExpression:
+		e.Right = o.expr(e.Right)
+		return foldPrefix(e)
+	case *ast.InfixExpression:
+		e.Left = o.expr(e.Left)
+		e.Right = o.expr(e.Right)
+		return foldInfix(e)
+	case *ast.IfExpression:
+		e.Condition = o.expr(e.Condition)
+		o.block(e.Consequence, false)
+		o.block(e.Alternative, false)
+		return pruneIf(e)
+	case *ast.FunctionLiteral:
+		for i, d := range e.Defaults {
+			if d != nil {
+				e.Defaults[i] = o.expr(d)
+			}
+		}
+		o.block(e.Body, true)
+	case *ast.SpreadExpression:
+		e.Value = o.expr(e.Value)
+	case *ast.CallExpression:
+		e.Function = o.expr(e.Function)
+		for i, a := range e.Arguments {
+			e.Arguments[i] = o.expr(a)
+		}
+	case *ast.ArrayLiteral:
+		for i, el := range e.Elements {
+			e.Elements[i] = o.expr(el)
+		}
+	case *ast.IndexExpression:
+		e.Left = o.expr(e.Left)
+		e.Index = o.expr(e.Index)
+	case *ast.MemberExpression:
+		e.Object = o.expr(e.Object)
+	case *ast.HashLiteral:
+		pairs := make(map[ast.Expression]ast.Expression, len(e.Pairs))
+		for k, v := range e.Pairs {
+			pairs[o.expr(k)] = o.expr(v)
+		}
+		e.Pairs = pairs
+	}
+	return e
+}
+
+// identifier replaces a reference to a constant let with its value if the let has run by then.
+func (o *optimizer) identifier(ident *ast.Identifier) ast.Expression {
+	b := o.r.Binding(ident)
+	c, ok := o.consts[b]
+	if !ok || !c.pos.Before(ident.Token.Pos) {
+		return ident
+	}
+	return relocate(c.value, ident.Token.Pos)
+}
+
+// relocate copies a literal giving it the position of the node it replaces.
+func relocate(lit ast.Expression, pos token.Position) ast.Expression {
+	switch l := lit.(type) {
+	case *ast.IntegerLiteral:
+		c := *l
+		c.Token.Pos = pos
+		return &c
+	case *ast.BigIntLiteral:
+		c := *l
+		c.Token.Pos = pos
+		return &c
+	case *ast.DecimalLiteral:
+		c := *l
+		c.Token.Pos = pos
+		return &c
+	case *ast.StringLiteral:
+		c := *l
+		c.Token.Pos = pos
+		return &c
+	case *ast.Boolean:
+		c := *l
+		c.Token.Pos = pos
+		return &c
+	}
+	return lit
+}
+
+func isLiteral(e ast.Expression) bool {
+	switch e.(type) {
+	case *ast.IntegerLiteral, *ast.BigIntLiteral, *ast.DecimalLiteral, *ast.StringLiteral, *ast.Boolean:
+		return true
+	}
+	return false
+}
+
+func foldPrefix(pe *ast.PrefixExpression) ast.Expression {
+	switch right := pe.Right.(type) {
+	case *ast.IntegerLiteral:
+		switch pe.Operator {
+		case "-":
+			if right.Value == minInt {
+				return pe
+			}
+			return integer(pe.Token.Pos, -right.Value)
+		case "!":
+			return boolean(pe.Token.Pos, false)
+		}
+	case *ast.StringLiteral:
+		if pe.Operator == "!" {
+			return boolean(pe.Token.Pos, false)
+		}
+	case *ast.Boolean:
+		if pe.Operator == "!" {
+			return boolean(pe.Token.Pos, !right.Value)
+		}
+	}
+	return pe
+}
+
+const (
+	maxInt = int64(^uint64(0) >> 1)
+	minInt = -maxInt - 1
+)
+
+func foldInfix(ie *ast.InfixExpression) ast.Expression {
+	switch left := ie.Left.(type) {
+	case *ast.IntegerLiteral:
+		right, ok := ie.Right.(*ast.IntegerLiteral)
+		if !ok {
+			return ie
+		}
+		a, b := left.Value, right.Value
+		switch ie.Operator {
+		case "+":
+			if (b > 0 && a > maxInt-b) || (b < 0 && a < minInt-b) {
+				return ie
+			}
+			return integer(left.Token.Pos, a+b)
+		case "-":
+			if (b < 0 && a > maxInt+b) || (b > 0 && a < minInt+b) {
+				return ie
+			}
+			return integer(left.Token.Pos, a-b)
+		case "*":
+			if a != 0 && b != 0 && ((a*b)/b != a || (a == -1 && b == minInt) || (b == -1 && a == minInt)) {
+				return ie
+			}
+			return integer(left.Token.Pos, a*b)
+		case "/":
+			if b == 0 || (a == minInt && b == -1) {
+				return ie
+			}
+			return integer(left.Token.Pos, a/b)
+		case "%":
+			if b == 0 || (a == minInt && b == -1) {
+				return ie
+			}
+			return integer(left.Token.Pos, a%b)
+		case "<":
+			return boolean(left.Token.Pos, a < b)
+		case ">":
+			return boolean(left.Token.Pos, a > b)
+		case "==":
+			return boolean(left.Token.Pos, a == b)
+		case "!=":
+			return boolean(left.Token.Pos, a != b)
+		}
+	case *ast.Boolean:
+		right, ok := ie.Right.(*ast.Boolean)
+		if !ok {
+			return ie
+		}
+		switch ie.Operator {
+		case "==":
+			return boolean(left.Token.Pos, left.Value == right.Value)
+		case "!=":
+			return boolean(left.Token.Pos, left.Value != right.Value)
+		}
+	case *ast.StringLiteral:
+		right, ok := ie.Right.(*ast.StringLiteral)
+		if !ok {
+			return ie
+		}
+		a, b := left.Value, right.Value
+		switch ie.Operator {
+		case "+":
+			return &ast.StringLiteral{Token: token.Token{Type: token.STRING, Literal: a + b, Pos: left.Token.Pos}, Value: a + b}
+		case "<":
+			return boolean(left.Token.Pos, a < b)
+		case ">":
+			return boolean(left.Token.Pos, a > b)
+		case "==":
+			return boolean(left.Token.Pos, a == b)
+		case "!=":
+			return boolean(left.Token.Pos, a != b)
+		}
+	}
+	return ie
+}
+
+/*
+	pruneIf drops the branch of an if that can never run when the condition is a literal. The if itself is
+	kept, with a literal condition, so it still evaluates to the value of the branch or to null.
+*/
+func pruneIf(ie *ast.IfExpression) ast.Expression {
+	var truthy bool
+	switch c := ie.Condition.(type) {
+	case *ast.Boolean:
+		truthy = c.Value
+	case *ast.IntegerLiteral, *ast.BigIntLiteral, *ast.DecimalLiteral, *ast.StringLiteral:
+		truthy = true
+	default:
+		return ie
+	}
+
+	cond := boolean(ie.Token.Pos, truthy)
+	if truthy {
+		return &ast.IfExpression{Token: ie.Token, Condition: cond, Consequence: ie.Consequence}
+	}
+	if ie.Alternative != nil {
+		return &ast.IfExpression{Token: ie.Token, Condition: boolean(ie.Token.Pos, true), Consequence: ie.Alternative}
+	}
+	empty := &ast.BlockStatement{Token: token.Token{Type: token.LBRACE, Literal: "{", Pos: ie.Token.Pos}}
+	return &ast.IfExpression{Token: ie.Token, Condition: cond, Consequence: empty}
+}
+
+func integer(pos token.Position, v int64) *ast.IntegerLiteral {
+	lit := strconv.FormatInt(v, 10)
+	return &ast.IntegerLiteral{Token: token.Token{Type: token.INT, Literal: lit, Pos: pos}, Value: v}
+}
+
+func boolean(pos token.Position, v bool) *ast.Boolean {
+	tt, lit := token.TokenType(token.FALSE), "false"
+	if v {
+		tt, lit = token.TRUE, "true"
+	}
+	return &ast.Boolean{Token: token.Token{Type: tt, Literal: lit, Pos: pos}, Value: v}
+}
@@ -0,0 +1,82 @@
+package optimizer
+
+import (
+	"testing"
+
+	"go-interpreter-lexer/evaluator"
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/object"
+	"go-interpreter-lexer/parser"
+)
+
+func TestOptimize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"60 * 60 * 24", "86400"},
+		{"1 + 2 * 3 - 4 / 2", "5"},
+		{`"a" + "b" + "c"`, "abc"},
+		{"!true", "false"},
+		{"!!5", "true"},
+		{"-(2 + 3)", "-5"},
+		{"1 < 2 == true", "true"},
+		{"5 + true", "(5 + true)"},
+		{`5 + "a"`, `(5 + a)`},
+		{"1 / 0", "(1 / 0)"},
+		{"17 % 5 * 2", "4"},
+		{"1 % 0", "(1 % 0)"},
+		{"9223372036854775807 + 1", "(9223372036854775807 + 1)"},
+		{`"a" == "a"`, "true"},
+		{`"ab" < "b"`, "true"},
+		{"let x = 2 * 3; x * x", "let x = 6;36"},
+		{"let f = fn(y) { let k = 10; y * k }", "let f = fn(y) let k = 10;(y * 10);"},
+		{"if (1 > 2) { a } else { b }", "iftrueb "},
+		{"if (1 < 2) { a } else { b }", "iftruea "},
+		{"if (false) { a }", "iffalse "},
+		{"let x = 1; let f = fn() { x }; let x = 2;", "let x = 1;let f = fn() x;let x = 2;"},
+		{"let x = 1; if (y) { let z = 3 }; z", "let x = 1;ifylet z = 3; z"},
+		{"x; let x = 1;", "xlet x = 1;"},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%q: parser errors %v", tt.input, p.Errors())
+		}
+		got := Optimize(program).String()
+		if got != tt.expected {
+			t.Errorf("%q: expected=%q, got=%q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestSameResult(t *testing.T) {
+	inputs := []string{
+		"let a = 10; let b = a * 2; let f = fn(x) { if (x > b) { x - a } else { a * 3 } }; [f(5), f(50)]",
+		`let s = "x" + "y"; let f = fn() { s + "z" }; f()`,
+		"if (1 > 2) { 10 }",
+		`5 + "a"`,
+		"true + false",
+		"let k = -3; fn(n) { n * k }(2)",
+		`["a" == "a", "a" != "a", "b" > "a"]`,
+		"let price = 2.50; let n = 3n; [price * 4, n * 9223372036854775807]",
+	}
+
+	for _, input := range inputs {
+		plain := evaluate(input, false)
+		optimized := evaluate(input, true)
+		if plain.Inspect() != optimized.Inspect() {
+			t.Errorf("%q: optimized program gives %q, expected %q", input, optimized.Inspect(), plain.Inspect())
+		}
+	}
+}
+
+func evaluate(input string, optimize bool) object.Object {
+	program := parser.New(lexer.New(input)).ParseProgram()
+	if optimize {
+		Optimize(program)
+	}
+	return evaluator.Eval(program, object.NewEnvironment())
+}
@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"testing"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/lexer"
+)
+
+var fuzzSeeds = []string{
+	"",
+	`let add = fn(a, b) { a + b; }; add(1, 2 * 3);`,
+	`if (x != 10) { return "ten"; } else { [1, 2][0] }`,
+	`{"a": 1, true: [x]}["a"] == !y`,
+	`let f = fn(a: int, b: {string: [bool]}): fn(int): int { return a }`,
+	"return",
+	"let = ;",
+	"a[",
+	"{1:",
+	"fn(,)",
+}
+
+// FuzzParseProgram checks the parser returns a program whose nodes can all be visited and printed.
+func FuzzParseProgram(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		program := New(lexer.New(input)).ParseProgram()
+		for _, stmt := range program.Statements {
+			if stmt == nil {
+				t.Fatal("nil statement in the program")
+			}
+		}
+		ast.Inspect(program, func(n ast.Node) bool {
+			ast.Pos(n)
+			return true
+		})
+		_ = program.String()
+	})
+}
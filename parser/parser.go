@@ -4,8 +4,11 @@ import (
 	"go-interpreter-lexer/lexer"
 	"go-interpreter-lexer/token"
 	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/diagnostic"
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
 )
 
 
@@ -35,8 +38,10 @@ var precedences = map[token.TokenType] int {
 	token.MINUS: SUM,
 	token.SLASH: PRODUCT,
 	token.ASTERISK: PRODUCT,
+	token.PERCENT: PRODUCT,
 	token.LPAREN: CALL,
 	token.LBRACKET: INDEX,
+	token.DOT: INDEX,
 }
 
 // precedence function to determine the presedecne of the current operator with other operators.
@@ -61,6 +66,7 @@ type Parser struct{
 	curToken token.Token
 	peekToken token.Token
 	errors []string
+	diagnostics []diagnostic.Diagnostic
 	// adding a series of infix and prefix func holder.
 	prefixParseFuncs map[token.TokenType]prefixParseFn
 	infixParseFuncs map[token.TokenType]infixParseFn
@@ -103,6 +109,8 @@ func New(l *lexer.Lexer) *Parser{
 	p.prefixParseFuncs = make(map[token.TokenType]prefixParseFn)
 	p.registerPrefixFn(token.IDENT, p.parseIdentifier)
 	p.registerPrefixFn(token.INT, p.parseInteger)
+	p.registerPrefixFn(token.BIGINT, p.parseBigInt)
+	p.registerPrefixFn(token.DECIMAL, p.parseDecimal)
 	p.registerPrefixFn(token.BANG, p.parsePrefixExpression)
 	p.registerPrefixFn(token.MINUS, p.parsePrefixExpression)
 	p.registerPrefixFn(token.TRUE, p.parseBoolean)
@@ -122,10 +130,12 @@ func New(l *lexer.Lexer) *Parser{
 	p.registerInfixFn(token.LT, p.parseInfixExpression)
 	p.registerInfixFn(token.SLASH, p.parseInfixExpression)
 	p.registerInfixFn(token.ASTERISK, p.parseInfixExpression)
+	p.registerInfixFn(token.PERCENT, p.parseInfixExpression)
 	p.registerInfixFn(token.EQ, p.parseInfixExpression)
 	p.registerInfixFn(token.NOT_EQ, p.parseInfixExpression)
 	p.registerInfixFn(token.LPAREN, p.parseCallExpression)
 	p.registerInfixFn(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfixFn(token.DOT, p.parseMemberExpression)
 
 	// calling next token twice to get current and peek token populated.
     p.nextToken()
@@ -166,6 +176,15 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression{
 	return exp
 }
 
+func (p *Parser) parseMemberExpression(left ast.Expression) ast.Expression{
+	exp := &ast.MemberExpression{Token: p.curToken, Object: left}
+	if !p.expectPeek(token.IDENT){
+		return nil
+	}
+	exp.Property = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	return exp
+}
+
 func (p *Parser) parseArrayLiteral() ast.Expression{
 	array := &ast.ArrayLiteral{Token: p.curToken}
 	array.Elements = p.parseExpressionList(token.RBRACKET)
@@ -179,11 +198,11 @@ func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression{
 			return list
 		}
 		p.nextToken()
-		list = append(list, p.parseExpression(LOWEST))
+		list = append(list, p.parseListElement(end))
 		for p.peekTokenIs(token.COMMA){
 			p.nextToken()
 			p.nextToken()
-			list = append(list, p.parseExpression(LOWEST))
+			list = append(list, p.parseListElement(end))
 		}
 		if !p.expectPeek(end){
 			return nil
@@ -191,6 +210,17 @@ func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression{
 	return list
 }
 
+// parses an element of a list, the arguments of a call can be spread with ... in front of them.
+func (p *Parser) parseListElement(end token.TokenType) ast.Expression{
+	if end == token.RPAREN && p.curTokenIs(token.ELLIPSIS){
+		spread := &ast.SpreadExpression{Token: p.curToken}
+		p.nextToken()
+		spread.Value = p.parseExpression(LOWEST)
+		return spread
+	}
+	return p.parseExpression(LOWEST)
+}
+
 func (p *Parser) parseStringLiteral() ast.Expression{
 	return &ast.StringLiteral{ Token: p.curToken, Value: p.curToken.Literal}
 }
@@ -227,42 +257,155 @@ func (p *Parser) parseCallArguments() []ast.Expression{
 
 func (p *Parser) parseFunctionLiteral() ast.Expression{
 	fLit := &ast.FunctionLiteral{ Token: p.curToken}
+	if !p.parseFunction(fLit){
+		return nil
+	}
+	return fLit
+}
 
+// parses the parameters, result type and body that follow fn or the name of a declared function.
+func (p *Parser) parseFunction(fLit *ast.FunctionLiteral) bool{
 	if !p.expectPeek(token.LPAREN){
-		return nil
+		return false
+	}
+	if !p.parseFunctionParameters(fLit){
+		return false
+	}
+
+	if p.peekTokenIs(token.COLON){
+		p.nextToken()
+		p.nextToken()
+		fLit.ResultType = p.parseType()
 	}
-	fLit.Parameters = p.parseFunctionParameters()
 
 	if !p.expectPeek(token.LBRACE){
-		return nil
+		return false
 	}
 
 	fLit.Body = p.parseBlockStatement()
-
-	return fLit
+	return true
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier{
-	identifiers := []*ast.Identifier{}
+/*
+	parses the parameter list of a function into fLit, each parameter is a name with an optional type
+	annotation and default value, fn(a: int, b = 10). The last parameter can be a rest parameter, ...rest.
+	The types and defaults slices have a nil entry for parameters without one.
+*/
+func (p *Parser) parseFunctionParameters(fLit *ast.FunctionLiteral) bool{
+	fLit.Parameters = []*ast.Identifier{}
+	fLit.ParameterTypes = []ast.TypeExpr{}
+	fLit.Defaults = []ast.Expression{}
 
 	if p.peekTokenIs(token.RPAREN){
 		p.nextToken()
-		return identifiers
+		return true
 	}
-	p.nextToken()
-	ident := &ast.Identifier{ Token: p.curToken, Value: p.curToken.Literal}
-	identifiers = append(identifiers,ident)
+	for {
+		if fLit.Rest {
+			p.addError(p.peekToken.Pos, "a rest parameter must be the last parameter")
+			return false
+		}
+		if p.peekTokenIs(token.ELLIPSIS){
+			p.nextToken()
+			fLit.Rest = true
+		}
+		if !p.expectPeek(token.IDENT){
+			return false
+		}
+		ident := &ast.Identifier{ Token: p.curToken, Value: p.curToken.Literal}
+		fLit.Parameters = append(fLit.Parameters, ident)
+		fLit.ParameterTypes = append(fLit.ParameterTypes, p.parseOptionalType())
+
+		var def ast.Expression
+		if p.peekTokenIs(token.ASSIGN){
+			if fLit.Rest {
+				p.addError(p.peekToken.Pos, "a rest parameter cannot have a default value")
+				return false
+			}
+			p.nextToken()
+			p.nextToken()
+			def = p.parseExpression(LOWEST)
+		} else if !fLit.Rest && len(fLit.Defaults) > 0 && fLit.Defaults[len(fLit.Defaults)-1] != nil {
+			p.addError(ident.Token.Pos, fmt.Sprintf("parameter %s without a default value follows one with a default", ident.Value))
+			return false
+		}
+		fLit.Defaults = append(fLit.Defaults, def)
 
-	for p.peekTokenIs(token.COMMA){
-		p.nextToken()
+		if !p.peekTokenIs(token.COMMA){
+			break
+		}
 		p.nextToken()
-		id := &ast.Identifier{ Token: p.curToken, Value: p.curToken.Literal}
-		identifiers = append(identifiers,id)
 	}
-	if !p.expectPeek(token.RPAREN){
+	return p.expectPeek(token.RPAREN)
+}
+
+// parses ": type" following a name if it is there.
+func (p *Parser) parseOptionalType() ast.TypeExpr{
+	if !p.peekTokenIs(token.COLON){
 		return nil
 	}
-	return identifiers
+	p.nextToken()
+	p.nextToken()
+	return p.parseType()
+}
+
+/*
+	parses a type annotation starting at the current token
+	int | string | bool | null | any | [type] | {type: type} | fn(type, ...): type
+*/
+func (p *Parser) parseType() ast.TypeExpr{
+	switch p.curToken.Type{
+		case token.IDENT:
+			return &ast.NamedType{Token: p.curToken, Name: p.curToken.Literal}
+		case token.LBRACKET:
+			at := &ast.ArrayType{Token: p.curToken}
+			p.nextToken()
+			at.Element = p.parseType()
+			if at.Element == nil || !p.expectPeek(token.RBRACKET){
+				return nil
+			}
+			return at
+		case token.LBRACE:
+			ht := &ast.HashType{Token: p.curToken}
+			p.nextToken()
+			ht.Key = p.parseType()
+			if ht.Key == nil || !p.expectPeek(token.COLON){
+				return nil
+			}
+			p.nextToken()
+			ht.Value = p.parseType()
+			if ht.Value == nil || !p.expectPeek(token.RBRACE){
+				return nil
+			}
+			return ht
+		case token.FUNCTION:
+			ft := &ast.FunctionType{Token: p.curToken}
+			if !p.expectPeek(token.LPAREN){
+				return nil
+			}
+			for !p.peekTokenIs(token.RPAREN){
+				p.nextToken()
+				param := p.parseType()
+				if param == nil {
+					return nil
+				}
+				ft.Parameters = append(ft.Parameters, param)
+				if !p.peekTokenIs(token.RPAREN) && !p.expectPeek(token.COMMA){
+					return nil
+				}
+			}
+			p.nextToken()
+			if p.peekTokenIs(token.COLON){
+				p.nextToken()
+				p.nextToken()
+				if ft.Result = p.parseType(); ft.Result == nil {
+					return nil
+				}
+			}
+			return ft
+	}
+	p.addError(p.curToken.Pos, fmt.Sprintf("expected a type got: %s", p.curToken.Type))
+	return nil
 }
 
 func (p *Parser) parseIfExpression() ast.Expression{
@@ -307,6 +450,7 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement{
 		}
 		p.nextToken()
 	}
+	block.End = p.curToken.Pos
 	return block
 }
 
@@ -349,13 +493,34 @@ func (p *Parser) parseInteger() ast.Expression{
 	value, err := strconv.ParseInt(p.curToken.Literal, 0,64)
 	if err != nil{
 		msg := fmt.Sprintf("could not parse integer literal %q as integer", p.curToken.Literal)
-		p.errors = append(p.errors,msg)
+		p.addError(p.curToken.Pos, msg)
 		return nil
 	}
 	il.Value = value
 	return il
 }
 
+func (p *Parser) parseBigInt() ast.Expression{
+	digits := strings.TrimSuffix(p.curToken.Literal, "n")
+	value, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		p.addError(p.curToken.Pos, fmt.Sprintf("could not parse %q as a big integer", p.curToken.Literal))
+		return nil
+	}
+	return &ast.BigIntLiteral{Token: p.curToken, Value: value}
+}
+
+func (p *Parser) parseDecimal() ast.Expression{
+	literal := p.curToken.Literal
+	point := strings.IndexByte(literal, '.')
+	unscaled, ok := new(big.Int).SetString(literal[:point] + literal[point+1:], 10)
+	if !ok {
+		p.addError(p.curToken.Pos, fmt.Sprintf("could not parse %q as a decimal", literal))
+		return nil
+	}
+	return &ast.DecimalLiteral{Token: p.curToken, Unscaled: unscaled, Scale: int32(len(literal) - point - 1)}
+}
+
 func (p *Parser) parsePrefixExpression() ast.Expression{
 	pe := &ast.PrefixExpression{
 		Token : p.curToken,
@@ -370,9 +535,24 @@ func (p *Parser) Errors() []string{
 	return p.errors
 }
 
+// Diagnostics returns the same errors as Errors along with the position they were found at.
+func (p *Parser) Diagnostics() []diagnostic.Diagnostic{
+	return p.diagnostics
+}
+
+func (p *Parser) addError(pos token.Position, msg string){
+	p.errors = append(p.errors,msg)
+	p.diagnostics = append(p.diagnostics, diagnostic.Diagnostic{
+		Pos: pos,
+		Severity: diagnostic.Error,
+		Code: "syntax",
+		Message: strings.TrimSpace(msg),
+	})
+}
+
 func (p *Parser) peekError(t token.TokenType){
 	msg := fmt.Sprintf("expected next token to be %s got: %s", t, p.peekToken.Type)
-	p.errors = append(p.errors,msg)
+	p.addError(p.peekToken.Pos, msg)
 }
 
 func (p *Parser) nextToken(){
@@ -395,11 +575,23 @@ func (p *Parser) ParseProgram() *ast.Program{
 }
 
 func (p *Parser) parseStatement() ast.Statement{
+	// a nil *ast.LetStatement must not become a non-nil ast.Statement.
 	switch p.curToken.Type{
 		case token.LET:
-			return p.parseLetStatement()
+			if stmt := p.parseLetStatement(); stmt != nil{
+				return stmt
+			}
+			return nil
 		case token.RETURN:
 			return p.parseReturnStatement()
+		case token.FUNCTION:
+			if !p.peekTokenIs(token.IDENT){
+				return p.parseExpressionStatement()
+			}
+			if stmt := p.parseFunctionStatement(); stmt != nil{
+				return stmt
+			}
+			return nil
 		default:
 			return p.parseExpressionStatement()
 	}
@@ -413,12 +605,34 @@ func (p *Parser) parseLetStatement() *ast.LetStatement{
 	}
 
 	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	stmt.Type = p.parseOptionalType()
 	if !p.expectPeek(token.ASSIGN){
 		return nil
 	}
 
 	p.nextToken()
 	stmt.Value = p.parseExpression(LOWEST)
+	// a function bound by a let is known by the name of the let.
+	if fLit, ok := stmt.Value.(*ast.FunctionLiteral); ok && fLit.Name == ""{
+		fLit.Name = stmt.Name.Value
+	}
+
+	for p.peekTokenIs(token.SEMICOLON){
+		p.nextToken()
+	}
+	return stmt
+}
+
+// parses a function declaration, fn name(params) { body }.
+func (p *Parser) parseFunctionStatement() *ast.FunctionStatement{
+	stmt := &ast.FunctionStatement{Token: p.curToken}
+	p.nextToken()
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	stmt.Function = &ast.FunctionLiteral{Token: stmt.Token, Name: stmt.Name.Value}
+	if !p.parseFunction(stmt.Function){
+		return nil
+	}
 
 	for p.peekTokenIs(token.SEMICOLON){
 		p.nextToken()
@@ -428,11 +642,18 @@ func (p *Parser) parseLetStatement() *ast.LetStatement{
 
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement{
 	stmt := &ast.ReturnStatement{Token: p.curToken}
-	p.nextToken()
 
+	// a return without a value returns null.
+	if p.peekTokenIs(token.SEMICOLON) || p.peekTokenIs(token.RBRACE) || p.peekTokenIs(token.EOF){
+		if p.peekTokenIs(token.SEMICOLON){
+			p.nextToken()
+		}
+		return stmt
+	}
+	p.nextToken()
 	stmt.ReturnValue = p.parseExpression(LOWEST)
 
-	for !p.curTokenIs(token.SEMICOLON) {
+	if p.peekTokenIs(token.SEMICOLON){
 		p.nextToken()
 	}
 	return stmt
@@ -489,7 +710,7 @@ func (p *Parser) parseExpression(precedence int) ast.Expression{
 
 func (p *Parser) noPrefixParseFnError(t token.TokenType){
 	msg := fmt.Sprintf("no prefix parse function for %s found\n", t)
-	p.errors = append(p.errors,msg)
+	p.addError(p.curToken.Pos, msg)
 }
 
 
@@ -6,6 +6,7 @@ import (
 	"go-interpreter-lexer/ast"
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 type identifiers struct{
@@ -94,6 +95,7 @@ func TestReturnStatements(t *testing.T) {
 		{"return 5;", 5},
 		{"return true;", true},
 		{"return foobar;", "foobar"},
+		{"return foobar", "foobar"},
 	}
 
 	for _, tt := range tests {
@@ -126,6 +128,33 @@ func TestReturnStatements(t *testing.T) {
 	}
 }
 
+func TestReturnWithoutValue(t *testing.T){
+	for _, input := range []string{"return;", "return", "fn() { return }"}{
+		p := New(lexer.New(input))
+		program := p.ParseProgram()
+		if count := ParserErrorsCount(t, p); count != 0 {
+			t.Fatalf("%q: expected no errors but found %d", input, count)
+		}
+		if len(program.Statements) != 1 {
+			t.Fatalf("%q: expected 1 statement got %d", input, len(program.Statements))
+		}
+	}
+}
+
+func TestFailedStatementsAreDropped(t *testing.T){
+	p := New(lexer.New("let = 5; let x 5; 1 +; a[;"))
+	program := p.ParseProgram()
+	if len(p.Errors()) == 0 {
+		t.Fatalf("expected errors")
+	}
+	for i, stmt := range program.Statements{
+		if ls, ok := stmt.(*ast.LetStatement); ok && ls == nil {
+			t.Errorf("statement %d is a nil *ast.LetStatement", i)
+		}
+	}
+	// printing a program with missing expressions must not panic.
+	_ = program.String()
+}
 
 func TestIdentifierExpression(t *testing.T){
 	input := "foobar;"
@@ -401,6 +430,14 @@ func TestOperatorPrecendenceParsing(t *testing.T){
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1,2][1])))",
 		},
+		{
+			"a + b % c * d",
+			"(a + ((b % c) * d))",
+		},
+		{
+			"-strings.upper(a.b)[0] + c.d",
+			"((-((strings.upper)((a.b))[0])) + (c.d))",
+		},
 	}
 
 	for _, tt := range tests{
@@ -493,7 +530,7 @@ func TestBooleanExpression(t *testing.T){
 		}
 
 		if len(program.Statements) != 1 {
-			t.Errorf("program.Statements is on length %d expected 1")
+			t.Errorf("program.Statements is on length %d expected 1", len(program.Statements))
 		}
 
 		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
@@ -506,7 +543,7 @@ func TestBooleanExpression(t *testing.T){
 		}
 		gotValue, _ := strconv.ParseBool(tc.expected)
 		if  gotValue != exp.Value{
-			t.Errorf("Expected value %s but got= %s", gotValue,exp.Value)
+			t.Errorf("Expected value %t but got= %t", gotValue,exp.Value)
 		}
 	}
 }
@@ -523,7 +560,7 @@ func testBooleanLiterals(t *testing.T, exp ast.Expression, value bool) bool{
 	}
 
 	if bo.TokenLiteral() != fmt.Sprintf("%t", value){
-		t.Errorf("bo.TokenLiteral not %t got: %t",value, bo.TokenLiteral())
+		t.Errorf("bo.TokenLiteral not %t got: %s",value, bo.TokenLiteral())
 		return false
 	}
 	return true
@@ -719,6 +756,79 @@ func TestFunctionParameterParsiing(t *testing.T){
 	}
 }
 
+func TestDefaultAndRestParameters(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"fn(a, b = 10) { a }", "fn(a,b = 10) a"},
+		{"fn(a: int = 1 + 2) { a }", "fn(a: int = (1 + 2)) a"},
+		{"fn(first, ...rest) { rest }", "fn(first,...rest) rest"},
+		{"fn(a = 1, ...rest: [int]) { rest }", "fn(a = 1,...rest: [int]) rest"},
+		{"f(...xs, 1)", "f(...xs, 1)"},
+	}
+	for _, tt := range tests{
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		if count := ParserErrorsCount(t, p); count != 0 {
+			t.Fatalf("%q: expected no errors but found %d", tt.input, count)
+		}
+		if program.String() != tt.expected {
+			t.Errorf("%q: expected %q got %q", tt.input, tt.expected, program.String())
+		}
+	}
+}
+
+func TestFunctionStatement(t *testing.T){
+	input := "fn add(a, b: int): int { a + b } fn(x) { x };"
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	if count := ParserErrorsCount(t, p); count != 0 {
+		t.Fatalf("expected no errors but found %d", count)
+	}
+	if len(program.Statements) != 2 {
+		t.Fatalf("expected 2 statements got %d", len(program.Statements))
+	}
+	stmt, ok := program.Statements[0].(*ast.FunctionStatement)
+	if !ok {
+		t.Fatalf("statement is not *ast.FunctionStatement got %T", program.Statements[0])
+	}
+	if stmt.Name.Value != "add" || stmt.Function.Name != "add" {
+		t.Errorf("expected the function to be named add got %q and %q", stmt.Name.Value, stmt.Function.Name)
+	}
+	if stmt.String() != "fn add(a,b: int): int (a + b)" {
+		t.Errorf("wrong string got %q", stmt.String())
+	}
+	if _, ok := program.Statements[1].(*ast.ExpressionStatement); !ok {
+		t.Errorf("an anonymous function is an expression statement got %T", program.Statements[1])
+	}
+
+	let := New(lexer.New("let inc = fn(x) { x + 1 };")).ParseProgram().Statements[0].(*ast.LetStatement)
+	if fl := let.Value.(*ast.FunctionLiteral); fl.Name != "inc" {
+		t.Errorf("a function bound by a let is named after it, got %q", fl.Name)
+	}
+}
+
+func TestParameterErrors(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"fn(...rest, a) {}", "a rest parameter must be the last parameter"},
+		{"fn(...rest = 1) {}", "a rest parameter cannot have a default value"},
+		{"fn(a = 1, b) {}", "parameter b without a default value follows one with a default"},
+		{"fn(1) {}", "expected next token to be IDENT got: INT"},
+		{"[...xs]", "no prefix parse function for ... found"},
+	}
+	for _, tt := range tests{
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 || !strings.HasPrefix(p.Errors()[0], tt.expected) {
+			t.Errorf("%q: expected error %q got %v", tt.input, tt.expected, p.Errors())
+		}
+	}
+}
+
 func TestCallExpressionParsing(t *testing.T){
 	input := `add(1, 2 * 3, 4 + 5);`
 
@@ -827,6 +937,55 @@ func TestParsingIndexExpression(t *testing.T){
 	}
 }
 
+func TestNumberLiterals(t *testing.T){
+	p := New(lexer.New("123456789012345678901234567890n; 12.050;"))
+	program := p.ParseProgram()
+	if count := ParserErrorsCount(t,p); count != 0 {
+		t.Fatalf("the error count must be 0 got %d", count)
+	}
+
+	big, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.BigIntLiteral)
+	if !ok {
+		t.Fatalf("expected *ast.BigIntLiteral got %T", program.Statements[0].(*ast.ExpressionStatement).Expression)
+	}
+	if big.Value.String() != "123456789012345678901234567890" || big.String() != "123456789012345678901234567890n" {
+		t.Errorf("wrong big integer %s written as %s", big.Value, big.String())
+	}
+
+	dec, ok := program.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.DecimalLiteral)
+	if !ok {
+		t.Fatalf("expected *ast.DecimalLiteral got %T", program.Statements[1].(*ast.ExpressionStatement).Expression)
+	}
+	if dec.Unscaled.Int64() != 12050 || dec.Scale != 3 || dec.String() != "12.050" {
+		t.Errorf("wrong decimal %s with scale %d written as %s", dec.Unscaled, dec.Scale, dec.String())
+	}
+}
+
+func TestParsingMemberExpression(t *testing.T){
+	p := New(lexer.New("strings.upper"))
+	program := p.ParseProgram()
+	if count := ParserErrorsCount(t,p); count != 0 {
+		t.Fatalf("the error count must be 0 got %d", count)
+	}
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	member, ok := stmt.Expression.(*ast.MemberExpression)
+	if !ok {
+		t.Fatalf("expected the expression to be *ast.MemberExpression but got %T", stmt.Expression)
+	}
+	if !testIdentifier(t, member.Object, "strings"){
+		return
+	}
+	testIdentifier(t, member.Property, "upper")
+
+	p = New(lexer.New("a.1"))
+	p.ParseProgram()
+	diags := p.Diagnostics()
+	if len(diags) == 0 || diags[0].String() != "1:3: error: expected next token to be IDENT got: INT (syntax)" {
+		t.Errorf("expected an error for a member that is not an identifier, got %v", diags)
+	}
+}
+
 func TestParsingHashLiteralStringKeys(t *testing.T){
 	input := `{"one": 1, "two": 2, "three": 3}`
 	l := lexer.New(input)
@@ -925,3 +1084,51 @@ func TestParsingHashLiteralWithExpression(t *testing.T){
 		testFunc(value)
 	}
 }
+
+func TestTypeAnnotations(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"let x: int = 5;", "let x: int = 5;"},
+		{"let xs: [string] = [];", "let xs: [string] = [];"},
+		{"let h: {string: [int]} = {};", "let h: {string: [int]} = {};"},
+		{"let f = fn(a: string, b: [int]): bool { true };", "let f = fn(a: string,b: [int]): bool true;"},
+		{"let f = fn(a, b: int) { a };", "let f = fn(a,b: int) a;"},
+		{"let g: fn(int, int): int = fn(a, b) { a + b };", "let g: fn(int, int): int = fn(a,b) (a + b);"},
+		{"let h = fn(f: fn(): any) { f() };", "let h = fn(f: fn(): any) f();"},
+	}
+
+	for _, tt := range tests{
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		if ParserErrorsCount(t, p) != 0 {
+			t.Fatalf("%q: unexpected parser errors", tt.input)
+		}
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestTypeAnnotationErrors(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"let x: = 5;", "1:8: error: expected a type got: = (syntax)"},
+		{"let x: [int = 5;", "1:13: error: expected next token to be ] got: = (syntax)"},
+	}
+
+	for _, tt := range tests{
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		diags := p.Diagnostics()
+		if len(diags) == 0 {
+			t.Fatalf("%q: expected parser errors", tt.input)
+		}
+		if diags[0].String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, diags[0].String())
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package profiler
+
+import (
+	"compress/gzip"
+	"io"
+	"sort"
+)
+
+/*
+	WritePprof writes the profile as a gzipped profile.proto, the format read by go tool pprof. Each sample
+	is a call stack with two values, the statements it ran and the nanoseconds spent in it. The program
+	itself is called main.
+*/
+func (p *Profiler) WritePprof(w io.Writer) error {
+	strs := &stringTable{index: map[string]int64{"": 0}, list: []string{""}}
+	var prof buffer
+
+	for _, vt := range [][2]string{{"hits", "count"}, {"time", "nanoseconds"}} {
+		var b buffer
+		b.int(1, strs.get(vt[0]))
+		b.int(2, strs.get(vt[1]))
+		prof.message(1, b)
+	}
+
+	keys := make([]string, 0, len(p.samples))
+	for k := range p.samples {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	funcIDs := make(map[string]uint64)
+	locIDs := make(map[location]uint64)
+	var funcs, locs []buffer
+	locationID := func(l location) uint64 {
+		if id, ok := locIDs[l]; ok {
+			return id
+		}
+		fid, ok := funcIDs[l.fn]
+		if !ok {
+			fid = uint64(len(funcIDs) + 1)
+			funcIDs[l.fn] = fid
+			// pprof drops anything between < and > from names as if they were template arguments.
+			name := l.fn
+			if name == programName {
+				name = "main"
+			}
+			var f buffer
+			f.uint(1, fid)
+			f.int(2, strs.get(name))
+			f.int(3, strs.get(name))
+			f.int(4, strs.get(p.Filename))
+			funcs = append(funcs, f)
+		}
+		id := uint64(len(locIDs) + 1)
+		locIDs[l] = id
+		var line, loc buffer
+		line.uint(1, fid)
+		line.int(2, int64(l.line))
+		loc.uint(1, id)
+		loc.message(4, line)
+		locs = append(locs, loc)
+		return id
+	}
+
+	for _, k := range keys {
+		s := p.samples[k]
+		if s.hits == 0 && s.time == 0 {
+			continue
+		}
+		var ids []uint64
+		for _, l := range s.stack {
+			ids = append(ids, locationID(l))
+		}
+		var b buffer
+		b.packedUints(1, ids)
+		b.packedInts(2, []int64{s.hits, int64(s.time)})
+		prof.message(2, b)
+	}
+	for _, l := range locs {
+		prof.message(4, l)
+	}
+	for _, f := range funcs {
+		prof.message(5, f)
+	}
+	for _, s := range strs.list {
+		prof.bytes(6, []byte(s))
+	}
+	if p.started {
+		prof.int(9, p.start.UnixNano())
+		prof.int(10, int64(p.last.Sub(p.start)))
+	}
+
+	gz := gzip.NewWriter(w)
+	if _, err := gz.Write(prof); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+type stringTable struct {
+	index map[string]int64
+	list  []string
+}
+
+func (t *stringTable) get(s string) int64 {
+	if i, ok := t.index[s]; ok {
+		return i
+	}
+	i := int64(len(t.list))
+	t.index[s] = i
+	t.list = append(t.list, s)
+	return i
+}
+
+// buffer is an encoded protocol buffer message, only the wire types profile.proto needs are supported.
+type buffer []byte
+
+func (b *buffer) varint(v uint64) {
+	for v >= 0x80 {
+		*b = append(*b, byte(v)|0x80)
+		v >>= 7
+	}
+	*b = append(*b, byte(v))
+}
+
+func (b *buffer) key(field int, wireType uint64) {
+	b.varint(uint64(field)<<3 | wireType)
+}
+
+func (b *buffer) uint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	b.key(field, 0)
+	b.varint(v)
+}
+
+func (b *buffer) int(field int, v int64) {
+	b.uint(field, uint64(v))
+}
+
+func (b *buffer) bytes(field int, v []byte) {
+	b.key(field, 2)
+	b.varint(uint64(len(v)))
+	*b = append(*b, v...)
+}
+
+func (b *buffer) message(field int, m buffer) {
+	b.bytes(field, m)
+}
+
+func (b *buffer) packedUints(field int, vs []uint64) {
+	var p buffer
+	for _, v := range vs {
+		p.varint(v)
+	}
+	b.bytes(field, p)
+}
+
+func (b *buffer) packedInts(field int, vs []int64) {
+	var p buffer
+	for _, v := range vs {
+		p.varint(uint64(v))
+	}
+	b.bytes(field, p)
+}
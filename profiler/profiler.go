@@ -0,0 +1,234 @@
+/*
+	Package profiler measures where a program spends its time. As the hook of an evaluation it counts the
+	calls of every function and the statements run on every line, and charges the time between two events
+	of the evaluator to the function and line that were running. The results can be read as tables or
+	written in the pprof format for go tool pprof.
+
+	Functions are told apart by the name CallName gives them, the program itself is <program>.
+*/
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/evaluator"
+	"go-interpreter-lexer/object"
+)
+
+const programName = "<program>"
+
+type FuncStats struct {
+	Name  string
+	Calls int
+	// Total is the time from the call to the return, Self leaves out the time spent in functions it called.
+	Total time.Duration
+	Self  time.Duration
+}
+
+type LineStats struct {
+	Line int
+	// Hits is the number of statements on the line that were run.
+	Hits int
+	Self time.Duration
+}
+
+type frame struct {
+	fn    *FuncStats
+	line  int
+	start time.Time
+}
+
+// location is a line of a function, the line is 0 for builtins.
+type location struct {
+	fn   string
+	line int
+}
+
+// sample is the time spent and the statements run with a particular call stack.
+type sample struct {
+	stack []location
+	hits  int64
+	time  time.Duration
+}
+
+type Profiler struct {
+	// Now is the clock time is measured with, time.Now unless a test replaces it.
+	Now func() time.Time
+	// Filename is reported as the file of every function in pprof output.
+	Filename string
+
+	started bool
+	last    time.Time
+	start   time.Time
+	frames  []*frame
+	active  map[string]int
+	funcs   map[string]*FuncStats
+	lines   map[int]*LineStats
+	samples map[string]*sample
+}
+
+func New() *Profiler {
+	return &Profiler{
+		Now:     time.Now,
+		active:  make(map[string]int),
+		funcs:   make(map[string]*FuncStats),
+		lines:   make(map[int]*LineStats),
+		samples: make(map[string]*sample),
+	}
+}
+
+// tick charges the time since the last event to what was running and returns the current time.
+func (p *Profiler) tick() time.Time {
+	now := p.Now()
+	if !p.started {
+		p.started = true
+		p.start, p.last = now, now
+		program := p.function(programName)
+		program.Calls = 1
+		p.frames = []*frame{{fn: program, start: now}}
+		return now
+	}
+	elapsed := now.Sub(p.last)
+	p.last = now
+	top := p.frames[len(p.frames)-1]
+	top.fn.Self += elapsed
+	if top.line > 0 {
+		p.lineStats(top.line).Self += elapsed
+	}
+	p.sample().time += elapsed
+	return now
+}
+
+func (p *Profiler) function(name string) *FuncStats {
+	f, ok := p.funcs[name]
+	if !ok {
+		f = &FuncStats{Name: name}
+		p.funcs[name] = f
+	}
+	return f
+}
+
+func (p *Profiler) lineStats(line int) *LineStats {
+	l, ok := p.lines[line]
+	if !ok {
+		l = &LineStats{Line: line}
+		p.lines[line] = l
+	}
+	return l
+}
+
+// sample returns the sample of the current call stack.
+func (p *Profiler) sample() *sample {
+	var key strings.Builder
+	for _, f := range p.frames {
+		key.WriteString(f.fn.Name + ":" + strconv.Itoa(f.line) + ";")
+	}
+	s, ok := p.samples[key.String()]
+	if !ok {
+		s = &sample{}
+		// pprof lists the innermost location first.
+		for i := len(p.frames) - 1; i >= 0; i-- {
+			s.stack = append(s.stack, location{fn: p.frames[i].fn.Name, line: p.frames[i].line})
+		}
+		p.samples[key.String()] = s
+	}
+	return s
+}
+
+// Eval implements evaluator.Hook, it counts the statements run on each line.
+func (p *Profiler) Eval(node ast.Node, env *object.Environment) {
+	p.tick()
+	stmt, ok := node.(ast.Statement)
+	if !ok {
+		return
+	}
+	if _, ok := stmt.(*ast.BlockStatement); ok {
+		return
+	}
+	pos := ast.Pos(stmt)
+	if !pos.IsValid() {
+		return
+	}
+	p.frames[len(p.frames)-1].line = pos.Line
+	p.lineStats(pos.Line).Hits++
+	p.sample().hits++
+}
+
+func (p *Profiler) Call(call *ast.CallExpression, fn object.Object, args []object.Object) {
+	now := p.tick()
+	f := p.function(evaluator.CallName(call, fn))
+	f.Calls++
+	p.active[f.Name]++
+	p.frames = append(p.frames, &frame{fn: f, start: now})
+}
+
+func (p *Profiler) Return(call *ast.CallExpression, fn object.Object, result object.Object) {
+	now := p.tick()
+	if len(p.frames) < 2 {
+		return
+	}
+	top := p.frames[len(p.frames)-1]
+	p.frames = p.frames[:len(p.frames)-1]
+	// the outermost of recursive calls covers the time of the others.
+	p.active[top.fn.Name]--
+	if p.active[top.fn.Name] == 0 {
+		top.fn.Total += now.Sub(top.start)
+	}
+}
+
+// Stop charges the time since the last event, call it once the program has finished.
+func (p *Profiler) Stop() {
+	if !p.started {
+		return
+	}
+	now := p.tick()
+	p.function(programName).Total = now.Sub(p.start)
+}
+
+// Functions returns the statistics of every function called, the most expensive in self time first.
+func (p *Profiler) Functions() []FuncStats {
+	funcs := make([]FuncStats, 0, len(p.funcs))
+	for _, f := range p.funcs {
+		funcs = append(funcs, *f)
+	}
+	sort.Slice(funcs, func(i, j int) bool {
+		if funcs[i].Self != funcs[j].Self {
+			return funcs[i].Self > funcs[j].Self
+		}
+		return funcs[i].Name < funcs[j].Name
+	})
+	return funcs
+}
+
+// Lines returns the statistics of every line that ran a statement, in line order.
+func (p *Profiler) Lines() []LineStats {
+	lines := make([]LineStats, 0, len(p.lines))
+	for _, l := range p.lines {
+		lines = append(lines, *l)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].Line < lines[j].Line
+	})
+	return lines
+}
+
+// WriteReport writes the function and line statistics as two tables.
+func (p *Profiler) WriteReport(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%8s %12s %12s  %s\n", "calls", "total", "self", "function")
+	for _, f := range p.Functions() {
+		fmt.Fprintf(&b, "%8d %12s %12s  %s\n", f.Calls, f.Total, f.Self, f.Name)
+	}
+	fmt.Fprintf(&b, "\n%8s %12s %12s\n", "line", "hits", "self")
+	for _, l := range p.Lines() {
+		fmt.Fprintf(&b, "%8d %12d %12s\n", l.Line, l.Hits, l.Self)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
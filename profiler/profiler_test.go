@@ -0,0 +1,98 @@
+package profiler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"go-interpreter-lexer/evaluator"
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/parser"
+)
+
+const input = `let fib = fn(n) {
+  if (n < 2) { return n; }
+  fib(n - 1) + fib(n - 2)
+};
+fib(4);`
+
+// profile runs input under a profiler whose clock moves a millisecond every time it is read.
+func profile(t *testing.T) *Profiler {
+	p := New()
+	p.Filename = "fib.mk"
+	clock := time.Unix(0, 0)
+	p.Now = func() time.Time {
+		clock = clock.Add(time.Millisecond)
+		return clock
+	}
+	program := parser.New(lexer.New(input)).ParseProgram()
+	evaluator.Eval(program, evaluator.NewEnvironment(evaluator.Config{Hook: p}))
+	p.Stop()
+	return p
+}
+
+func TestFunctions(t *testing.T) {
+	p := profile(t)
+	funcs := p.Functions()
+	if len(funcs) != 2 || funcs[0].Name != "fib" || funcs[1].Name != "<program>" {
+		t.Fatalf("wrong functions %+v", funcs)
+	}
+	fib, program := funcs[0], funcs[1]
+	if fib.Calls != 9 || program.Calls != 1 {
+		t.Errorf("wrong calls fib=%d program=%d", fib.Calls, program.Calls)
+	}
+	if program.Total != fib.Self+program.Self {
+		t.Errorf("the self times should add up to the total, %s != %s + %s", program.Total, fib.Self, program.Self)
+	}
+	if fib.Total >= program.Total || fib.Total < fib.Self {
+		t.Errorf("fib total %s should be between its self time %s and the program total %s", fib.Total, fib.Self, program.Total)
+	}
+}
+
+func TestLines(t *testing.T) {
+	var got []string
+	var total time.Duration
+	for _, l := range profile(t).Lines() {
+		got = append(got, fmt.Sprintf("%d:%d", l.Line, l.Hits))
+		total += l.Self
+	}
+	if strings.Join(got, " ") != "1:1 2:14 3:4 5:1" {
+		t.Errorf("wrong line hits %v", got)
+	}
+	if total == 0 {
+		t.Errorf("no time charged to lines")
+	}
+}
+
+func TestWritePprof(t *testing.T) {
+	var buf bytes.Buffer
+	if err := profile(t).WritePprof(&buf); err != nil {
+		t.Fatal(err)
+	}
+	r, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("profile is not gzipped: %v", err)
+	}
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"hits", "nanoseconds", "fib", "main", "fib.mk"} {
+		if !bytes.Contains(raw, []byte(s)) {
+			t.Errorf("string table is missing %q", s)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	var buf bytes.Buffer
+	profile(t).WriteReport(&buf)
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.Contains(lines[0], "calls") || !strings.HasSuffix(lines[1], "fib") || !strings.HasSuffix(lines[2], "<program>") {
+		t.Errorf("wrong report\n%s", buf.String())
+	}
+}
@@ -4,10 +4,10 @@ import (
 	"io"
 	"bufio"
 	"fmt"
+	"strings"
 	"go-interpreter-lexer/lexer"
 	"go-interpreter-lexer/parser"
 	"go-interpreter-lexer/evaluator"
-	"go-interpreter-lexer/object"
 )
 
 const PROMPT ="$> "
@@ -26,16 +26,17 @@ const MONKEY_FACE = `            __,__
 `
 
 func Start(in io.Reader, out io.Writer){
-	scanner := bufio.NewScanner(in)
-	env := object.NewEnvironment()
+	// programs read io.readLine from the same reader as the lines typed, so neither loses what the other buffered.
+	reader := bufio.NewReader(in)
+	env := evaluator.NewEnvironment(evaluator.Config{IO: evaluator.IO{Stdout: out, Stdin: reader}})
 
 	for{
 		fmt.Printf(PROMPT)
-		scanned := scanner.Scan()
-		if !scanned{
+		line, err := reader.ReadString('\n')
+		if err != nil && line == ""{
 			return
 		}
-		line := scanner.Text()
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
 		l := lexer.New(line)
 		p := parser.New(l)
 
@@ -0,0 +1,17 @@
+package repl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStartWritesToOut(t *testing.T) {
+	in := strings.NewReader("puts(\"hi\"); io.print(1, 2)\nlet name = io.readLine()\nAda\nname\n")
+	var out bytes.Buffer
+	Start(in, &out)
+
+	if got, want := out.String(), "hi\n12null\nAda\n"; got != want {
+		t.Errorf("wrong output. got %q, want %q", got, want)
+	}
+}
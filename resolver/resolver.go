@@ -0,0 +1,316 @@
+/*
+	Package resolver runs over a parsed program before it is evaluated and works out which binding every
+	identifier refers to. It reports identifiers that are not declared anywhere and declarations that shadow
+	a binding from an enclosing scope, and annotates each ast.Identifier with the depth and slot of its binding.
+
+	Scopes follow the evaluator: the program and every function literal get a scope, blocks do not. A let
+	anywhere in a function body declares the name for the whole function, but a reference in the same
+	function only sees it once the let has been passed. References from nested functions see every binding
+	of the enclosing scopes since those functions can only run after they have been created. Functions
+	declared with fn name() at the top level of the program or of a function body are visible in the whole
+	scope, declarations nested in blocks behave like a let.
+*/
+package resolver
+
+import (
+	"fmt"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/diagnostic"
+)
+
+type Kind int
+
+const (
+	Let Kind = iota
+	Param
+	Global
+	Builtin
+	Function
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Let:
+		return "let"
+	case Param:
+		return "parameter"
+	case Global:
+		return "global"
+	case Function:
+		return "function"
+	default:
+		return "builtin"
+	}
+}
+
+// Binding is a name declared in a scope along with every identifier that refers to it.
+type Binding struct {
+	Name string
+	Kind Kind
+	// Decls are the identifiers that declare the binding, a name can be declared by more than one let in
+	// the same scope. Globals and builtins have no declarations.
+	Decls []*ast.Identifier
+	Uses  []*ast.Identifier
+	// Scope is nil for builtins.
+	Scope *Scope
+	Slot  int
+}
+
+// Decl returns the first declaration of the binding or nil.
+func (b *Binding) Decl() *ast.Identifier {
+	if len(b.Decls) == 0 {
+		return nil
+	}
+	return b.Decls[0]
+}
+
+/*
+	Scope holds the bindings of the program or of a function literal. Node is the *ast.Program or
+	*ast.FunctionLiteral the scope belongs to.
+*/
+type Scope struct {
+	Parent   *Scope
+	Children []*Scope
+	Node     ast.Node
+	Bindings []*Binding
+
+	names   map[string]*Binding
+	defined map[*Binding]bool
+}
+
+func newScope(parent *Scope, node ast.Node) *Scope {
+	s := &Scope{
+		Parent:  parent,
+		Node:    node,
+		names:   make(map[string]*Binding),
+		defined: make(map[*Binding]bool),
+	}
+	if parent != nil {
+		parent.Children = append(parent.Children, s)
+	}
+	return s
+}
+
+// Lookup finds the binding for name in s or its enclosing scopes regardless of where it is declared.
+func (s *Scope) Lookup(name string) *Binding {
+	for sc := s; sc != nil; sc = sc.Parent {
+		if b, ok := sc.names[name]; ok {
+			return b
+		}
+	}
+	return nil
+}
+
+type Resolver struct {
+	builtins    map[string]*Binding
+	globals     []string
+	global      *Scope
+	refs        map[*ast.Identifier]*Binding
+	diagnostics []diagnostic.Diagnostic
+}
+
+// New returns a resolver that knows about the given builtin functions, e.g. evaluator.BuiltinNames().
+func New(builtins []string) *Resolver {
+	r := &Resolver{builtins: make(map[string]*Binding)}
+	for _, name := range builtins {
+		r.builtins[name] = &Binding{Name: name, Kind: Builtin, Slot: -1}
+	}
+	return r
+}
+
+// DefineGlobal declares a name the host makes available in the global environment before the program runs.
+func (r *Resolver) DefineGlobal(name string) {
+	r.globals = append(r.globals, name)
+}
+
+/*
+	Resolve resolves every identifier in program, annotating them in place. Diagnostics returns what was
+	found, undefined identifiers are errors and shadowing declarations warnings.
+*/
+func (r *Resolver) Resolve(program *ast.Program) {
+	r.refs = make(map[*ast.Identifier]*Binding)
+	r.diagnostics = nil
+	for _, b := range r.builtins {
+		b.Uses = nil
+	}
+
+	r.global = newScope(nil, program)
+	for _, name := range r.globals {
+		b := r.declare(r.global, name, Global, nil)
+		r.global.defined[b] = true
+	}
+	r.hoist(r.global, program)
+	r.defineFunctions(r.global, program.Statements)
+	r.walk(r.global, program)
+
+	diagnostic.Sort(r.diagnostics)
+}
+
+func (r *Resolver) Diagnostics() []diagnostic.Diagnostic {
+	return r.diagnostics
+}
+
+// Global returns the scope of the program resolved last, its Children are the scopes of function literals.
+func (r *Resolver) Global() *Scope {
+	return r.global
+}
+
+// Binding returns the binding an identifier refers to or declares, nil if it could not be resolved.
+func (r *Resolver) Binding(ident *ast.Identifier) *Binding {
+	return r.refs[ident]
+}
+
+// Builtin returns the binding of a builtin function, its Uses are filled in by Resolve.
+func (r *Resolver) Builtin(name string) *Binding {
+	return r.builtins[name]
+}
+
+func (r *Resolver) declare(s *Scope, name string, kind Kind, decl *ast.Identifier) *Binding {
+	if b, ok := s.names[name]; ok {
+		if decl != nil {
+			b.Decls = append(b.Decls, decl)
+			r.annotate(decl, b, 0)
+		}
+		return b
+	}
+
+	if s.Parent != nil && decl != nil {
+		if outer := s.Parent.Lookup(name); outer != nil {
+			r.shadowed(decl, outer)
+		}
+	}
+
+	b := &Binding{Name: name, Kind: kind, Scope: s, Slot: len(s.Bindings)}
+	s.Bindings = append(s.Bindings, b)
+	s.names[name] = b
+	if decl != nil {
+		b.Decls = append(b.Decls, decl)
+		r.annotate(decl, b, 0)
+	}
+	return b
+}
+
+func (r *Resolver) shadowed(decl *ast.Identifier, outer *Binding) {
+	msg := fmt.Sprintf("declaration of %s shadows the %s %s", decl.Value, outer.Kind, outer.Name)
+	if outer.Decl() != nil {
+		msg = fmt.Sprintf("declaration of %s shadows the %s declared at %s", decl.Value, outer.Kind, outer.Decl().Token.Pos)
+	}
+	r.diagnostics = append(r.diagnostics, diagnostic.Diagnostic{
+		Pos:      decl.Token.Pos,
+		Severity: diagnostic.Warning,
+		Code:     "shadowed",
+		Message:  msg,
+	})
+}
+
+func (r *Resolver) annotate(ident *ast.Identifier, b *Binding, depth int) {
+	ident.Depth = depth
+	r.refs[ident] = b
+}
+
+// hoist declares every let in node that belongs to scope s, lets inside nested functions belong to those.
+func (r *Resolver) hoist(s *Scope, node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FunctionLiteral:
+			return false
+		case *ast.LetStatement:
+			if n.Name != nil {
+				r.declare(s, n.Name.Value, Let, n.Name)
+			}
+		case *ast.FunctionStatement:
+			if n.Name != nil {
+				r.declare(s, n.Name.Value, Function, n.Name)
+			}
+		}
+		return true
+	})
+}
+
+// defineFunctions makes the functions declared by stmts visible before the statements are walked.
+func (r *Resolver) defineFunctions(s *Scope, stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		if fs, ok := stmt.(*ast.FunctionStatement); ok && fs.Name != nil {
+			s.defined[s.names[fs.Name.Value]] = true
+		}
+	}
+}
+
+func (r *Resolver) walk(s *Scope, node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.LetStatement:
+			r.walk(s, n.Value)
+			if n.Name != nil {
+				s.defined[s.names[n.Name.Value]] = true
+			}
+			return false
+		case *ast.FunctionStatement:
+			if n.Name != nil {
+				s.defined[s.names[n.Name.Value]] = true
+			}
+			if n.Function != nil {
+				r.function(s, n.Function)
+			}
+			return false
+		case *ast.FunctionLiteral:
+			r.function(s, n)
+			return false
+		case *ast.MemberExpression:
+			// the property names a member, it is not a reference.
+			r.walk(s, n.Object)
+			return false
+		case *ast.Identifier:
+			r.reference(s, n)
+		}
+		return true
+	})
+}
+
+func (r *Resolver) function(parent *Scope, fl *ast.FunctionLiteral) {
+	s := newScope(parent, fl)
+	for i, p := range fl.Parameters {
+		if p == nil {
+			continue
+		}
+		// a default value is evaluated in the function and sees the parameters before it.
+		if i < len(fl.Defaults) && fl.Defaults[i] != nil {
+			r.walk(s, fl.Defaults[i])
+		}
+		b := r.declare(s, p.Value, Param, p)
+		s.defined[b] = true
+	}
+	if fl.Body == nil {
+		return
+	}
+	r.hoist(s, fl.Body)
+	r.defineFunctions(s, fl.Body.Statements)
+	r.walk(s, fl.Body)
+}
+
+func (r *Resolver) reference(s *Scope, ident *ast.Identifier) {
+	depth := 0
+	for sc := s; sc != nil; sc = sc.Parent {
+		// a binding of the current function is only visible once its let has run.
+		if b, ok := sc.names[ident.Value]; ok && (sc != s || sc.defined[b]) {
+			b.Uses = append(b.Uses, ident)
+			r.annotate(ident, b, depth)
+			return
+		}
+		depth++
+	}
+
+	ident.Depth = -1
+	if b, ok := r.builtins[ident.Value]; ok {
+		b.Uses = append(b.Uses, ident)
+		r.refs[ident] = b
+		return
+	}
+	r.diagnostics = append(r.diagnostics, diagnostic.Diagnostic{
+		Pos:      ident.Token.Pos,
+		Severity: diagnostic.Error,
+		Code:     "undefined",
+		Message:  "identifier not found: " + ident.Value,
+	})
+}
@@ -0,0 +1,122 @@
+package resolver
+
+import (
+	"testing"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/diagnostic"
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/parser"
+)
+
+func resolve(t *testing.T, input string, globals ...string) (*ast.Program, *Resolver) {
+	t.Helper()
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+	r := New([]string{"len", "puts"})
+	for _, g := range globals {
+		r.DefineGlobal(g)
+	}
+	r.Resolve(program)
+	return program, r
+}
+
+func TestDiagnostics(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"let a = 1; a;", nil},
+		{"len([1]); puts(1);", nil},
+		{"foo;", []string{"1:1: error: identifier not found: foo (undefined)"}},
+		{"let a = fn(x) { x + y };", []string{"1:21: error: identifier not found: y (undefined)"}},
+		{"a; let a = 1;", []string{"1:1: error: identifier not found: a (undefined)"}},
+		{"let f = fn() { f() };", nil},
+		{"let f = fn() { g() }; let g = fn() { 1 };", nil},
+		{"let x = 1; let f = fn(x) { x };", []string{"1:23: warning: declaration of x shadows the let declared at 1:5 (shadowed)"}},
+		{"let f = fn() {\n let host = 2;\n host };", []string{"2:6: warning: declaration of host shadows the global host (shadowed)"}},
+		{"let f = fn() { let len = 1; len };", nil},
+		{"f(); fn f() { 1 }", nil},
+		{"fn even(n) { odd(n) } fn odd(n) { even(n) }", nil},
+		{"fn f() { g(); if (true) { fn g() { 1 } } }", []string{"1:10: error: identifier not found: g (undefined)"}},
+		{"fn x() { 1 } let f = fn() { fn x() { 2 } };", []string{"1:32: warning: declaration of x shadows the function declared at 1:4 (shadowed)"}},
+	}
+
+	for _, tt := range tests {
+		_, r := resolve(t, tt.input, "host")
+		diags := r.Diagnostics()
+		if len(diags) != len(tt.expected) {
+			t.Errorf("%q: expected %d diagnostics got %v", tt.input, len(tt.expected), diags)
+			continue
+		}
+		for i, d := range diags {
+			if d.String() != tt.expected[i] {
+				t.Errorf("%q: wrong diagnostic. expected=%q, got=%q", tt.input, tt.expected[i], d.String())
+			}
+		}
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	input := `
+	let a = 1;
+	let b = fn(x, y) {
+		let c = a;
+		fn(z) { x + c + z + a + len("") }
+	};`
+	program, r := resolve(t, input)
+	if diagnostic.HasErrors(r.Diagnostics()) {
+		t.Fatalf("unexpected diagnostics %v", r.Diagnostics())
+	}
+
+	expected := map[string][2]int{}
+	ast.Inspect(program, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Identifier); ok {
+			key := ident.Value + "@" + ident.Token.Pos.String()
+			slot := 0
+			if b := r.Binding(ident); b != nil {
+				slot = b.Slot
+			}
+			expected[key] = [2]int{ident.Depth, slot}
+		}
+		return true
+	})
+
+	tests := []struct {
+		key   string
+		depth int
+		slot  int
+	}{
+		{"a@2:6", 0, 0},
+		{"b@3:6", 0, 1},
+		{"x@3:13", 0, 0},
+		{"c@4:7", 0, 2},
+		{"a@4:11", 1, 0},
+		{"x@5:11", 1, 0},
+		{"c@5:15", 1, 2},
+		{"z@5:19", 0, 0},
+		{"a@5:23", 2, 0},
+		{"len@5:27", -1, -1},
+	}
+	for _, tt := range tests {
+		got, ok := expected[tt.key]
+		if !ok {
+			t.Errorf("identifier %s not found in %v", tt.key, expected)
+			continue
+		}
+		if got[0] != tt.depth || got[1] != tt.slot {
+			t.Errorf("%s: expected depth=%d slot=%d got depth=%d slot=%d", tt.key, tt.depth, tt.slot, got[0], got[1])
+		}
+	}
+
+	a := r.Global().Bindings[0]
+	if a.Name != "a" || len(a.Uses) != 2 {
+		t.Errorf("expected binding a with 2 uses got %s with %d", a.Name, len(a.Uses))
+	}
+	if len(r.Builtin("len").Uses) != 1 {
+		t.Errorf("expected len to be used once got %d", len(r.Builtin("len").Uses))
+	}
+}
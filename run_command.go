@@ -0,0 +1,220 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go-interpreter-lexer/coverage"
+	"go-interpreter-lexer/diagnostic"
+	"go-interpreter-lexer/evaluator"
+	"go-interpreter-lexer/object"
+	"go-interpreter-lexer/optimizer"
+	"go-interpreter-lexer/profiler"
+	"go-interpreter-lexer/resolver"
+	"go-interpreter-lexer/tracer"
+)
+
+/*
+	runCommand evaluates a source file, monkey run file.mk. Identifiers are resolved before the program
+	runs so a misspelt name is reported without executing anything. The run can be traced, profiled and its
+	coverage recorded for monkey cover. The arguments after the file are what args() returns, and exit(code)
+	ends the run with that status once coverage and profiles are written.
+*/
+func runCommand(args []string) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	optimize := fs.Bool("optimize", false, "fold constant expressions before running")
+	trace := fs.Bool("trace", false, "write every call and return to stderr")
+	profile := fs.String("profile", "", "write a pprof profile of the run to `file`")
+	report := fs.Bool("profile-report", false, "write the time spent in each function and line to stderr")
+	cover := fs.String("coverage", "", "write the statements, branches and functions that ran to `file`")
+	host := addConfigFlags(fs)
+	allow := fs.String("allow", "", "let the program use `builtins`, a comma separated list of args, env, exit and exec or all")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "usage: monkey run [--optimize] [--trace] [--profile=file] [--profile-report] [--coverage=file] [--seed=n] [--now=t] [--root=dir|--no-files] [--allow=args,env,exit,exec] file.mk [args...]")
+		return 2
+	}
+	capabilities, err := parseCapabilities(*allow)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "--allow:", err)
+		return 2
+	}
+	cfg, err := host.config()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	path := fs.Arg(0)
+
+	program, diags, err := parseFile(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(diags) != 0 {
+		printDiagnostics(os.Stderr, path, diags)
+		return 1
+	}
+
+	r := resolver.New(evaluator.BuiltinNames())
+	r.Resolve(program)
+	printDiagnostics(os.Stderr, path, r.Diagnostics())
+	if diagnostic.HasErrors(r.Diagnostics()) {
+		return 1
+	}
+
+	if *optimize {
+		optimizer.Optimize(program)
+	}
+	cfg.Process = evaluator.Process{Args: fs.Args()[1:], Allow: capabilities}
+	var hooks []evaluator.Hook
+	if *trace {
+		hooks = append(hooks, tracer.New(os.Stderr))
+	}
+	var prof *profiler.Profiler
+	if *profile != "" || *report {
+		prof = profiler.New()
+		prof.Filename = path
+		hooks = append(hooks, prof)
+	}
+	var collector *coverage.Collector
+	if *cover != "" {
+		collector = coverage.NewCollector()
+		collector.Add(path, program)
+		hooks = append(hooks, collector)
+	}
+	if len(hooks) != 0 {
+		cfg.Hook = evaluator.MultiHook(hooks...)
+	}
+	result := evaluator.Eval(program, evaluator.NewEnvironment(cfg))
+	if prof != nil {
+		prof.Stop()
+		if *report {
+			prof.WriteReport(os.Stderr)
+		}
+		if *profile != "" {
+			if err := writeProfile(*profile, prof); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+		}
+	}
+	if collector != nil {
+		if err := writeCoverage(*cover, collector.Profiles()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	if errObj, ok := result.(*object.Error); ok {
+		if errObj.Exit {
+			return errObj.Code
+		}
+		fmt.Fprintf(os.Stderr, "%s: %s\n", path, errObj.Inspect())
+		return 1
+	}
+	return 0
+}
+
+// configFlags are the flags setting up the evaluator.Config of the programs monkey run and monkey test run.
+type configFlags struct {
+	fs      *flag.FlagSet
+	seed    *int64
+	now     *string
+	root    *string
+	noFiles *bool
+}
+
+func addConfigFlags(fs *flag.FlagSet) *configFlags {
+	return &configFlags{
+		fs:      fs,
+		seed:    fs.Int64("seed", 0, "seed math.random with `n` so the run can be repeated, without it the seed is picked from the time"),
+		now:     fs.String("now", "", "freeze the clock of the time module at `t`, an RFC 3339 time such as 2024-05-01T09:00:00Z"),
+		root:    fs.String("root", "", "only let the io module reach the files under `dir`"),
+		noFiles: fs.Bool("no-files", false, "deny the io module access to files"),
+	}
+}
+
+/*
+	config returns the Config the flags ask for once they are parsed. Programs use the standard streams and
+	every file of the machine unless --root or --no-files restrict them.
+*/
+func (f *configFlags) config() (evaluator.Config, error) {
+	var cfg evaluator.Config
+	f.fs.Visit(func(fl *flag.Flag) {
+		if fl.Name == "seed" {
+			cfg.Seed = f.seed
+		}
+	})
+	if *f.now != "" {
+		frozen, err := time.Parse(time.RFC3339Nano, *f.now)
+		if err != nil {
+			return cfg, fmt.Errorf("--now: %s", err)
+		}
+		cfg.Clock = func() time.Time { return frozen }
+	}
+	files := evaluator.HostFS()
+	switch {
+	case *f.noFiles:
+		files = nil
+	case *f.root != "":
+		root, err := evaluator.RootFS(*f.root)
+		if err != nil {
+			return cfg, fmt.Errorf("--root: %s", err)
+		}
+		files = root
+	}
+	cfg.IO = evaluator.IO{Stdout: os.Stdout, Stdin: os.Stdin, Files: files}
+	return cfg, nil
+}
+
+// parseCapabilities reads the value of --allow, the builtins a program run from the command line may use.
+func parseCapabilities(list string) (evaluator.Capability, error) {
+	names := map[string]evaluator.Capability{
+		"args": evaluator.AllowArgs,
+		"env":  evaluator.AllowEnv,
+		"exit": evaluator.AllowExit,
+		"exec": evaluator.AllowExec,
+		"all":  evaluator.AllowAll,
+	}
+	var c evaluator.Capability
+	if list == "" {
+		return c, nil
+	}
+	for _, name := range strings.Split(list, ",") {
+		allow, ok := names[strings.TrimSpace(name)]
+		if !ok {
+			return 0, fmt.Errorf("unknown builtin %q, want args, env, exit, exec or all", name)
+		}
+		c |= allow
+	}
+	return c, nil
+}
+
+func writeProfile(path string, prof *profiler.Profiler) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := prof.WritePprof(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func writeCoverage(path string, profiles []*coverage.Profile) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := coverage.Write(f, profiles); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
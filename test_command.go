@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+
+	"go-interpreter-lexer/coverage"
+	"go-interpreter-lexer/diagnostic"
+	"go-interpreter-lexer/evaluator"
+	"go-interpreter-lexer/resolver"
+	"go-interpreter-lexer/testrunner"
+)
+
+/*
+	testCommand runs the tests of *_test.mk files,
+	monkey test [-run regexp] [-v] [-junit file] [-coverage file] [-seed n] [-now t] [-root dir|-no-files] [path...]
+	Paths are test files or directories searched for them, the current directory by default. The seed, clock
+	and files are set up like for monkey run and every test starts with them. The exit code is 1 if a test
+	failed or a file could not be run.
+*/
+func testCommand(args []string) int {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	run := fs.String("run", "", "run only the tests whose name matches `regexp`")
+	verbose := fs.Bool("v", false, "list every test, not only the failures")
+	junit := fs.String("junit", "", "write a JUnit XML report to `file`")
+	cover := fs.String("coverage", "", "write the coverage of the test files to `file`")
+	host := addConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	cfg, err := host.config()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	runner := testrunner.New()
+	runner.Config = cfg
+	if *run != "" {
+		filter, err := regexp.Compile(*run)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "monkey test: bad -run: %s\n", err)
+			return 2
+		}
+		runner.Filter = filter
+	}
+	var collector *coverage.Collector
+	if *cover != "" {
+		collector = coverage.NewCollector()
+		runner.Hook = collector
+	}
+
+	files, err := testrunner.Discover(paths)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	code := 0
+	var results []testrunner.Result
+	passed, failed := 0, 0
+	for _, path := range files {
+		program, diags, err := parseFile(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = 1
+			continue
+		}
+		// every file given to monkey test is run as a test, whatever its name.
+		r := resolver.New(append(evaluator.BuiltinNames(), evaluator.AssertionNames()...))
+		if len(diags) == 0 {
+			r.Resolve(program)
+			diags = r.Diagnostics()
+		}
+		printDiagnostics(os.Stderr, path, diags)
+		if diagnostic.HasErrors(diags) {
+			fmt.Printf("FAIL %s\n", path)
+			code = 1
+			continue
+		}
+
+		if collector != nil {
+			collector.Add(path, program)
+		}
+		fileFailed := false
+		for _, res := range runner.Run(path, program) {
+			results = append(results, res)
+			if res.Passed() {
+				passed++
+				if *verbose {
+					fmt.Printf("--- PASS: %s (%s)\n", res.Name, res.Duration)
+				}
+				continue
+			}
+			failed++
+			fileFailed = true
+			fmt.Printf("--- FAIL: %s (%s)\n    %s\n        %s\n", res.Name, res.Duration, res.Location(), res.Failure)
+		}
+		if fileFailed {
+			fmt.Printf("FAIL %s\n", path)
+			code = 1
+		} else {
+			fmt.Printf("ok   %s\n", path)
+		}
+	}
+	fmt.Printf("%d passed, %d failed\n", passed, failed)
+
+	if *junit != "" {
+		f, err := os.Create(*junit)
+		if err == nil {
+			err = testrunner.WriteJUnit(f, results)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	if collector != nil {
+		if err := writeCoverage(*cover, collector.Profiles()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	return code
+}
@@ -0,0 +1,225 @@
+/*
+	Package testrunner runs the tests written in Monkey. A test file is named *_test.mk and every top-level
+	let binding a function whose name starts with test is a test:
+
+		let testAdd = fn() {
+			assertEq(add(1, 2), 3);
+		};
+
+	Each test gets a fresh environment: the whole file is evaluated again before the test function is
+	called, so tests cannot see what other tests did. The environment declares the assertions, assert,
+	assertEq and assertError, which other programs do not have. A test fails when it returns an error,
+	usually from an assertion, and the failure points at the call the error came from.
+*/
+package testrunner
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/evaluator"
+	"go-interpreter-lexer/format"
+	"go-interpreter-lexer/object"
+	"go-interpreter-lexer/token"
+)
+
+type Result struct {
+	File string
+	Name string
+	// Failure is the error message of a failed test, empty if it passed.
+	Failure string
+	// Expr is the call that failed and Pos its position, Expr is empty if the failure did not come from a call.
+	Expr     string
+	Pos      token.Position
+	Duration time.Duration
+}
+
+func (r Result) Passed() bool {
+	return r.Failure == ""
+}
+
+// Location is where a test failed, file:line:col followed by the failed call if there is one.
+func (r Result) Location() string {
+	loc := r.File
+	if r.Pos.IsValid() {
+		loc += ":" + r.Pos.String()
+	}
+	if r.Expr != "" {
+		loc += ": " + r.Expr
+	}
+	return loc
+}
+
+type Runner struct {
+	// Filter selects the tests to run by name, nil runs them all.
+	Filter *regexp.Regexp
+	// Hook is installed alongside the runner while tests run, for coverage for example. It replaces the Hook of Config.
+	Hook evaluator.Hook
+	// Now is the clock tests are timed with, time.Now unless a test replaces it.
+	Now func() time.Time
+	// Config is what every test is evaluated with, the standard streams and nothing else unless it is replaced.
+	Config evaluator.Config
+}
+
+func New() *Runner {
+	return &Runner{Now: time.Now, Config: evaluator.Config{IO: evaluator.IO{Stdout: os.Stdout, Stdin: os.Stdin}}}
+}
+
+// Tests returns the names of the tests of a program in the order they are declared.
+func Tests(program *ast.Program) []string {
+	var names []string
+	for _, stmt := range program.Statements {
+		switch stmt := stmt.(type) {
+		case *ast.LetStatement:
+			if _, ok := stmt.Value.(*ast.FunctionLiteral); ok && stmt.Name != nil && strings.HasPrefix(stmt.Name.Value, "test") {
+				names = append(names, stmt.Name.Value)
+			}
+		case *ast.FunctionStatement:
+			if stmt.Name != nil && strings.HasPrefix(stmt.Name.Value, "test") {
+				names = append(names, stmt.Name.Value)
+			}
+		}
+	}
+	return names
+}
+
+// Run runs the tests of a program parsed from file that match the filter.
+func (r *Runner) Run(file string, program *ast.Program) []Result {
+	var results []Result
+	for _, name := range Tests(program) {
+		if r.Filter != nil && !r.Filter.MatchString(name) {
+			continue
+		}
+		results = append(results, r.runTest(file, program, name))
+	}
+	return results
+}
+
+func (r *Runner) runTest(file string, program *ast.Program, name string) Result {
+	result := Result{File: file, Name: name}
+	f := &failure{}
+	var h evaluator.Hook = f
+	if r.Hook != nil {
+		h = evaluator.MultiHook(r.Hook, f)
+	}
+	cfg := r.Config
+	cfg.Hook = h
+
+	start := r.Now()
+	env := evaluator.NewEnvironment(cfg)
+	evaluator.DeclareAssertions(env)
+	obj := evaluator.Eval(program, env)
+	if !isError(obj) {
+		if fn, ok := env.Get(name); ok {
+			f.reset()
+			obj = evaluator.ApplyFunction(env, fn)
+		} else {
+			obj = &object.Error{Message: "the program returned before " + name + " was defined"}
+		}
+	}
+	result.Duration = r.Now().Sub(start)
+
+	if errObj, ok := obj.(*object.Error); ok {
+		result.Failure = errObj.Message
+		if f.call != nil {
+			result.Expr = format.Expression(f.call)
+			result.Pos = ast.Pos(f.call)
+		} else if f.stmt != nil {
+			result.Pos = ast.Pos(f.stmt)
+		}
+	}
+	return result
+}
+
+func isError(obj object.Object) bool {
+	_, ok := obj.(*object.Error)
+	return ok
+}
+
+/*
+	failure is a hook that finds where an error came from: the innermost call that returned it, or the last
+	statement evaluated if no call did. An error that a call swallows, as assertError does, is forgotten.
+*/
+type failure struct {
+	call *ast.CallExpression
+	stmt ast.Statement
+}
+
+func (f *failure) reset() {
+	f.call, f.stmt = nil, nil
+}
+
+func (f *failure) Eval(node ast.Node, env *object.Environment) {
+	if f.call != nil {
+		return
+	}
+	if stmt, ok := node.(ast.Statement); ok {
+		if _, block := stmt.(*ast.BlockStatement); !block {
+			f.stmt = stmt
+		}
+	}
+}
+
+func (f *failure) Call(call *ast.CallExpression, fn object.Object, args []object.Object) {}
+
+func (f *failure) Return(call *ast.CallExpression, fn object.Object, result object.Object) {
+	if !isError(result) {
+		f.call = nil
+		return
+	}
+	if f.call == nil && call != nil {
+		f.call = call
+	}
+}
+
+// IsTestFile reports whether path is named like a test file, *_test.mk.
+func IsTestFile(path string) bool {
+	return strings.HasSuffix(path, "_test.mk")
+}
+
+// BuiltinNames returns the builtins the program at path can use, the assertions are added for a test file.
+func BuiltinNames(path string) []string {
+	names := evaluator.BuiltinNames()
+	if IsTestFile(path) {
+		names = append(names, evaluator.AssertionNames()...)
+		sort.Strings(names)
+	}
+	return names
+}
+
+/*
+	Discover returns the test files named by paths in sorted order. A directory stands for every *_test.mk
+	file under it, a file is taken as it is.
+*/
+func Discover(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && IsTestFile(p) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
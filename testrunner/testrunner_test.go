@@ -0,0 +1,170 @@
+package testrunner
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"go-interpreter-lexer/evaluator"
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/parser"
+)
+
+const input = `let counter = [];
+let add = fn(a, b) { a + b };
+let testAdd = fn() {
+  assertEq(add(1, 2), 3);
+};
+let testWrong = fn() {
+  assertEq(add(1, 2), 4);
+};
+let testAssert = fn() {
+  assert(add(1, 1) == 3, "one and one");
+};
+let testError = fn() {
+  assertError(fn() { add(1, "a") });
+  assertError(fn() { 1 });
+};
+fn testType() {
+  1 + true;
+}
+let helper = fn() { 1 };`
+
+func run(t *testing.T, r *Runner) []Result {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Diagnostics()) != 0 {
+		t.Fatalf("parse errors %v", p.Diagnostics())
+	}
+	clock := time.Unix(0, 0)
+	r.Now = func() time.Time {
+		clock = clock.Add(time.Millisecond)
+		return clock
+	}
+	return r.Run("math_test.mk", program)
+}
+
+func TestRun(t *testing.T) {
+	results := run(t, New())
+	tests := []struct {
+		name     string
+		failure  string
+		location string
+	}{
+		{"testAdd", "", "math_test.mk"},
+		{"testWrong", "assertion failed: got 3, want 4", "math_test.mk:7:3: assertEq(add(1, 2), 4)"},
+		{"testAssert", `assertion failed: one and one`, `math_test.mk:10:3: assert(add(1, 1) == 3, "one and one")`},
+		{"testError", "assertion failed: expected an error, got 1", "math_test.mk:14:3: assertError(fn() {\n  1;\n})"},
+		{"testType", "type mismatch: INTEGER + BOOLEAN", "math_test.mk:17:3"},
+	}
+	if len(results) != len(tests) {
+		t.Fatalf("wrong number of results %d, want %d", len(results), len(tests))
+	}
+	for i, tt := range tests {
+		r := results[i]
+		if r.Name != tt.name || r.Failure != tt.failure || r.Location() != tt.location {
+			t.Errorf("wrong result %d\n got %s %q %q\nwant %s %q %q", i, r.Name, r.Failure, r.Location(), tt.name, tt.failure, tt.location)
+		}
+		if r.Duration != time.Millisecond {
+			t.Errorf("%s took %s", r.Name, r.Duration)
+		}
+	}
+}
+
+func TestConfig(t *testing.T) {
+	src := `let testNow = fn() {
+  assertEq(time.unix(time.now()), 60);
+};
+let testFile = fn() {
+  assertEq(io.readFile("data.txt"), "42");
+};
+let testRandom = fn() {
+  assertEq(math.random(1000000), math.random(1000000));
+};`
+	program := parser.New(lexer.New(src)).ParseProgram()
+	seed := int64(7)
+	r := New()
+	r.Config = evaluator.Config{
+		Clock: func() time.Time { return time.Unix(60, 0) },
+		IO:    evaluator.IO{Files: fstest.MapFS{"data.txt": {Data: []byte("42")}}},
+		Seed:  &seed,
+	}
+	results := r.Run("config_test.mk", program)
+	for _, res := range results[:2] {
+		if !res.Passed() {
+			t.Errorf("%s failed: %s", res.Name, res.Failure)
+		}
+	}
+
+	// every test starts with the same seed, so the numbers drawn in one test repeat in the next.
+	first := r.Run("config_test.mk", program)[2].Failure
+	if second := r.Run("config_test.mk", program)[2].Failure; first != second || first == "" {
+		t.Errorf("the seeded runs failed with %q and %q", first, second)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	r := New()
+	r.Filter = regexp.MustCompile("^test(Add|Error)$")
+	var names []string
+	for _, res := range run(t, r) {
+		names = append(names, res.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"testAdd", "testError"}) {
+		t.Errorf("wrong tests run %v", names)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	results := run(t, New())[:2]
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="2" failures="1" time="0.002">
+  <testsuite name="math_test.mk" tests="2" failures="1" time="0.002">
+    <testcase name="testAdd" classname="math_test.mk" time="0.001"></testcase>
+    <testcase name="testWrong" classname="math_test.mk" time="0.001">
+      <failure message="assertion failed: got 3, want 4">math_test.mk:7:3: assertEq(add(1, 2), 4)</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if buf.String() != want {
+		t.Errorf("wrong report\n got %s\nwant %s", buf.String(), want)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testrunner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"b_test.mk", "a.mk", "sub/c_test.mk", "sub/d.txt"} {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := ioutil.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, err := Discover([]string{dir, filepath.Join(dir, "a.mk")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range files {
+		got = append(got, strings.TrimPrefix(filepath.ToSlash(f), filepath.ToSlash(dir)+"/"))
+	}
+	if !reflect.DeepEqual(got, []string{"a.mk", "b_test.mk", "sub/c_test.mk"}) {
+		t.Errorf("wrong files %v", got)
+	}
+}
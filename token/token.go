@@ -1,5 +1,7 @@
 package token
 
+import "fmt"
+
 const(
 
 	ILLEGAL = "ILLEGAL"
@@ -8,6 +10,8 @@ const(
 	// Identifiers
 	IDENT = "IDENT"  // identifiers add, foobar, x, y
  	INT = "INT"   // integers 23, 12343
+	BIGINT = "BIGINT" // big integers 123n
+	DECIMAL = "DECIMAL" // decimals 12.50
 
 	//OPERATORS
 	ASSIGN = "="
@@ -16,6 +20,7 @@ const(
     BANG = "!"
     ASTERISK = "*"
     SLASH = "/"
+    PERCENT = "%"
 
     LT = "<"
     GT = ">"
@@ -30,6 +35,8 @@ const(
 	LBRACKET = "["
 	RBRACKET = "]"
 	COLON = ":"
+	ELLIPSIS = "..."
+	DOT = "."
 
 	// Keywords
 	FUNCTION = "FUNCTION"
@@ -51,6 +58,32 @@ type TokenType string
 type Token struct{
 	Type TokenType
 	Literal string
+	Pos Position
+}
+
+/*
+	Position of a token in the source, lines and columns start at 1. Columns count bytes rather than
+	characters. The zero value is used for tokens that were not produced by the lexer.
+*/
+type Position struct{
+	Line int
+	Column int
+}
+
+func (p Position) IsValid() bool{
+	return p.Line > 0
+}
+
+func (p Position) String() string{
+	if !p.IsValid(){
+		return "-"
+	}
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Before reports whether p comes before q in the source.
+func (p Position) Before(q Position) bool{
+	return p.Line < q.Line || p.Line == q.Line && p.Column < q.Column
 }
 
 var keywords = map[string]TokenType{
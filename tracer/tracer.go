@@ -0,0 +1,82 @@
+/*
+	Package tracer writes a line for every function call a program makes and another when it returns,
+	indented by the depth of the call:
+
+		-> add(1, 2) at 6:9
+		<- add = 3 (15µs)
+
+	Install a Tracer as the Hook of an evaluator.Config, tracing is off unless a host asks for it.
+*/
+package tracer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/evaluator"
+	"go-interpreter-lexer/object"
+)
+
+type Tracer struct {
+	w io.Writer
+	// Now is the clock durations are measured with, time.Now unless a test replaces it.
+	Now   func() time.Time
+	stack []time.Time
+}
+
+func New(w io.Writer) *Tracer {
+	return &Tracer{w: w, Now: time.Now}
+}
+
+// Eval implements evaluator.Hook, only calls are traced.
+func (t *Tracer) Eval(node ast.Node, env *object.Environment) {}
+
+func (t *Tracer) Call(call *ast.CallExpression, fn object.Object, args []object.Object) {
+	values := make([]string, len(args))
+	for i, a := range args {
+		values[i] = inspect(a)
+	}
+	fmt.Fprintf(t.w, "%s-> %s(%s)", t.indent(), evaluator.CallName(call, fn), strings.Join(values, ", "))
+	if call != nil {
+		fmt.Fprintf(t.w, " at %s", ast.Pos(call))
+	}
+	fmt.Fprintln(t.w)
+	t.stack = append(t.stack, t.Now())
+}
+
+func (t *Tracer) Return(call *ast.CallExpression, fn object.Object, result object.Object) {
+	if len(t.stack) == 0 {
+		return
+	}
+	start := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+	fmt.Fprintf(t.w, "%s<- %s = %s (%s)\n", t.indent(), evaluator.CallName(call, fn), inspect(result), t.Now().Sub(start))
+}
+
+// inspect is the value of an object on one line, functions are shown by their parameters.
+func inspect(obj object.Object) string {
+	switch obj := obj.(type) {
+	case nil:
+		return "null"
+	case *object.Function:
+		params := make([]string, len(obj.Parameters))
+		for i, p := range obj.Parameters {
+			params[i] = p.Value
+			if obj.Rest && i == len(obj.Parameters)-1 {
+				params[i] = "..." + p.Value
+			}
+		}
+		if obj.Name != "" {
+			return "fn " + obj.Name + "(" + strings.Join(params, ", ") + ")"
+		}
+		return "fn(" + strings.Join(params, ", ") + ")"
+	}
+	return obj.Inspect()
+}
+
+func (t *Tracer) indent() string {
+	return strings.Repeat("  ", len(t.stack))
+}
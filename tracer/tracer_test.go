@@ -0,0 +1,44 @@
+package tracer
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"go-interpreter-lexer/evaluator"
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/parser"
+)
+
+func TestTrace(t *testing.T) {
+	input := `let add = fn(a, b) { a + b };
+let twice = fn(f, x) { f(f(x, x), x) };
+twice(add, 2);
+fn(s) { len(s) }("abc");`
+
+	var out bytes.Buffer
+	tr := New(&out)
+	clock := time.Unix(0, 0)
+	tr.Now = func() time.Time {
+		clock = clock.Add(time.Millisecond)
+		return clock
+	}
+
+	program := parser.New(lexer.New(input)).ParseProgram()
+	evaluator.Eval(program, evaluator.NewEnvironment(evaluator.Config{Hook: tr}))
+
+	expected := `-> twice(fn add(a, b), 2) at 3:1
+  -> f(2, 2) at 2:26
+  <- f = 4 (1ms)
+  -> f(4, 2) at 2:24
+  <- f = 6 (1ms)
+<- twice = 6 (5ms)
+-> fn@4:7(abc) at 4:1
+  -> len(abc) at 4:9
+  <- len = 3 (1ms)
+<- fn@4:7 = 3 (3ms)
+`
+	if out.String() != expected {
+		t.Errorf("wrong trace.\nexpected=%s\ngot=%s", expected, out.String())
+	}
+}
@@ -0,0 +1,107 @@
+package typecheck
+
+import "fmt"
+
+// builtin describes a builtin function of the evaluator, check returns the result type for a call with
+// the given argument types or a message explaining why the arguments are wrong.
+type builtin struct {
+	typ   *Func
+	check func(args []Type) (Type, string)
+}
+
+// modules holds the types of the builtin modules by name.
+var modules = map[string]*Module{}
+
+var builtins = map[string]builtin{
+	"len": {
+		typ: &Func{Params: []Type{Any}, Result: Int},
+		check: func(args []Type) (Type, string) {
+			if len(args) != 1 {
+				return Int, wrongArity(1, len(args))
+			}
+			switch args[0].(type) {
+			case *Array:
+				return Int, ""
+			}
+			if args[0] == String || args[0] == Any {
+				return Int, ""
+			}
+			return Int, "argument not supported, got " + args[0].String()
+		},
+	},
+	"first": {typ: &Func{Params: []Type{&Array{Elem: Any}}, Result: Any}, check: element},
+	"last":  {typ: &Func{Params: []Type{&Array{Elem: Any}}, Result: Any}, check: element},
+	"puts": {
+		typ: &Func{Params: []Type{Any}, Result: Null, Variadic: true},
+		check: func(args []Type) (Type, string) {
+			return Null, ""
+		},
+	},
+	"assert": {
+		typ: &Func{Params: []Type{Any, String}, Result: Null},
+		check: func(args []Type) (Type, string) {
+			if len(args) < 1 || len(args) > 2 {
+				return Null, fmt.Sprintf("wrong number of arguments. got %d, want=1 or 2", len(args))
+			}
+			return Null, ""
+		},
+	},
+	"assertEq": {
+		typ: &Func{Params: []Type{Any, Any}, Result: Null},
+		check: func(args []Type) (Type, string) {
+			if len(args) != 2 {
+				return Null, wrongArity(2, len(args))
+			}
+			return Null, ""
+		},
+	},
+	"assertError": {
+		typ: &Func{Params: []Type{Any}, Result: String},
+		check: func(args []Type) (Type, string) {
+			if len(args) != 1 {
+				return String, wrongArity(1, len(args))
+			}
+			if _, ok := args[0].(*Func); !ok && args[0] != Any {
+				return String, "argument must be a function, got " + args[0].String()
+			}
+			return String, ""
+		},
+	},
+	"args": signature(&Array{Elem: String}, 0),
+	"env":  signature(Any, 0, String),
+	"exit": signature(Null, 1, Int),
+	"exec": signature(&Hash{Key: String, Value: Any}, 1, String, &Array{Elem: String}),
+	"decimal": {
+		typ: &Func{Params: []Type{Any, Int}, Result: Decimal, Optional: 1},
+		check: func(args []Type) (Type, string) {
+			if len(args) < 1 || len(args) > 2 {
+				return Decimal, fmt.Sprintf("wrong number of arguments. got %d, want=1 or 2", len(args))
+			}
+			if !numeric(args[0]) && args[0] != String && args[0] != Any {
+				return Decimal, "argument 1 must be a number or a string, got " + args[0].String()
+			}
+			if len(args) == 2 && !AssignableTo(args[1], Int) {
+				return Decimal, "argument 2 must be an int, got " + args[1].String()
+			}
+			return Decimal, ""
+		},
+	},
+}
+
+// element checks a builtin that takes an array and returns one of its elements.
+func element(args []Type) (Type, string) {
+	if len(args) != 1 {
+		return Any, wrongArity(1, len(args))
+	}
+	if arr, ok := args[0].(*Array); ok {
+		return arr.Elem, ""
+	}
+	if args[0] == Any {
+		return Any, ""
+	}
+	return Any, "argument must be an array, got " + args[0].String()
+}
+
+func wrongArity(want, got int) string {
+	return fmt.Sprintf("wrong number of arguments. got %d, want=%d", got, want)
+}
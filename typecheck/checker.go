@@ -0,0 +1,587 @@
+/*
+	Package typecheck infers and checks the types of a program before it runs. Annotations written in the
+	source (let x: int = 5, fn(a: string): bool { ... }) are checked against the values that flow into them,
+	and operators, calls and index expressions are checked the way the evaluator would check them at
+	runtime so a mismatch such as 5 + "a" is reported with its position without running the program.
+
+	Checking is gradual: unannotated parameters and anything else the checker cannot work out have type
+	any, which is compatible with every type, so unannotated programs only report errors that are certain.
+*/
+package typecheck
+
+import (
+	"fmt"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/diagnostic"
+	"go-interpreter-lexer/token"
+)
+
+type scope struct {
+	parent *scope
+	names  map[string]Type
+}
+
+func (s *scope) lookup(name string) (Type, bool) {
+	for sc := s; sc != nil; sc = sc.parent {
+		if t, ok := sc.names[name]; ok {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// function is the function literal being checked, results collects the types of its return statements.
+type function struct {
+	declared Type
+	results  Type
+}
+
+type Checker struct {
+	scope       *scope
+	fn          *function
+	types       map[ast.Node]Type
+	diagnostics []diagnostic.Diagnostic
+}
+
+func New() *Checker {
+	return &Checker{
+		scope: &scope{names: make(map[string]Type)},
+		types: make(map[ast.Node]Type),
+	}
+}
+
+// DefineGlobal gives a global the host defines before the program runs a type.
+func (c *Checker) DefineGlobal(name string, t Type) {
+	c.scope.names[name] = t
+}
+
+// Check checks program, Diagnostics returns the errors found.
+func (c *Checker) Check(program *ast.Program) {
+	c.declareFunctions(program.Statements)
+	for _, s := range program.Statements {
+		c.statement(s)
+	}
+}
+
+func (c *Checker) Diagnostics() []diagnostic.Diagnostic {
+	return c.diagnostics
+}
+
+// TypeOf returns the type inferred for an expression or declared identifier, nil if it was not checked.
+func (c *Checker) TypeOf(node ast.Node) Type {
+	return c.types[node]
+}
+
+func (c *Checker) errorf(pos token.Position, format string, a ...interface{}) {
+	d := diagnostic.Diagnostic{
+		Pos:      pos,
+		Severity: diagnostic.Error,
+		Code:     "type",
+		Message:  fmt.Sprintf(format, a...),
+	}
+	// annotations of a function are converted both when it is bound and when it is checked.
+	for _, seen := range c.diagnostics {
+		if seen == d {
+			return
+		}
+	}
+	c.diagnostics = append(c.diagnostics, d)
+}
+
+// statement checks a statement and returns the type of the value it evaluates to.
+func (c *Checker) statement(s ast.Statement) Type {
+	switch s := s.(type) {
+	case *ast.LetStatement:
+		if s != nil {
+			c.let(s)
+		}
+		return Null
+	case *ast.FunctionStatement:
+		if s != nil && s.Name != nil && s.Function != nil {
+			c.scope.names[s.Name.Value] = c.signature(s.Function)
+			t := c.expr(s.Function)
+			c.scope.names[s.Name.Value] = t
+			c.types[s.Name] = t
+		}
+		return Null
+	case *ast.ReturnStatement:
+		if s == nil {
+			return Any
+		}
+		if s.ReturnValue == nil {
+			if c.fn != nil {
+				c.result(s.Token.Pos, Null)
+			}
+			return Null
+		}
+		t := c.expr(s.ReturnValue)
+		if c.fn != nil {
+			c.result(ast.Pos(s.ReturnValue), t)
+		}
+		return t
+	case *ast.ExpressionStatement:
+		if s == nil {
+			return Any
+		}
+		return c.expr(s.Expression)
+	case *ast.BlockStatement:
+		return c.block(s)
+	}
+	return Any
+}
+
+/*
+	declareFunctions binds the functions declared by stmts to the type of their annotations before the
+	statements are checked, the evaluator binds them before the statements run.
+*/
+func (c *Checker) declareFunctions(stmts []ast.Statement) {
+	for _, s := range stmts {
+		if fs, ok := s.(*ast.FunctionStatement); ok && fs.Name != nil && fs.Function != nil {
+			c.scope.names[fs.Name.Value] = c.signature(fs.Function)
+		}
+	}
+}
+
+func (c *Checker) block(b *ast.BlockStatement) Type {
+	if b == nil {
+		return Null
+	}
+	var t Type = Null
+	for _, s := range b.Statements {
+		t = c.statement(s)
+	}
+	return t
+}
+
+func (c *Checker) let(s *ast.LetStatement) {
+	var declared Type
+	if s.Type != nil {
+		declared = c.annotation(s.Type)
+	}
+
+	// a function is bound before its body is checked so it can call itself.
+	if fl, ok := s.Value.(*ast.FunctionLiteral); ok && s.Name != nil {
+		if declared != nil {
+			c.scope.names[s.Name.Value] = declared
+		} else {
+			c.scope.names[s.Name.Value] = c.signature(fl)
+		}
+	}
+
+	t := c.expr(s.Value)
+	if declared != nil {
+		if !AssignableTo(t, declared) {
+			c.errorf(s.Token.Pos, "cannot use %s as %s in let %s", t, declared, s.Name.Value)
+		}
+		t = declared
+	}
+	if s.Name != nil {
+		c.scope.names[s.Name.Value] = t
+		c.types[s.Name] = t
+	}
+}
+
+// annotation converts a type written in the source.
+func (c *Checker) annotation(te ast.TypeExpr) Type {
+	switch te := te.(type) {
+	case *ast.NamedType:
+		if t, ok := basics[te.Name]; ok {
+			return t
+		}
+		c.errorf(te.Token.Pos, "unknown type %s", te.Name)
+	case *ast.ArrayType:
+		return &Array{Elem: c.annotation(te.Element)}
+	case *ast.HashType:
+		key := c.annotation(te.Key)
+		if !hashable(key) {
+			c.errorf(te.Token.Pos, "unusable as hash key: %s", key)
+		}
+		return &Hash{Key: key, Value: c.annotation(te.Value)}
+	case *ast.FunctionType:
+		f := &Func{Result: Any}
+		for _, p := range te.Parameters {
+			f.Params = append(f.Params, c.annotation(p))
+		}
+		if te.Result != nil {
+			f.Result = c.annotation(te.Result)
+		}
+		return f
+	}
+	return Any
+}
+
+// signature is the type of a function literal from its annotations alone.
+func (c *Checker) signature(fl *ast.FunctionLiteral) *Func {
+	f := &Func{Result: Any, Variadic: fl.Rest}
+	for i := range fl.Parameters {
+		var t Type = Any
+		rest := fl.Rest && i == len(fl.Parameters)-1
+		if i < len(fl.ParameterTypes) && fl.ParameterTypes[i] != nil {
+			t = c.annotation(fl.ParameterTypes[i])
+			// a rest parameter is annotated with the array it is bound to.
+			if arr, ok := t.(*Array); ok && rest {
+				t = arr.Elem
+			}
+		} else if i < len(fl.Defaults) && fl.Defaults[i] != nil {
+			t = literalType(fl.Defaults[i])
+		}
+		if i < len(fl.Defaults) && fl.Defaults[i] != nil {
+			f.Optional++
+		}
+		f.Params = append(f.Params, t)
+	}
+	if fl.ResultType != nil {
+		f.Result = c.annotation(fl.ResultType)
+	}
+	return f
+}
+
+func (c *Checker) expr(e ast.Expression) Type {
+	if e == nil {
+		return Any
+	}
+	t := c.infer(e)
+	c.types[e] = t
+	return t
+}
+
+func (c *Checker) infer(e ast.Expression) Type {
+	switch e := e.(type) {
+	case *ast.IntegerLiteral:
+		return Int
+	case *ast.BigIntLiteral:
+		return BigInt
+	case *ast.DecimalLiteral:
+		return Decimal
+	case *ast.StringLiteral:
+		return String
+	case *ast.Boolean:
+		return Bool
+	case *ast.Identifier:
+		if t, ok := c.scope.lookup(e.Value); ok {
+			return t
+		}
+		if b, ok := builtins[e.Value]; ok {
+			return b.typ
+		}
+		if m, ok := modules[e.Value]; ok {
+			return m
+		}
+		return Any
+	case *ast.PrefixExpression:
+		return c.prefix(e)
+	case *ast.InfixExpression:
+		return c.infix(e)
+	case *ast.IfExpression:
+		c.expr(e.Condition)
+		t := c.block(e.Consequence)
+		if e.Alternative == nil {
+			return join(t, Null)
+		}
+		return join(t, c.block(e.Alternative))
+	case *ast.FunctionLiteral:
+		return c.function(e)
+	case *ast.SpreadExpression:
+		t := c.expr(e.Value)
+		if _, ok := t.(*Array); !ok && t != Any {
+			c.errorf(e.Token.Pos, "cannot spread %s, only arrays can be spread", t)
+		}
+		return t
+	case *ast.CallExpression:
+		return c.call(e)
+	case *ast.ArrayLiteral:
+		var elem Type
+		for _, el := range e.Elements {
+			elem = join(elem, c.expr(el))
+		}
+		if elem == nil {
+			elem = Any
+		}
+		return &Array{Elem: elem}
+	case *ast.IndexExpression:
+		return c.index(e)
+	case *ast.MemberExpression:
+		return c.member(e)
+	case *ast.HashLiteral:
+		var key, value Type
+		for k, v := range e.Pairs {
+			kt := c.expr(k)
+			if !hashable(kt) {
+				c.errorf(e.Token.Pos, "unusable as hash key: %s", kt)
+			}
+			key = join(key, kt)
+			value = join(value, c.expr(v))
+		}
+		if key == nil {
+			key, value = Any, Any
+		}
+		return &Hash{Key: key, Value: value}
+	}
+	return Any
+}
+
+func (c *Checker) prefix(e *ast.PrefixExpression) Type {
+	right := c.expr(e.Right)
+	switch e.Operator {
+	case "!":
+		return Bool
+	case "-":
+		if numeric(right) || right == Any {
+			return right
+		}
+		c.errorf(e.Token.Pos, "unknown operator: -%s", right)
+	}
+	return Any
+}
+
+// kind is what the evaluator compares when it decides if two operands have the same type.
+func kind(t Type) string {
+	switch t.(type) {
+	case *Array:
+		return "array"
+	case *Hash:
+		return "hash"
+	case *Func:
+		return "fn"
+	}
+	return t.String()
+}
+
+func (c *Checker) infix(e *ast.InfixExpression) Type {
+	right := c.expr(e.Right)
+	left := c.expr(e.Left)
+	op := e.Operator
+
+	if op == "==" || op == "!=" {
+		return Bool
+	}
+	if left == Any || right == Any {
+		if op == "<" || op == ">" {
+			return Bool
+		}
+		return Any
+	}
+
+	if t := temporal(left, op, right); t != nil {
+		return t
+	}
+	switch {
+	case numeric(left) && numeric(right):
+		switch op {
+		case "+", "-", "*", "/", "%":
+			return arithmetic(left, right)
+		case "<", ">":
+			return Bool
+		}
+	case kind(left) != kind(right):
+		c.errorf(e.Token.Pos, "type mismatch: %s %s %s", left, op, right)
+		return Any
+	case left == String && op == "+":
+		return String
+	case left == String && (op == "<" || op == ">"):
+		return Bool
+	}
+	c.errorf(e.Token.Pos, "unknown operator: %s %s %s", left, op, right)
+	return Any
+}
+
+func (c *Checker) function(fl *ast.FunctionLiteral) Type {
+	sig := c.signature(fl)
+
+	outer, outerFn := c.scope, c.fn
+	c.scope = &scope{parent: outer, names: make(map[string]Type)}
+	c.fn = &function{}
+	if fl.ResultType != nil {
+		c.fn.declared = sig.Result
+	}
+	defer func() { c.scope, c.fn = outer, outerFn }()
+
+	for i, p := range fl.Parameters {
+		if p == nil {
+			continue
+		}
+		if i < len(fl.Defaults) && fl.Defaults[i] != nil {
+			if t := c.expr(fl.Defaults[i]); !AssignableTo(t, sig.Params[i]) {
+				c.errorf(ast.Pos(fl.Defaults[i]), "cannot use %s as the default value of %s %s", t, p.Value, sig.Params[i])
+			}
+		}
+		t := sig.Params[i]
+		if fl.Rest && i == len(fl.Parameters)-1 {
+			t = &Array{Elem: t}
+		}
+		c.scope.names[p.Value] = t
+		c.types[p] = t
+	}
+
+	if fl.Body != nil {
+		c.declareFunctions(fl.Body.Statements)
+	}
+	last := c.block(fl.Body)
+	// the value of the last statement is returned unless it is a return statement itself.
+	if fl.Body != nil && len(fl.Body.Statements) > 0 {
+		if es, ok := fl.Body.Statements[len(fl.Body.Statements)-1].(*ast.ExpressionStatement); ok {
+			c.result(ast.Pos(es.Expression), last)
+		}
+	} else {
+		c.result(fl.Token.Pos, Null)
+	}
+
+	if c.fn.declared == nil {
+		sig.Result = c.fn.results
+		if sig.Result == nil {
+			sig.Result = Null
+		}
+	}
+	return sig
+}
+
+// result records a value returned from the function being checked and checks it against the annotation.
+func (c *Checker) result(pos token.Position, t Type) {
+	if c.fn.declared != nil && !AssignableTo(t, c.fn.declared) {
+		c.errorf(pos, "cannot return %s from function returning %s", t, c.fn.declared)
+	}
+	c.fn.results = join(c.fn.results, t)
+}
+
+func (c *Checker) call(e *ast.CallExpression) Type {
+	callee := c.expr(e.Function)
+	args := make([]Type, len(e.Arguments))
+	// the number of arguments is unknown once an array is spread, only those before it are checked.
+	spread := -1
+	for i, a := range e.Arguments {
+		args[i] = c.expr(a)
+		if _, ok := a.(*ast.SpreadExpression); ok && spread < 0 {
+			spread = i
+		}
+	}
+
+	if b, name, ok := c.builtinCalled(e.Function); ok {
+		if spread >= 0 {
+			return b.typ.Result
+		}
+		t, err := b.check(args)
+		if err != "" {
+			c.errorf(e.Token.Pos, "%s: %s", name, err)
+		}
+		return t
+	}
+
+	switch f := callee.(type) {
+	case *Func:
+		if spread >= 0 {
+			args = args[:spread]
+		} else if len(args) < f.minArgs() || !f.Variadic && len(args) > len(f.Params) {
+			c.errorf(e.Token.Pos, "wrong number of arguments: want=%s, got=%d", f.arity(), len(args))
+			return f.Result
+		}
+		for i, a := range args {
+			var want Type = Any
+			if i < len(f.Params) {
+				want = f.Params[i]
+			} else if f.Variadic && len(f.Params) > 0 {
+				want = f.Params[len(f.Params)-1]
+			}
+			if !AssignableTo(a, want) {
+				c.errorf(ast.Pos(e.Arguments[i]), "cannot use %s as %s in argument %d", a, want, i+1)
+			}
+		}
+		return f.Result
+	case *Basic:
+		if f == Any {
+			return Any
+		}
+	}
+	c.errorf(e.Token.Pos, "not a function: %s", callee)
+	return Any
+}
+
+// builtinCalled returns the builtin fn refers to and its name for messages, fn is either a builtin the program
+// has not shadowed or a member of a builtin module.
+func (c *Checker) builtinCalled(fn ast.Expression) (builtin, string, bool) {
+	switch fn := fn.(type) {
+	case *ast.Identifier:
+		if _, shadowed := c.scope.lookup(fn.Value); !shadowed {
+			b, ok := builtins[fn.Value]
+			return b, fn.Value, ok
+		}
+	case *ast.MemberExpression:
+		if m, ok := c.types[fn.Object].(*Module); ok {
+			b, ok := m.members[fn.Property.Value]
+			return b, m.Name + "." + fn.Property.Value, ok
+		}
+	}
+	return builtin{}, "", false
+}
+
+func (c *Checker) index(e *ast.IndexExpression) Type {
+	left := c.expr(e.Left)
+	index := c.expr(e.Index)
+
+	switch l := left.(type) {
+	case *Array:
+		if index == Int || index == Any {
+			return l.Elem
+		}
+	case *Hash:
+		if !hashable(index) {
+			c.errorf(e.Token.Pos, "unusable as hash key: %s", index)
+			return Any
+		}
+		if !AssignableTo(index, l.Key) {
+			c.errorf(e.Token.Pos, "cannot use %s as %s hash key", index, l.Key)
+		}
+		return l.Value
+	case *Basic:
+		if l == Any {
+			return Any
+		}
+		if l == String && (index == Int || index == Any) {
+			return String
+		}
+	}
+	c.errorf(e.Token.Pos, "index operator not supported: %s", left)
+	return Any
+}
+
+// member checks a member expression, a member of a hash is the value of the key with the member's name and
+// a member of a module is one of its builtins.
+func (c *Checker) member(e *ast.MemberExpression) Type {
+	object := c.expr(e.Object)
+	switch o := object.(type) {
+	case *Hash:
+		if !AssignableTo(String, o.Key) {
+			c.errorf(e.Token.Pos, "cannot use string as %s hash key", o.Key)
+		}
+		return o.Value
+	case *Module:
+		if b, ok := o.members[e.Property.Value]; ok {
+			return b.typ
+		}
+		c.errorf(e.Property.Token.Pos, "module %s has no member %s", o.Name, e.Property.Value)
+		return Any
+	case *Basic:
+		if o == Any {
+			return Any
+		}
+	}
+	c.errorf(e.Token.Pos, "member access not supported: %s", object)
+	return Any
+}
+
+// literalType is the type of a literal used as a default value, Any for other expressions.
+func literalType(e ast.Expression) Type {
+	switch e.(type) {
+	case *ast.IntegerLiteral:
+		return Int
+	case *ast.BigIntLiteral:
+		return BigInt
+	case *ast.DecimalLiteral:
+		return Decimal
+	case *ast.StringLiteral:
+		return String
+	case *ast.Boolean:
+		return Bool
+	}
+	return Any
+}
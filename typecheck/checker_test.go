@@ -0,0 +1,105 @@
+package typecheck
+
+import (
+	"testing"
+
+	"go-interpreter-lexer/ast"
+	"go-interpreter-lexer/lexer"
+	"go-interpreter-lexer/parser"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"let x: int = 5; x + 1;", nil},
+		{`5 + "a"`, []string{"1:3: error: type mismatch: int + string (type)"}},
+		{`let x: int = "a";`, []string{"1:1: error: cannot use string as int in let x (type)"}},
+		{`let f = fn(a: string, b: [int]): bool { len(b) > len(a) }; f("x", [1]);`, nil},
+		{`let f = fn(a: string): bool { a }`, []string{"1:31: error: cannot return string from function returning bool (type)"}},
+		{`let f = fn(a: int) { a }; f("x");`, []string{"1:29: error: cannot use string as int in argument 1 (type)"}},
+		{`let f = fn(a, b) { a }; f(1);`, []string{"1:26: error: wrong number of arguments: want=2, got=1 (type)"}},
+		{`let f = fn(a) { a + 1 }; f("a");`, nil},
+		{`let f = fn() { 5 }; f() + "a";`, []string{"1:25: error: type mismatch: int + string (type)"}},
+		{`-"a"`, []string{"1:1: error: unknown operator: -string (type)"}},
+		{`"a" - "b"`, []string{"1:5: error: unknown operator: string - string (type)"}},
+		{`true + false`, []string{"1:6: error: unknown operator: bool + bool (type)"}},
+		{`let x = 5; x(1)`, []string{"1:13: error: not a function: int (type)"}},
+		{`[1, 2]["a"]`, []string{"1:7: error: index operator not supported: [int] (type)"}},
+		{`{"a": 1}[[1]]`, []string{"1:9: error: unusable as hash key: [int] (type)"}},
+		{`len(1)`, []string{"1:4: error: len: argument not supported, got int (type)"}},
+		{`first([1]) + "a"`, []string{"1:12: error: type mismatch: int + string (type)"}},
+		{`let fact = fn(n: int): int { if (n < 2) { return 1 }; n * fact(n - 1) }; fact(5);`, nil},
+		{`let apply = fn(f: fn(int): int, x: int): int { f(x) }; apply(fn(a: int): int { a * 2 }, 2);`, nil},
+		{`let apply = fn(f: fn(int): int) { f(1) }; apply(fn(a: string): int { 1 });`, []string{"1:49: error: cannot use fn(string): int as fn(int): int in argument 1 (type)"}},
+		{`let h: {string: int} = {"a": 1}; h["a"] + 1;`, nil},
+		{`let x: foo = 1;`, []string{"1:8: error: unknown type foo (type)"}},
+		{`let xs: [string] = map([1, 2], fn(x: int): string { "a" }); let n: int = reduce(xs, fn(acc, x) { 1 }, 0);`, nil},
+		{`map([1], 1)`, []string{"1:4: error: map: argument 2 must be a function, got int (type)"}},
+		{`filter(1, fn(x) { x })`, []string{"1:7: error: filter: argument 1 must be an array, got int (type)"}},
+		{`first(range(3)) + "a"; any([1], fn(x) { x }) + 1;`, []string{"1:17: error: type mismatch: int + string (type)", "1:46: error: type mismatch: bool + int (type)"}},
+		{`let xs: [int] = flatten([[1], [2]]); let ys: [string] = concat([1], [2]);`, []string{"1:38: error: cannot use [int] as [string] in let ys (type)"}},
+		{`let k: [string] = keys({"a": 1}); let v: [int] = values({"a": 1}); let h: {string: int} = merge({"a": 1}, {"b": 2});`, nil},
+		{`has({"a": 1}, [1]); delete({"a": 1}, 1); keys([1]);`, []string{"1:4: error: has: unusable as hash key: [int] (type)", "1:27: error: delete: cannot use int as string in argument 2 (type)", "1:46: error: keys: argument 1 must be a hash, got [int] (type)"}},
+		{`sort([1], 2)`, []string{"1:5: error: sort: argument 2 must be a function, got int (type)"}},
+		{`push([1], ...[2, 3]); slice([1], "a");`, []string{"1:28: error: slice: argument 2 must be an int, got string (type)"}},
+		{`let f = fn(a, b = 10) { a + b }; f(1); f(1, 2); f(1, "x");`, []string{"1:54: error: cannot use string as int in argument 2 (type)"}},
+		{`let f = fn(a, b = 10) { a }; f();`, []string{"1:31: error: wrong number of arguments: want=1 to 2, got=0 (type)"}},
+		{`let f = fn(a: int = "x") { a };`, []string{"1:21: error: cannot use string as the default value of a int (type)"}},
+		{`let f = fn(first, ...rest: [int]) { len(rest) + first }; f(1, 2, 3); f(1, "x");`, []string{"1:75: error: cannot use string as int in argument 2 (type)"}},
+		{`let x: int = double(2); fn double(n: int): int { n * 2 }`, nil},
+		{`fn even(n: int): bool { if (n == 0) { true } else { odd(n - 1) } } fn odd(n: int): bool { even(n - 1) } even("x");`, []string{"1:110: error: cannot use string as int in argument 1 (type)"}},
+		{`fn f(a: foo) { a }`, []string{"1:9: error: unknown type foo (type)"}},
+		{`let f = fn(first, ...rest) { rest }; f();`, []string{"1:39: error: wrong number of arguments: want=at least 1, got=0 (type)"}},
+		{`let a: [string] = strings.split("a,b", ","); let n: int = strings.indexOf("a", "b"); let s: string = strings.format("%d", 1) + "a"[0];`, nil},
+		{`strings.upper(1); strings.padLeft("a"); strings.nope; strings + 1;`, []string{"1:14: error: strings.upper: argument 1 must be string, got int (type)", "1:34: error: strings.padLeft: wrong number of arguments. got 1, want=2 or 3 (type)", "1:49: error: module strings has no member nope (type)", "1:63: error: type mismatch: module strings + int (type)"}},
+		{`let x: bool = "a" < "b"; let h = {"a": 1}; let y: string = h.a; 1.a;`, []string{"1:44: error: cannot use int as string in let y (type)", "1:66: error: member access not supported: int (type)"}},
+		{`let n: int = math.max(1, 2, 3) % math.pow(2, 3) + math.random(10); math.sqrt("4"); math.min();`, []string{"1:77: error: math.sqrt: argument 1 must be int, got string (type)", "1:92: error: math.min: wrong number of arguments. got 0, want=at least 1 (type)"}},
+		{`let a: decimal = math.abs(-1.5) + math.max(1.5, 2.5); let b: bigint = math.pow(2n, 3); let c: int = math.clamp(5, 0, 3); math.abs("a"); let d: int = math.min(1, 2.5);`, []string{"1:130: error: math.abs: argument 1 must be a number, got string (type)"}},
+		{`let n: int = math.round(2.5) + math.div(7, 2); let b: bigint = math.floor(10n); math.ceil("1");`, []string{"1:90: error: math.ceil: argument 1 must be a number, got string (type)"}},
+		{`let a: decimal = 1.50 * 2; let b: bigint = 2n + 1; let c: decimal = b / 2.0; let d: decimal = decimal("1.5", 1); let h = {1.5: "x"}; -a;`, nil},
+		{`let a: int = 1.5; 2n + "a"; decimal(true);`, []string{"1:1: error: cannot use decimal as int in let a (type)", "1:22: error: type mismatch: bigint + string (type)", "1:36: error: decimal: argument 1 must be a number or a string, got bool (type)"}},
+		{`let s: string = json.stringify(json.parse("[1]"), 2); json.parse(1); json.stringify();`, []string{"1:65: error: json.parse: argument 1 must be string, got int (type)", "1:84: error: json.stringify: wrong number of arguments. got 0, want=1 or 2 (type)"}},
+		{`let re: regex = regex.compile("a+"); let b: bool = regex.match(re, "aa"); let p: [string] = regex.split("a", "bab", 2); regex.find(1, "a"); regex.findAll(re, 1);`, []string{"1:131: error: regex.find: argument 1 must be regex or string, got int (type)", "1:154: error: regex.findAll: argument 2 must be string, got int (type)"}},
+		{`let t: time = time.now() + time.duration("1h"); let d: duration = time.since(t) * 2; let n: int = d / time.duration("1s"); let b: bool = t < time.now(); time.now() + 1; time.now() * time.now();`, []string{"1:165: error: type mismatch: time + int (type)", "1:181: error: unknown operator: time * time (type)"}},
+		{`let s: string = io.readFile("a.txt"); let names: [string] = io.listDir(); io.print(1, "a"); io.writeFile("b.txt", 1);`, []string{"1:105: error: io.writeFile: argument 2 must be string, got int (type)"}},
+		{`let a: [string] = args(); let r = exec("ls", ["-l"]); let o: string = r.stdout; exit(); exec("ls", [1]);`, []string{"1:93: error: exec: argument 2 must be [string], got [int] (type)"}},
+		{`let f = fn(a, b) { a }; f(...[1, 2]); f(...1);`, []string{"1:41: error: cannot spread int, only arrays can be spread (type)"}},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%q: parser errors %v", tt.input, p.Errors())
+		}
+		c := New()
+		c.Check(program)
+		diags := c.Diagnostics()
+		if len(diags) != len(tt.expected) {
+			t.Errorf("%q: expected %d diagnostics got %v", tt.input, len(tt.expected), diags)
+			continue
+		}
+		for i, d := range diags {
+			if d.String() != tt.expected[i] {
+				t.Errorf("%q: wrong diagnostic. expected=%q, got=%q", tt.input, tt.expected[i], d.String())
+			}
+		}
+	}
+}
+
+func TestTypeOf(t *testing.T) {
+	p := parser.New(lexer.New(`let f = fn(a: int) { [a, a] }; let g = f(1); let h = {"a": g};`))
+	program := p.ParseProgram()
+	c := New()
+	c.Check(program)
+
+	expected := []string{"fn(int): [int]", "[int]", "{string: [int]}"}
+	for i, e := range expected {
+		typ := c.TypeOf(program.Statements[i].(*ast.LetStatement).Name)
+		if typ == nil || typ.String() != e {
+			t.Errorf("statement %d: expected type %s got %v", i, e, typ)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package typecheck
+
+import "fmt"
+
+var anyArray = &Array{Elem: Any}
+
+// the collection builtins of the evaluator, see evaluator/collections.go.
+func init() {
+	for name, b := range map[string]builtin{
+		"push": {
+			typ: &Func{Params: []Type{anyArray, Any}, Result: anyArray, Variadic: true},
+			check: func(args []Type) (Type, string) {
+				if len(args) < 1 {
+					return anyArray, fmt.Sprintf("wrong number of arguments. got %d, want=at least 1", len(args))
+				}
+				arr, err := arrayParam(args, 0)
+				elem := arr.Elem
+				for _, v := range args[1:] {
+					elem = join(elem, v)
+				}
+				return &Array{Elem: elem}, err
+			},
+		},
+		"rest":    {typ: &Func{Params: []Type{anyArray}, Result: anyArray}, check: sameArray(1, 1)},
+		"reverse": {typ: &Func{Params: []Type{anyArray}, Result: anyArray}, check: sameArray(1, 1)},
+		"uniq":    {typ: &Func{Params: []Type{anyArray}, Result: anyArray}, check: sameArray(1, 1)},
+		"slice": {
+			typ: &Func{Params: []Type{anyArray, Int, Int}, Result: anyArray, Optional: 1},
+			check: func(args []Type) (Type, string) {
+				t, err := sameArray(2, 3)(args)
+				for i := 1; i < len(args) && err == ""; i++ {
+					if !AssignableTo(args[i], Int) {
+						err = fmt.Sprintf("argument %d must be an int, got %s", i+1, args[i])
+					}
+				}
+				return t, err
+			},
+		},
+		"concat": {
+			typ:   &Func{Params: []Type{anyArray}, Result: anyArray, Variadic: true},
+			check: joinArrays(func(elem Type) Type { return &Array{Elem: elem} }),
+		},
+		"zip": {
+			typ:   &Func{Params: []Type{anyArray}, Result: &Array{Elem: anyArray}, Variadic: true},
+			check: joinArrays(func(elem Type) Type { return &Array{Elem: &Array{Elem: elem}} }),
+		},
+		"map": {
+			typ: &Func{Params: []Type{anyArray, Any}, Result: anyArray},
+			check: func(args []Type) (Type, string) {
+				if len(args) != 2 {
+					return anyArray, wrongArity(2, len(args))
+				}
+				_, err := arrayParam(args, 0)
+				if err == "" {
+					err = funcParam(args, 1)
+				}
+				return &Array{Elem: resultOf(args[1])}, err
+			},
+		},
+		"filter": {typ: &Func{Params: []Type{anyArray, Any}, Result: anyArray}, check: arrayWithFunc(2)},
+		"sort":   {typ: &Func{Params: []Type{anyArray, Any}, Result: anyArray, Optional: 1}, check: arrayWithFunc(1)},
+		"find":   {typ: &Func{Params: []Type{anyArray, Any}, Result: Any}, check: predicate(Any)},
+		"any":    {typ: &Func{Params: []Type{anyArray, Any}, Result: Bool}, check: predicate(Bool)},
+		"all":    {typ: &Func{Params: []Type{anyArray, Any}, Result: Bool}, check: predicate(Bool)},
+		"reduce": {
+			typ: &Func{Params: []Type{anyArray, Any, Any}, Result: Any, Optional: 1},
+			check: func(args []Type) (Type, string) {
+				if len(args) < 2 || len(args) > 3 {
+					return Any, fmt.Sprintf("wrong number of arguments. got %d, want=2 or 3", len(args))
+				}
+				_, err := arrayParam(args, 0)
+				if err == "" {
+					err = funcParam(args, 1)
+				}
+				return resultOf(args[1]), err
+			},
+		},
+		"range": {
+			typ: &Func{Params: []Type{Int, Int, Int}, Result: &Array{Elem: Int}, Optional: 2},
+			check: func(args []Type) (Type, string) {
+				t := &Array{Elem: Int}
+				if len(args) < 1 || len(args) > 3 {
+					return t, fmt.Sprintf("wrong number of arguments. got %d, want=1 to 3", len(args))
+				}
+				for i, a := range args {
+					if !AssignableTo(a, Int) {
+						return t, fmt.Sprintf("argument %d must be an int, got %s", i+1, a)
+					}
+				}
+				return t, ""
+			},
+		},
+		"flatten": {
+			typ: &Func{Params: []Type{anyArray}, Result: anyArray},
+			check: func(args []Type) (Type, string) {
+				if len(args) != 1 {
+					return anyArray, wrongArity(1, len(args))
+				}
+				arr, err := arrayParam(args, 0)
+				if inner, ok := arr.Elem.(*Array); ok {
+					return inner, err
+				}
+				if arr.Elem == Any {
+					return anyArray, err
+				}
+				return arr, err
+			},
+		},
+	} {
+		builtins[name] = b
+	}
+}
+
+// arrayParam returns the argument at i as an array, any is an array of any.
+func arrayParam(args []Type, i int) (*Array, string) {
+	if arr, ok := args[i].(*Array); ok {
+		return arr, ""
+	}
+	if args[i] != Any {
+		return anyArray, fmt.Sprintf("argument %d must be an array, got %s", i+1, args[i])
+	}
+	return anyArray, ""
+}
+
+func funcParam(args []Type, i int) string {
+	if _, ok := args[i].(*Func); !ok && args[i] != Any {
+		return fmt.Sprintf("argument %d must be a function, got %s", i+1, args[i])
+	}
+	return ""
+}
+
+// resultOf is the type a call of t returns.
+func resultOf(t Type) Type {
+	if f, ok := t.(*Func); ok {
+		return f.Result
+	}
+	return Any
+}
+
+// sameArray checks a builtin whose first argument is an array and that returns an array of the same type.
+func sameArray(min, max int) func(args []Type) (Type, string) {
+	return func(args []Type) (Type, string) {
+		if len(args) < min || len(args) > max {
+			if min == max {
+				return anyArray, wrongArity(min, len(args))
+			}
+			return anyArray, fmt.Sprintf("wrong number of arguments. got %d, want=%d or %d", len(args), min, max)
+		}
+		return arrayParam(args, 0)
+	}
+}
+
+// arrayWithFunc checks filter and sort, they take an array and a function and return the same array type.
+func arrayWithFunc(min int) func(args []Type) (Type, string) {
+	return func(args []Type) (Type, string) {
+		t, err := sameArray(min, 2)(args)
+		if err == "" && len(args) == 2 {
+			err = funcParam(args, 1)
+		}
+		return t, err
+	}
+}
+
+// predicate checks a builtin that takes an array and a function and returns result.
+func predicate(result Type) func(args []Type) (Type, string) {
+	return func(args []Type) (Type, string) {
+		if len(args) != 2 {
+			return result, wrongArity(2, len(args))
+		}
+		_, err := arrayParam(args, 0)
+		if err == "" {
+			err = funcParam(args, 1)
+		}
+		return result, err
+	}
+}
+
+// joinArrays checks a builtin that takes any number of arrays, result builds its type from their elements.
+func joinArrays(result func(elem Type) Type) func(args []Type) (Type, string) {
+	return func(args []Type) (Type, string) {
+		var elem Type
+		for i := range args {
+			arr, err := arrayParam(args, i)
+			if err != "" {
+				return result(Any), err
+			}
+			elem = join(elem, arr.Elem)
+		}
+		if elem == nil {
+			elem = Any
+		}
+		return result(elem), ""
+	}
+}
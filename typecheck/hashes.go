@@ -0,0 +1,101 @@
+package typecheck
+
+import "fmt"
+
+var anyHash = &Hash{Key: Any, Value: Any}
+
+// the hash builtins of the evaluator, see evaluator/hashes.go.
+func init() {
+	for name, b := range map[string]builtin{
+		"keys": {
+			typ:   &Func{Params: []Type{anyHash}, Result: anyArray},
+			check: hashList(func(h *Hash) Type { return &Array{Elem: h.Key} }),
+		},
+		"values": {
+			typ:   &Func{Params: []Type{anyHash}, Result: anyArray},
+			check: hashList(func(h *Hash) Type { return &Array{Elem: h.Value} }),
+		},
+		"entries": {
+			typ:   &Func{Params: []Type{anyHash}, Result: &Array{Elem: anyArray}},
+			check: hashList(func(h *Hash) Type { return &Array{Elem: &Array{Elem: join(h.Key, h.Value)}} }),
+		},
+		"has": {
+			typ: &Func{Params: []Type{anyHash, Any}, Result: Bool},
+			check: func(args []Type) (Type, string) {
+				if len(args) != 2 {
+					return Bool, wrongArity(2, len(args))
+				}
+				h, err := hashParam(args, 0)
+				if err == "" {
+					err = keyParam(h, args, 1)
+				}
+				return Bool, err
+			},
+		},
+		"delete": {
+			typ: &Func{Params: []Type{anyHash, Any}, Result: anyHash, Variadic: true},
+			check: func(args []Type) (Type, string) {
+				if len(args) < 1 {
+					return anyHash, fmt.Sprintf("wrong number of arguments. got %d, want=at least 1", len(args))
+				}
+				h, err := hashParam(args, 0)
+				for i := 1; i < len(args) && err == ""; i++ {
+					err = keyParam(h, args, i)
+				}
+				return h, err
+			},
+		},
+		"merge": {
+			typ: &Func{Params: []Type{anyHash}, Result: anyHash, Variadic: true},
+			check: func(args []Type) (Type, string) {
+				var key, value Type
+				for i := range args {
+					h, err := hashParam(args, i)
+					if err != "" {
+						return anyHash, err
+					}
+					key, value = join(key, h.Key), join(value, h.Value)
+				}
+				if key == nil {
+					return anyHash, ""
+				}
+				return &Hash{Key: key, Value: value}, ""
+			},
+		},
+	} {
+		builtins[name] = b
+	}
+}
+
+// hashParam returns the argument at i as a hash, any is a hash of any.
+func hashParam(args []Type, i int) (*Hash, string) {
+	if h, ok := args[i].(*Hash); ok {
+		return h, ""
+	}
+	if args[i] != Any {
+		return anyHash, fmt.Sprintf("argument %d must be a hash, got %s", i+1, args[i])
+	}
+	return anyHash, ""
+}
+
+// keyParam checks that the argument at i can be a key of h.
+func keyParam(h *Hash, args []Type, i int) string {
+	if !hashable(args[i]) {
+		return "unusable as hash key: " + args[i].String()
+	}
+	if !AssignableTo(args[i], h.Key) {
+		return fmt.Sprintf("cannot use %s as %s in argument %d", args[i], h.Key, i+1)
+	}
+	return ""
+}
+
+// hashList checks keys, values and entries, which take a hash and return an array built by result.
+func hashList(result func(h *Hash) Type) func(args []Type) (Type, string) {
+	return func(args []Type) (Type, string) {
+		if len(args) != 1 {
+			return result(anyHash), wrongArity(1, len(args))
+		}
+		h, err := hashParam(args, 0)
+		return result(h), err
+	}
+}
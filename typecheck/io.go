@@ -0,0 +1,17 @@
+package typecheck
+
+// the io module of the evaluator, see evaluator/io.go. readLine returns null at the end of the input.
+func init() {
+	modules["io"] = &Module{Name: "io", members: map[string]builtin{
+		"readFile":  signature(String, 0, String),
+		"writeFile": signature(Null, 0, String, String),
+		"listDir":   signature(&Array{Elem: String}, 1, String),
+		"readLine":  signature(Any, 0),
+		"print": {
+			typ: &Func{Params: []Type{Any}, Result: Null, Variadic: true},
+			check: func(args []Type) (Type, string) {
+				return Null, ""
+			},
+		},
+	}}
+}
@@ -0,0 +1,9 @@
+package typecheck
+
+// the json module of the evaluator, see evaluator/json.go. The indent of stringify is an int or a string.
+func init() {
+	modules["json"] = &Module{Name: "json", members: map[string]builtin{
+		"parse":     signature(Any, 0, String),
+		"stringify": signature(String, 1, Any, Any),
+	}}
+}
@@ -0,0 +1,88 @@
+package typecheck
+
+import "fmt"
+
+// the math module of the evaluator, see evaluator/math.go.
+func init() {
+	extreme := builtin{
+		typ: &Func{Params: []Type{Any}, Result: Any, Variadic: true},
+		check: func(args []Type) (Type, string) {
+			if len(args) < 1 {
+				return Any, fmt.Sprintf("wrong number of arguments. got %d, want=at least 1", len(args))
+			}
+			return numbers(args)
+		},
+	}
+	// abs and clamp return one of their arguments, or its absolute value, so their type is the type of the arguments.
+	sameNumber := func(params int) builtin {
+		typ := &Func{Result: Any}
+		for i := 0; i < params; i++ {
+			typ.Params = append(typ.Params, Any)
+		}
+		return builtin{
+			typ: typ,
+			check: func(args []Type) (Type, string) {
+				if len(args) != params {
+					return Any, wrongArity(params, len(args))
+				}
+				return numbers(args)
+			},
+		}
+	}
+	pow := builtin{
+		typ: &Func{Params: []Type{Any, Int}, Result: Any},
+		check: func(args []Type) (Type, string) {
+			if len(args) != 2 {
+				return Any, wrongArity(2, len(args))
+			}
+			if !numeric(args[0]) && args[0] != Any {
+				return Any, "argument 1 must be a number, got " + args[0].String()
+			}
+			if !AssignableTo(args[1], Int) {
+				return args[0], "argument 2 must be int, got " + args[1].String()
+			}
+			return args[0], ""
+		},
+	}
+	// floor, ceil and round turn a decimal into an integer and leave integers as they are.
+	rounding := builtin{
+		typ: &Func{Params: []Type{Any}, Result: Int},
+		check: func(args []Type) (Type, string) {
+			if len(args) != 1 {
+				return Int, wrongArity(1, len(args))
+			}
+			if !numeric(args[0]) && args[0] != Any {
+				return Int, "argument 1 must be a number, got " + args[0].String()
+			}
+			if args[0] == BigInt {
+				return BigInt, ""
+			}
+			return Int, ""
+		},
+	}
+	modules["math"] = &Module{Name: "math", members: map[string]builtin{
+		"abs":    sameNumber(1),
+		"min":    extreme,
+		"max":    extreme,
+		"pow":    pow,
+		"sqrt":   signature(Int, 0, Int),
+		"floor":  rounding,
+		"ceil":   rounding,
+		"round":  rounding,
+		"div":    signature(Int, 0, Int, Int),
+		"clamp":  sameNumber(3),
+		"random": signature(Int, 1, Int, Int),
+	}}
+}
+
+// numbers checks that every argument is a number, the result is one of them so it has their type when they agree.
+func numbers(args []Type) (Type, string) {
+	var result Type
+	for i, a := range args {
+		if !numeric(a) && a != Any {
+			return Any, fmt.Sprintf("argument %d must be a number, got %s", i+1, a)
+		}
+		result = join(result, a)
+	}
+	return result, ""
+}
@@ -0,0 +1,30 @@
+package typecheck
+
+import "fmt"
+
+// the regex module of the evaluator, see evaluator/regex.go.
+func init() {
+	modules["regex"] = &Module{Name: "regex", members: map[string]builtin{
+		"compile":  signature(Regex, 0, String),
+		"match":    pattern(Bool, 0, String),
+		"find":     pattern(Any, 0, String),
+		"findAll":  pattern(&Array{Elem: String}, 1, String, Int),
+		"captures": pattern(Any, 0, String),
+		"replace":  pattern(String, 0, String, Any),
+		"split":    pattern(&Array{Elem: String}, 1, String, Int),
+	}}
+}
+
+// pattern is the signature of a function taking a regex or a string pattern followed by params.
+func pattern(result Type, optional int, params ...Type) builtin {
+	rest := signature(result, optional, append([]Type{Any}, params...)...)
+	return builtin{
+		typ: rest.typ,
+		check: func(args []Type) (Type, string) {
+			if len(args) > 0 && args[0] != Regex && !AssignableTo(args[0], String) {
+				return result, fmt.Sprintf("argument 1 must be regex or string, got %s", args[0])
+			}
+			return rest.check(args)
+		},
+	}
+}
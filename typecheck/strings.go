@@ -0,0 +1,67 @@
+package typecheck
+
+import "fmt"
+
+// the strings module of the evaluator, see evaluator/strings.go.
+func init() {
+	stringArray := &Array{Elem: String}
+	format := builtin{
+		typ: &Func{Params: []Type{String, Any}, Result: String, Variadic: true},
+		check: func(args []Type) (Type, string) {
+			if len(args) < 1 {
+				return String, fmt.Sprintf("wrong number of arguments. got %d, want=at least 1", len(args))
+			}
+			return String, argTypes(args[:1], String)
+		},
+	}
+	modules["strings"] = &Module{Name: "strings", members: map[string]builtin{
+		"split":      signature(stringArray, 0, String, String),
+		"join":       signature(String, 0, stringArray, String),
+		"trim":       signature(String, 1, String, String),
+		"trimLeft":   signature(String, 1, String, String),
+		"trimRight":  signature(String, 1, String, String),
+		"upper":      signature(String, 0, String),
+		"lower":      signature(String, 0, String),
+		"contains":   signature(Bool, 0, String, String),
+		"startsWith": signature(Bool, 0, String, String),
+		"endsWith":   signature(Bool, 0, String, String),
+		"indexOf":    signature(Int, 0, String, String),
+		"replace":    signature(String, 0, String, String, String),
+		"repeat":     signature(String, 0, String, Int),
+		"substring":  signature(String, 1, String, Int, Int),
+		"padLeft":    signature(String, 1, String, Int, String),
+		"padRight":   signature(String, 1, String, Int, String),
+		"chars":      signature(stringArray, 0, String),
+		"format":     format,
+		"sprintf":    format,
+	}}
+}
+
+// signature describes a builtin with fixed parameter types, the last optional ones can be left out.
+func signature(result Type, optional int, params ...Type) builtin {
+	return builtin{
+		typ: &Func{Params: params, Result: result, Optional: optional},
+		check: func(args []Type) (Type, string) {
+			min, max := len(params)-optional, len(params)
+			switch {
+			case len(args) >= min && len(args) <= max:
+				return result, argTypes(args, params...)
+			case min == max:
+				return result, wrongArity(min, len(args))
+			case min+1 == max:
+				return result, fmt.Sprintf("wrong number of arguments. got %d, want=%d or %d", len(args), min, max)
+			}
+			return result, fmt.Sprintf("wrong number of arguments. got %d, want=%d to %d", len(args), min, max)
+		},
+	}
+}
+
+// argTypes checks each argument against the parameter at the same position.
+func argTypes(args []Type, params ...Type) string {
+	for i, a := range args {
+		if !AssignableTo(a, params[i]) {
+			return fmt.Sprintf("argument %d must be %s, got %s", i+1, params[i], a)
+		}
+	}
+	return ""
+}
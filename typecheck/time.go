@@ -0,0 +1,48 @@
+package typecheck
+
+// the time module of the evaluator, see evaluator/time.go.
+func init() {
+	modules["time"] = &Module{Name: "time", members: map[string]builtin{
+		"now":           signature(Time, 0),
+		"since":         signature(Duration, 0, Time),
+		"parse":         signature(Time, 1, String, String, String),
+		"format":        signature(String, 0, Time, String),
+		"in":            signature(Time, 0, Time, String),
+		"parts":         signature(&Hash{Key: String, Value: Any}, 0, Time),
+		"unix":          signature(Int, 0, Time),
+		"unixMilli":     signature(Int, 0, Time),
+		"fromUnix":      signature(Time, 0, Int),
+		"fromUnixMilli": signature(Time, 0, Int),
+		"duration":      signature(Duration, 0, String),
+	}}
+}
+
+// temporal is the type of an operation on times and durations as evaluator/time.go applies it, nil when the
+// operands are not ones it takes.
+func temporal(left Type, op string, right Type) Type {
+	switch {
+	case left == Time && right == Time:
+		switch op {
+		case "-":
+			return Duration
+		case "<", ">":
+			return Bool
+		}
+	case left == Time && right == Duration && (op == "+" || op == "-"),
+		left == Duration && right == Time && op == "+":
+		return Time
+	case left == Duration && right == Duration:
+		switch op {
+		case "+", "-", "%":
+			return Duration
+		case "/":
+			return Int
+		case "<", ">":
+			return Bool
+		}
+	case left == Duration && right == Int && (op == "*" || op == "/"),
+		left == Int && right == Duration && op == "*":
+		return Duration
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package typecheck
+
+import (
+	"fmt"
+	"strings"
+
+	"go-interpreter-lexer/object"
+)
+
+// Type is the static type of an expression as worked out by the checker.
+type Type interface {
+	String() string
+	// Object is the object type values of this type have at runtime, empty for any.
+	Object() object.ObjectType
+}
+
+// Basic is one of the types that are referred to by name in annotations.
+type Basic struct {
+	Name string
+	obj  object.ObjectType
+}
+
+func (b *Basic) String() string            { return b.Name }
+func (b *Basic) Object() object.ObjectType { return b.obj }
+
+var (
+	Int      = &Basic{Name: "int", obj: object.INTEGER_OBJ}
+	String   = &Basic{Name: "string", obj: object.STRING_OBJ}
+	Bool     = &Basic{Name: "bool", obj: object.BOOLEAN_OBJ}
+	Null     = &Basic{Name: "null", obj: object.NULL_OBJ}
+	BigInt   = &Basic{Name: "bigint", obj: object.BIGINT_OBJ}
+	Decimal  = &Basic{Name: "decimal", obj: object.DECIMAL_OBJ}
+	Regex    = &Basic{Name: "regex", obj: object.REGEX_OBJ}
+	Time     = &Basic{Name: "time", obj: object.TIME_OBJ}
+	Duration = &Basic{Name: "duration", obj: object.DURATION_OBJ}
+	// Any is the type of everything the checker knows nothing about, unannotated parameters for instance.
+	// It is compatible with every other type.
+	Any = &Basic{Name: "any"}
+)
+
+var basics = map[string]*Basic{
+	"int":      Int,
+	"string":   String,
+	"bool":     Bool,
+	"null":     Null,
+	"bigint":   BigInt,
+	"decimal":  Decimal,
+	"regex":    Regex,
+	"time":     Time,
+	"duration": Duration,
+	"any":      Any,
+}
+
+type Array struct {
+	Elem Type
+}
+
+func (a *Array) String() string            { return "[" + a.Elem.String() + "]" }
+func (a *Array) Object() object.ObjectType { return object.ARRAY_OBJ }
+
+type Hash struct {
+	Key   Type
+	Value Type
+}
+
+func (h *Hash) String() string            { return "{" + h.Key.String() + ": " + h.Value.String() + "}" }
+func (h *Hash) Object() object.ObjectType { return object.HASH_OBJ }
+
+/*
+Func is the type of a function, Variadic functions accept any number of arguments of the last parameter
+type. Optional is the number of parameters before the variadic one that have a default value, they are
+always the last ones.
+*/
+type Func struct {
+	Params   []Type
+	Result   Type
+	Variadic bool
+	Optional int
+}
+
+// minArgs is the number of arguments a call has to pass.
+func (f *Func) minArgs() int {
+	n := len(f.Params) - f.Optional
+	if f.Variadic {
+		n--
+	}
+	return n
+}
+
+// arity describes the number of arguments a call can pass in the words of the evaluator's errors.
+func (f *Func) arity() string {
+	switch {
+	case f.Variadic:
+		return fmt.Sprintf("at least %d", f.minArgs())
+	case f.Optional == 0:
+		return fmt.Sprintf("%d", len(f.Params))
+	}
+	return fmt.Sprintf("%d to %d", f.minArgs(), len(f.Params))
+}
+
+func (f *Func) String() string {
+	params := []string{}
+	for i, p := range f.Params {
+		s := p.String()
+		if f.Variadic && i == len(f.Params)-1 {
+			s = "..." + s
+		} else if i >= f.minArgs() {
+			s += "?"
+		}
+		params = append(params, s)
+	}
+	return "fn(" + strings.Join(params, ", ") + "): " + f.Result.String()
+}
+func (f *Func) Object() object.ObjectType { return object.FUNCTION_OBJ }
+
+// Module is the type of a builtin module of the evaluator, its members are checked like the builtins.
+type Module struct {
+	Name    string
+	members map[string]builtin
+}
+
+func (m *Module) String() string            { return "module " + m.Name }
+func (m *Module) Object() object.ObjectType { return object.MODULE_OBJ }
+
+// Identical reports whether two types are the same.
+func Identical(a, b Type) bool {
+	switch a := a.(type) {
+	case *Basic, *Module:
+		return a == b
+	case *Array:
+		b, ok := b.(*Array)
+		return ok && Identical(a.Elem, b.Elem)
+	case *Hash:
+		b, ok := b.(*Hash)
+		return ok && Identical(a.Key, b.Key) && Identical(a.Value, b.Value)
+	case *Func:
+		b, ok := b.(*Func)
+		if !ok || len(a.Params) != len(b.Params) || a.Variadic != b.Variadic || a.Optional != b.Optional || !Identical(a.Result, b.Result) {
+			return false
+		}
+		for i := range a.Params {
+			if !Identical(a.Params[i], b.Params[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// AssignableTo reports whether a value of type from can be used where a value of type to is expected.
+func AssignableTo(from, to Type) bool {
+	if from == Any || to == Any {
+		return true
+	}
+	switch t := to.(type) {
+	case *Basic, *Module:
+		return from == t
+	case *Array:
+		f, ok := from.(*Array)
+		return ok && AssignableTo(f.Elem, t.Elem)
+	case *Hash:
+		f, ok := from.(*Hash)
+		return ok && AssignableTo(f.Key, t.Key) && AssignableTo(f.Value, t.Value)
+	case *Func:
+		f, ok := from.(*Func)
+		if !ok || len(f.Params) != len(t.Params) || !AssignableTo(f.Result, t.Result) {
+			return false
+		}
+		for i := range t.Params {
+			if !AssignableTo(t.Params[i], f.Params[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// join is the type of a value that is either a or b.
+func join(a, b Type) Type {
+	if a == nil {
+		return b
+	}
+	if Identical(a, b) {
+		return a
+	}
+	return Any
+}
+
+func hashable(t Type) bool {
+	return t == Any || t == String || t == Bool || numeric(t)
+}
+
+func numeric(t Type) bool {
+	return t == Int || t == BigInt || t == Decimal
+}
+
+// arithmetic is the type of an operation on two numbers, a decimal operand makes it a decimal and a big
+// integer a big integer. Integers that overflow become big integers when the program runs, which the checker
+// does not follow.
+func arithmetic(a, b Type) Type {
+	switch {
+	case a == Decimal || b == Decimal:
+		return Decimal
+	case a == BigInt || b == BigInt:
+		return BigInt
+	}
+	return Int
+}